@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/cassette"
 	"github.com/microbus-io/fabric/connector"
 	"github.com/microbus-io/fabric/env"
 	"github.com/microbus-io/fabric/service"
@@ -222,7 +223,8 @@ func envDurationOrDefault(name string, def time.Duration) time.Duration {
 // RunInTest starts up all microservices included in this app, waits for the test to finish, then shuts them down.
 // A random plane of communication is used to isolate the testing app from other apps.
 // Errors in startup or shutdown will fail the test.
-func (app *Application) RunInTest(t testing.TB) error {
+// Options such as [Replay] may be used to replay recorded traffic.
+func (app *Application) RunInTest(t testing.TB, options ...TestOption) error {
 	app.plane = utils.RandomIdentifier(12)
 	app.deployment = connector.TESTING
 	for _, g := range app.groups {
@@ -233,6 +235,34 @@ func (app *Application) RunInTest(t testing.TB) error {
 	}
 
 	assert := testarossa.For(t)
+	var opts testOptions
+	for _, opt := range options {
+		if !assert.NoError(opt(&opts)) {
+			t.FailNow()
+		}
+	}
+
+	// Serve outbound requests from the cassette and prepare to re-drive the inbound ones
+	var replayer *connector.Connector
+	var hostnames []string
+	if opts.cassette != nil {
+		seen := map[string]bool{}
+		for _, g := range app.groups {
+			for _, s := range g {
+				if svc, ok := s.(cassette.Interceptable); ok {
+					if !assert.NoError(opts.cassette.Attach(svc)) {
+						t.FailNow()
+					}
+				}
+				if !seen[s.Hostname()] {
+					seen[s.Hostname()] = true
+					hostnames = append(hostnames, s.Hostname())
+				}
+			}
+		}
+		replayer = connector.New("replayer.application")
+		app.Add(replayer)
+	}
 	t.Cleanup(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*8)
 		shutdownErr := errors.CatchPanic(func() error {
@@ -249,5 +279,41 @@ func (app *Application) RunInTest(t testing.TB) error {
 	if !assert.NoError(startupErr) {
 		t.FailNow()
 	}
+
+	// Re-drive the recorded inbound requests
+	for _, hostname := range hostnames {
+		for _, in := range opts.cassette.Inbound(hostname) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+			err := in.Redrive(ctx, replayer)
+			cancel()
+			assert.NoError(err, "%s %s", in.Method, in.URL)
+		}
+	}
+	return nil
+}
+
+/*
+Record records the traffic of the microservices included in this app into the cassette.
+Microservices added to the app after the call are not recorded.
+It must be called before the app is started.
+
+	rec, err := cassette.Create("traffic.jsonl")
+	app.Record(rec)
+	app.Run()
+	rec.Close()
+*/
+func (app *Application) Record(rec *cassette.Recorder) error {
+	app.mux.Lock()
+	defer app.mux.Unlock()
+	for _, g := range app.groups {
+		for _, s := range g {
+			if svc, ok := s.(cassette.Interceptable); ok {
+				err := rec.Attach(svc)
+				if err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
+	}
 	return nil
 }
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/cassette"
 	"github.com/microbus-io/fabric/connector"
 	"github.com/microbus-io/fabric/coreservices/configurator"
 	"github.com/microbus-io/fabric/env"
@@ -324,3 +325,74 @@ func TestApplication_Run(t *testing.T) {
 	assert.False(con.IsStarted())
 	assert.False(config.IsStarted())
 }
+
+func TestApplication_RecordAndReplay(t *testing.T) {
+	// No parallel - Setting envars
+	assert := testarossa.For(t)
+	ctx := t.Context()
+
+	env.Push("MICROBUS_PLANE", utils.RandomIdentifier(12))
+	env.Push("MICROBUS_DEPLOYMENT", connector.TESTING)
+	defer env.Pop("MICROBUS_PLANE")
+	defer env.Pop("MICROBUS_DEPLOYMENT")
+
+	newEcho := func() *connector.Connector {
+		echo := connector.New("echo.record.replay.application")
+		echo.Subscribe("Echo",
+			func(w http.ResponseWriter, r *http.Request) error {
+				res, err := echo.Request(r.Context(), pub.GET("https://upstream.record.replay.application/word"))
+				if err != nil {
+					return errors.Trace(err)
+				}
+				_, err = io.Copy(w, res.Body)
+				return errors.Trace(err)
+			},
+			sub.At("GET", "/echo"),
+			sub.Web(),
+		)
+		return echo
+	}
+	upstream := connector.New("upstream.record.replay.application")
+	upstream.Subscribe("Word",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Write([]byte("recorded"))
+			return nil
+		},
+		sub.At("GET", "/word"),
+		sub.Web(),
+	)
+	client := connector.New("client.record.replay.application")
+
+	// Record
+	path := t.TempDir() + "/traffic.jsonl"
+	rec, err := cassette.Create(path)
+	assert.NoError(err)
+	app := New()
+	app.Add(newEcho())
+	err = app.Record(rec)
+	assert.NoError(err)
+	app.Add(upstream, client)
+	err = app.Startup(ctx)
+	assert.NoError(err)
+	res, err := client.Request(ctx, pub.GET("https://echo.record.replay.application/echo"))
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal("recorded", string(body))
+	}
+	err = app.Shutdown(ctx)
+	assert.NoError(err)
+	assert.NoError(rec.Err())
+	assert.NoError(rec.Close())
+
+	cas, err := cassette.Load(path)
+	if assert.NoError(err) {
+		assert.Len(cas.Interactions, 2)
+	}
+
+	// Replay without the upstream microservice
+	t.Run("replay", func(t *testing.T) {
+		app := New()
+		app.Add(newEcho())
+		app.RunInTest(t, Replay(path))
+	})
+}
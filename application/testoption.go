@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/cassette"
+)
+
+// TestOption is an option of [Application.RunInTest].
+type TestOption func(opts *testOptions) error
+
+// testOptions are the options collected from the [TestOption]s.
+type testOptions struct {
+	cassette *cassette.Cassette
+}
+
+/*
+Replay replays the traffic recorded in the JSONL cassette at the path.
+The outbound requests of the microservices of the app are served the recorded responses, see [cassette.Cassette.Attach].
+Once the app is started, the recorded inbound requests of its microservices are re-driven in order of recording,
+failing the test if their responses differ from the recorded ones.
+
+	app := application.New()
+	app.Add(svc)
+	app.RunInTest(t, application.Replay("testdata/traffic.jsonl"))
+*/
+func Replay(path string) TestOption {
+	return func(opts *testOptions) error {
+		cas, err := cassette.Load(path)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		opts.cassette = cas
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"os"
+	"sync"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// Cassette is a recording of the traffic of microservices that can be replayed in tests.
+type Cassette struct {
+	Interactions []*Interaction
+
+	outbound map[string][]*Interaction
+	played   map[string]int
+	mux      sync.Mutex
+}
+
+// Load reads a cassette from a JSONL file.
+func Load(path string) (*Cassette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	cas, err := Read(f)
+	return cas, errors.Trace(err)
+}
+
+// Read reads a cassette from a JSONL stream, one [Interaction] per line.
+// Empty lines are ignored.
+func Read(r io.Reader) (*Cassette, error) {
+	cas := &Cassette{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 256<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var in Interaction
+		err := json.Unmarshal(b, &in)
+		if err != nil {
+			return nil, errors.New("invalid interaction on line %d", line, err)
+		}
+		if in.Direction != Inbound && in.Direction != Outbound {
+			return nil, errors.New("invalid direction '%s' on line %d", in.Direction, line)
+		}
+		cas.Interactions = append(cas.Interactions, &in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cas, nil
+}
+
+// Inbound returns the recorded inbound interactions of the microservice, in order of recording.
+func (cas *Cassette) Inbound(hostname string) []*Interaction {
+	var result []*Interaction
+	for _, in := range cas.Interactions {
+		if in.Direction == Inbound && in.Hostname == hostname {
+			result = append(result, in)
+		}
+	}
+	return result
+}
+
+/*
+Attach serves the outbound requests of the microservice from the responses recorded in the cassette,
+without sending them over the bus. A request matches a recording of the same microservice if its method, URL and body are identical.
+Identical requests that were recorded more than once are served the recorded responses in order of recording,
+the last one repeating once all are played. Requests that were not recorded are sent over the bus as usual.
+Attach must be called before the microservice is started.
+*/
+func (cas *Cassette) Attach(svc Interceptable) error {
+	hostname := svc.Hostname()
+	err := svc.AddClientInterceptor(func(next connector.ClientHandler) connector.ClientHandler {
+		return func(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+			if isControl(req.URL) {
+				return next(ctx, req)
+			}
+			probe, err := newOutbound(hostname, req)
+			if err != nil {
+				return pub.NewSoloResponseQueue(pub.NewErrorResponse(errors.Trace(err)))
+			}
+			recorded := cas.play(probe.key())
+			if recorded == nil {
+				return next(ctx, req)
+			}
+			return func(yield func(*pub.Response) bool) {
+				for _, res := range recorded.Responses {
+					if !yield(res.pubResponse()) {
+						return
+					}
+				}
+			}
+		}
+	})
+	return errors.Trace(err)
+}
+
+// play returns the next recorded outbound interaction matching the key, or nil if none.
+func (cas *Cassette) play(key string) *Interaction {
+	cas.mux.Lock()
+	defer cas.mux.Unlock()
+	if cas.outbound == nil {
+		cas.outbound = map[string][]*Interaction{}
+		cas.played = map[string]int{}
+		for _, in := range cas.Interactions {
+			if in.Direction == Outbound {
+				k := in.key()
+				cas.outbound[k] = append(cas.outbound[k], in)
+			}
+		}
+	}
+	recorded := cas.outbound[key]
+	if len(recorded) == 0 {
+		return nil
+	}
+	i := min(cas.played[key], len(recorded)-1)
+	cas.played[key]++
+	return recorded[i]
+}
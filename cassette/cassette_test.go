@@ -0,0 +1,228 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/utils"
+	"github.com/microbus-io/testarossa"
+)
+
+// newFrontend creates a microservice that calls the backend.
+func newFrontend(plane string) *connector.Connector {
+	frontend := connector.New("frontend.cassette")
+	frontend.SetPlane(plane)
+	frontend.Subscribe("Greet",
+		func(w http.ResponseWriter, r *http.Request) error {
+			res, err := frontend.Request(r.Context(), pub.GET("https://backend.cassette/double?x="+r.URL.Query().Get("x")))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			var doubled int
+			err = json.NewDecoder(res.Body).Decode(&doubled)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			var actor struct {
+				Sub string `json:"sub"`
+			}
+			frame.Of(r).ParseActor(&actor)
+			w.Header().Set("Content-Type", "application/json")
+			return json.NewEncoder(w).Encode(map[string]any{
+				"greeting": "Hello " + actor.Sub,
+				"doubled":  doubled,
+			})
+		},
+		sub.At("POST", "/greet"),
+		sub.Web(),
+	)
+	return frontend
+}
+
+func TestCassette_RecordAndReplay(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+	plane := utils.RandomIdentifier(12)
+
+	var backendCalls atomic.Int32
+	backend := connector.New("backend.cassette")
+	backend.SetPlane(plane)
+	backend.Subscribe("Double",
+		func(w http.ResponseWriter, r *http.Request) error {
+			backendCalls.Add(1)
+			x, err := strconv.Atoi(r.URL.Query().Get("x"))
+			if err != nil {
+				return errors.New("bad x", http.StatusBadRequest)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			return json.NewEncoder(w).Encode(x * 2)
+		},
+		sub.At("GET", "/double"),
+		sub.Web(),
+	)
+	frontend := newFrontend(plane)
+	tester := connector.New("tester.cassette")
+	tester.SetPlane(plane)
+
+	// Record the traffic of the frontend
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	rec.SetRedactor(func(in *Interaction) {
+		in.Header.Del("X-Api-Key")
+	})
+	err := rec.Attach(frontend)
+	assert.NoError(err)
+
+	for _, s := range []*connector.Connector{backend, frontend, tester} {
+		err = s.Startup(ctx)
+		assert.NoError(err)
+		defer s.Shutdown(ctx)
+	}
+
+	greet := func(tester *connector.Connector, x string) (string, error) {
+		res, err := tester.Request(ctx,
+			pub.POST("https://frontend.cassette/greet?x="+x),
+			pub.Actor(map[string]any{"sub": "harry", "exp": 1}),
+			pub.Header("Authorization", "Bearer secret"),
+			pub.Header("X-Api-Key", "secret"),
+			pub.Body([]byte{0xff, 0x00}),
+		)
+		if err != nil {
+			return "", err
+		}
+		b, _ := io.ReadAll(res.Body)
+		return strings.TrimSpace(string(b)), nil
+	}
+	body, err := greet(tester, "5")
+	assert.Expect(body, `{"doubled":10,"greeting":"Hello harry"}`, err, nil)
+	_, err = greet(tester, "x")
+	assert.Equal(http.StatusBadRequest, errors.StatusCode(err))
+	_, err = tester.Request(ctx, pub.GET("https://frontend.cassette:888/ping"))
+	assert.NoError(err)
+	assert.Equal(int32(2), backendCalls.Load())
+	assert.NoError(rec.Err())
+
+	// Outbound interactions are written before the inbound ones that made them
+	cas, err := Read(&buf)
+	if !assert.NoError(err) || !assert.Len(cas.Interactions, 4) {
+		return
+	}
+	out, in := cas.Interactions[0], cas.Interactions[1]
+	assert.Expect(
+		out.Direction, Outbound,
+		out.Hostname, "frontend.cassette",
+		out.Method, "GET",
+		out.URL, "https://backend.cassette:443/double?x=5",
+		len(out.Responses), 1,
+		out.Responses[0].StatusCode, http.StatusOK,
+		strings.TrimSpace(out.Responses[0].Body), "10",
+	)
+	assert.Expect(
+		in.Direction, Inbound,
+		in.Hostname, "frontend.cassette",
+		in.Method, "POST",
+		in.RequestBody(), []byte{0xff, 0x00},
+		in.Base64, true,
+		in.Actor, map[string]any{"sub": "harry"},
+		in.Header.Get(frame.HeaderActor), "",
+		in.Header.Get("Authorization"), "",
+		in.Header.Get("X-Api-Key"), "",
+		in.Responses[0].StatusCode, http.StatusOK,
+	)
+	assert.Equal(http.StatusBadRequest, cas.Interactions[2].Responses[0].StatusCode)
+	assert.Equal("bad x", cas.Interactions[2].Responses[0].Error)
+	assert.Equal(http.StatusBadRequest, cas.Interactions[3].Responses[0].StatusCode)
+	assert.Len(cas.Inbound("frontend.cassette"), 2)
+	assert.Len(cas.Inbound("backend.cassette"), 0)
+
+	// Replay on a plane without the backend
+	replayPlane := utils.RandomIdentifier(12)
+	replayed := newFrontend(replayPlane)
+	err = cas.Attach(replayed)
+	assert.NoError(err)
+	replayTester := connector.New("tester.cassette")
+	replayTester.SetPlane(replayPlane)
+	for _, s := range []*connector.Connector{replayed, replayTester} {
+		err = s.Startup(ctx)
+		assert.NoError(err)
+		defer s.Shutdown(ctx)
+	}
+	for _, in := range cas.Inbound("frontend.cassette") {
+		err = in.Redrive(ctx, replayTester)
+		assert.NoError(err)
+	}
+	body, err = greet(replayTester, "5")
+	assert.Expect(body, `{"doubled":10,"greeting":"Hello harry"}`, err, nil)
+	assert.Equal(int32(2), backendCalls.Load())
+
+	// Unrecorded requests are sent over the bus
+	_, err = greet(replayTester, "6")
+	assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+
+	// Mismatching responses fail the re-drive
+	in.Responses[0].Body = `{"doubled":12,"greeting":"Hello harry"}`
+	err = in.Redrive(ctx, replayTester)
+	assert.Error(err)
+	in.Responses[0].Body = `{ "greeting": "Hello harry", "doubled": 10 }`
+	err = in.Redrive(ctx, replayTester)
+	assert.NoError(err)
+	in.Responses[0].StatusCode = http.StatusCreated
+	err = in.Redrive(ctx, replayTester)
+	assert.Error(err)
+	err = cas.Interactions[3].Redrive(ctx, replayTester)
+	assert.NoError(err)
+	err = out.Redrive(ctx, replayTester)
+	assert.Error(err)
+}
+
+func TestCassette_Read(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	cas, err := Read(strings.NewReader(`
+{"direction":"outbound","hostname":"a.example","method":"GET","url":"https://b.example/x","responses":[{"body":"1"}]}
+
+{"direction":"outbound","hostname":"a.example","method":"GET","url":"https://b.example/x","responses":[{"body":"2"}]}
+`))
+	if assert.NoError(err) && assert.Len(cas.Interactions, 2) {
+		key := cas.Interactions[0].key()
+		assert.Equal("1", cas.play(key).Responses[0].Body)
+		assert.Equal("2", cas.play(key).Responses[0].Body)
+		assert.Equal("2", cas.play(key).Responses[0].Body)
+		assert.Nil(cas.play("unknown"))
+	}
+
+	_, err = Read(strings.NewReader(`{"direction":"sideways"}`))
+	assert.Error(err)
+	_, err = Read(strings.NewReader(`{not json}`))
+	assert.Error(err)
+}
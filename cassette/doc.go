@@ -0,0 +1,26 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package cassette captures the traffic of microservices into a cassette and replays it in tests.
+
+A [Recorder] attached to a microservice writes the requests it receives and makes, along with their responses,
+to a JSONL cassette, one [Interaction] per line. A [Cassette] loaded from the file serves the recorded responses
+to the outbound requests of the microservices it is attached to, and re-drives the recorded inbound requests
+to verify that the microservices still respond the same. Together they turn production-like traffic
+into deterministic regression tests.
+*/
+package cassette
@@ -0,0 +1,219 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/service"
+)
+
+// Directions of interactions.
+const (
+	Inbound  = "inbound"
+	Outbound = "outbound"
+)
+
+// Interaction is a request received or made by a microservice, along with its responses.
+type Interaction struct {
+	Direction string         `json:"direction"`
+	Hostname  string         `json:"hostname"`
+	Time      time.Time      `json:"time,omitzero"`
+	Method    string         `json:"method"`
+	URL       string         `json:"url"`
+	Header    http.Header    `json:"header,omitzero"`
+	Actor     map[string]any `json:"actor,omitzero"`
+	Body      string         `json:"body,omitzero"`
+	Base64    bool           `json:"base64,omitzero"`
+	Responses []*Response    `json:"responses,omitzero"`
+}
+
+// Response is a recorded response to a request.
+// A response that is an error records the status code and message of the error.
+type Response struct {
+	StatusCode int         `json:"statusCode,omitzero"`
+	Header     http.Header `json:"header,omitzero"`
+	Body       string      `json:"body,omitzero"`
+	Base64     bool        `json:"base64,omitzero"`
+	Error      string      `json:"error,omitzero"`
+}
+
+// encodeBody encodes the body as a string, falling back to base64 for binary content.
+func encodeBody(body []byte) (encoded string, b64 bool) {
+	if utf8.Valid(body) {
+		return string(body), false
+	}
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+// decodeBody decodes a body encoded by encodeBody.
+func decodeBody(encoded string, b64 bool) []byte {
+	if !b64 {
+		return []byte(encoded)
+	}
+	body, _ := base64.StdEncoding.DecodeString(encoded)
+	return body
+}
+
+// RequestBody returns the body of the request.
+func (in *Interaction) RequestBody() []byte {
+	return decodeBody(in.Body, in.Base64)
+}
+
+// ResponseBody returns the body of the response.
+func (res *Response) ResponseBody() []byte {
+	return decodeBody(res.Body, res.Base64)
+}
+
+// key identifies requests that are expected to receive the same responses.
+func (in *Interaction) key() string {
+	return strings.Join([]string{in.Hostname, in.Method, in.URL, in.Body}, " ")
+}
+
+// pubResponse reconstitutes the recorded response as it is returned to the publisher.
+func (res *Response) pubResponse() *pub.Response {
+	if res.Error != "" {
+		statusCode := res.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusInternalServerError
+		}
+		return pub.NewErrorResponse(errors.New(res.Error, statusCode))
+	}
+	body := res.ResponseBody()
+	httpRes := &http.Response{
+		Status:        http.StatusText(res.StatusCode),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        res.Header.Clone(),
+		Body:          httpx.NewBodyReader(body),
+		ContentLength: int64(len(body)),
+	}
+	if httpRes.StatusCode == 0 {
+		httpRes.StatusCode = http.StatusOK
+	}
+	if httpRes.Header == nil {
+		httpRes.Header = http.Header{}
+	}
+	return pub.NewHTTPResponse(httpRes)
+}
+
+// replayedHeader returns the headers of the recorded request that are sent when it is re-driven.
+// Control headers other than baggage are set anew by the connector.
+func (in *Interaction) replayedHeader() http.Header {
+	h := http.Header{}
+	for name, values := range in.Header {
+		switch {
+		case strings.HasPrefix(name, frame.HeaderBaggagePrefix):
+		case strings.HasPrefix(name, frame.HeaderPrefix),
+			name == "Content-Length", name == "Traceparent", name == "Tracestate", name == "Connection":
+			continue
+		}
+		h[name] = values
+	}
+	return h
+}
+
+/*
+Redrive sends the recorded inbound request again via the publisher and compares the response to the recorded one.
+An error is returned if the status code or the body of the response differ.
+JSON bodies are compared by their value rather than by their formatting.
+*/
+func (in *Interaction) Redrive(ctx context.Context, publisher service.Publisher) error {
+	if in.Direction != Inbound {
+		return errors.New("not an inbound interaction")
+	}
+	options := []pub.Option{
+		pub.Method(in.Method),
+		pub.URL(in.URL),
+		pub.CopyHeaders(in.replayedHeader()),
+		pub.Unicast(),
+	}
+	if body := in.RequestBody(); len(body) > 0 {
+		options = append(options, pub.Body(body))
+	}
+	if in.Actor != nil {
+		options = append(options, pub.Actor(in.Actor))
+	}
+	res, err := publisher.Request(ctx, options...)
+	if len(in.Responses) == 0 {
+		return errors.Trace(err)
+	}
+	expected := in.Responses[0]
+	if expected.Error != "" {
+		expectedCode := expected.StatusCode
+		if expectedCode == 0 {
+			expectedCode = http.StatusInternalServerError
+		}
+		if err == nil {
+			return errors.New("expected error '%s' of %s %s", expected.Error, in.Method, in.URL)
+		}
+		if errors.StatusCode(err) != expectedCode {
+			return errors.New("expected status code %d of %s %s, got %d", expectedCode, in.Method, in.URL, errors.StatusCode(err))
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	expectedCode := expected.StatusCode
+	if expectedCode == 0 {
+		expectedCode = http.StatusOK
+	}
+	if res.StatusCode != expectedCode {
+		return errors.New("expected status code %d of %s %s, got %d", expectedCode, in.Method, in.URL, res.StatusCode)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !sameBody(expected.ResponseBody(), body, res.Header.Get("Content-Type")) {
+		return errors.New("expected body '%s' of %s %s, got '%s'", expected.Body, in.Method, in.URL, string(body))
+	}
+	return nil
+}
+
+// sameBody compares two bodies, by value if they are JSON.
+func sameBody(expected []byte, actual []byte, contentType string) bool {
+	if bytes.Equal(expected, actual) {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return false
+	}
+	var x, y any
+	if json.Unmarshal(expected, &x) != nil || json.Unmarshal(actual, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
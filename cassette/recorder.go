@@ -0,0 +1,321 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// Interceptable is a microservice whose inbound and outbound requests can be intercepted.
+type Interceptable interface {
+	Hostname() string
+	AddClientInterceptor(interceptor connector.ClientInterceptor) error
+	AddServerInterceptor(interceptor connector.ServerInterceptor) error
+}
+
+// credentialHeaders are the headers that are not recorded because they carry credentials.
+var credentialHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// timelessClaims are the claims of the actor that are not recorded because they would expire the recording.
+var timelessClaims = []string{"exp", "iat", "nbf"}
+
+// Recorder captures the inbound and outbound requests of microservices, along with their responses, into a JSONL cassette.
+// Requests to the control port 888 are not recorded.
+// The token of the actor is not recorded, only its claims.
+// The Authorization, Cookie and Set-Cookie headers are not recorded.
+type Recorder struct {
+	w      io.Writer
+	closer io.Closer
+	mux    sync.Mutex
+	err    error
+	redact func(in *Interaction)
+}
+
+// NewRecorder creates a recorder that writes to the writer.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Create creates a recorder that writes to a new file at the path, truncating it if it exists.
+// The recorder must be closed when recording is done.
+func Create(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Recorder{w: f, closer: f}, nil
+}
+
+// Close closes the file that the recorder writes to, if it was created with [Create].
+func (rec *Recorder) Close() error {
+	rec.mux.Lock()
+	defer rec.mux.Unlock()
+	if rec.closer == nil {
+		return nil
+	}
+	err := rec.closer.Close()
+	rec.closer = nil
+	return errors.Trace(err)
+}
+
+// Err returns the first error encountered writing to the cassette, if any.
+func (rec *Recorder) Err() error {
+	rec.mux.Lock()
+	defer rec.mux.Unlock()
+	return rec.err
+}
+
+// SetRedactor sets a function that is called with each interaction before it is written to the cassette.
+// It may modify the interaction to remove sensitive data, such as secrets in headers, URLs or bodies.
+func (rec *Recorder) SetRedactor(redact func(in *Interaction)) {
+	rec.mux.Lock()
+	defer rec.mux.Unlock()
+	rec.redact = redact
+}
+
+// Attach records the inbound and outbound requests of the microservice.
+// It must be called before the microservice is started.
+func (rec *Recorder) Attach(svc Interceptable) error {
+	hostname := svc.Hostname()
+	err := svc.AddClientInterceptor(func(next connector.ClientHandler) connector.ClientHandler {
+		return func(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+			if isControl(req.URL) {
+				return next(ctx, req)
+			}
+			in, err := newOutbound(hostname, req)
+			if err != nil {
+				return pub.NewSoloResponseQueue(pub.NewErrorResponse(errors.Trace(err)))
+			}
+			queue := next(ctx, req)
+			return func(yield func(*pub.Response) bool) {
+				defer rec.write(in)
+				for r := range queue {
+					res, err := r.Get()
+					recorded, readErr := recordResponse(res, err)
+					if readErr != nil {
+						r = pub.NewErrorResponse(errors.Trace(readErr))
+					}
+					in.Responses = append(in.Responses, recorded)
+					if !yield(r) {
+						return
+					}
+				}
+			}
+		}
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = svc.AddServerInterceptor(func(s *sub.Subscription, next connector.HTTPHandler) connector.HTTPHandler {
+		if s.Port == "888" {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) error {
+			in, err := newInbound(hostname, r)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			tw := &teeWriter{ResponseWriter: w}
+			err = next(tw, r)
+			res := &Response{
+				StatusCode: tw.statusCode,
+				Header:     tw.Header().Clone(),
+			}
+			if err != nil {
+				res.StatusCode = errors.StatusCode(err)
+				res.Header = nil
+				res.Error = err.Error()
+			} else {
+				if res.StatusCode == 0 {
+					res.StatusCode = http.StatusOK
+				}
+				res.Body, res.Base64 = encodeBody(tw.body.Bytes())
+			}
+			in.Responses = []*Response{res}
+			rec.write(in)
+			return err // No trace
+		}
+	})
+	return errors.Trace(err)
+}
+
+// newOutbound creates an interaction from an outbound request, buffering its body.
+func newOutbound(hostname string, req *pub.Request) (*Interaction, error) {
+	in := &Interaction{
+		Direction: Outbound,
+		Hostname:  hostname,
+		Time:      time.Now().UTC(),
+		Method:    req.Method,
+		URL:       req.URL,
+		Header:    req.Header.Clone(),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		var body []byte
+		if br, ok := req.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			req.Body = httpx.NewBodyReader(body)
+		}
+		in.Body, in.Base64 = encodeBody(body)
+	}
+	recordActor(in, req.Header)
+	return in, nil
+}
+
+// newInbound creates an interaction from an inbound request, buffering its body.
+func newInbound(hostname string, r *http.Request) (*Interaction, error) {
+	in := &Interaction{
+		Direction: Inbound,
+		Hostname:  hostname,
+		Time:      time.Now().UTC(),
+		Method:    r.Method,
+		URL:       r.URL.String(),
+		Header:    r.Header.Clone(),
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		r.Body = httpx.NewBodyReader(body)
+		in.Body, in.Base64 = encodeBody(body)
+	}
+	recordActor(in, r.Header)
+	return in, nil
+}
+
+// recordActor records the claims of the actor of the request in place of its token.
+func recordActor(in *Interaction, h http.Header) {
+	in.Header.Del(frame.HeaderActor)
+	var claims map[string]any
+	if ok, _ := frame.Of(h).ParseActor(&claims); ok {
+		for _, c := range timelessClaims {
+			delete(claims, c)
+		}
+		in.Actor = claims
+	}
+}
+
+// recordResponse creates a recorded response from a response or error received by the publisher, buffering its body.
+func recordResponse(res *http.Response, err error) (*Response, error) {
+	if err != nil {
+		return &Response{
+			StatusCode: errors.StatusCode(err),
+			Error:      err.Error(),
+		}, nil
+	}
+	recorded := &Response{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+	}
+	if res.Body != nil {
+		var body []byte
+		if br, ok := res.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			body, err = io.ReadAll(res.Body)
+			if err != nil {
+				return recorded, errors.Trace(err)
+			}
+			res.Body = httpx.NewBodyReader(body)
+		}
+		recorded.Body, recorded.Base64 = encodeBody(body)
+	}
+	return recorded, nil
+}
+
+// write redacts and appends the interaction to the cassette.
+func (rec *Recorder) write(in *Interaction) {
+	for _, name := range credentialHeaders {
+		in.Header.Del(name)
+		for _, res := range in.Responses {
+			res.Header.Del(name)
+		}
+	}
+	rec.mux.Lock()
+	defer rec.mux.Unlock()
+	if rec.redact != nil {
+		rec.redact(in)
+	}
+	b, err := json.Marshal(in)
+	if err == nil {
+		_, err = rec.w.Write(append(b, '\n'))
+	}
+	if err != nil && rec.err == nil {
+		rec.err = errors.Trace(err)
+	}
+}
+
+// isControl returns true if the URL is on the control port 888.
+func isControl(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Port() == "888"
+}
+
+// teeWriter captures the status code and body written to the response writer.
+type teeWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+// WriteHeader captures the status code.
+func (tw *teeWriter) WriteHeader(statusCode int) {
+	if tw.statusCode == 0 {
+		tw.statusCode = statusCode
+	}
+	tw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write captures the body.
+func (tw *teeWriter) Write(b []byte) (int, error) {
+	tw.body.Write(b)
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush flushes the underlying response writer, if it supports flushing.
+func (tw *teeWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer.
+func (tw *teeWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/utils"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestRun_CollectProfiles(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+	ctx := t.Context()
+
+	plane := utils.RandomIdentifier(12)
+	var replicas []*connector.Connector
+	for range 2 {
+		con := connector.New("profiled.collectprofiles")
+		con.SetPlane(plane)
+		con.SetDeployment(connector.TESTING)
+		err := con.Startup(ctx)
+		assert.NoError(err)
+		defer con.Shutdown(ctx)
+		replicas = append(replicas, con)
+	}
+
+	// Unsigned tokens are accepted in the TESTING deployment
+	admin := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin@example.com","roles":{"admin":true}}`)) + "."
+
+	out := t.TempDir()
+	saved, err := run(ctx, config{
+		host:    "profiled.collectprofiles",
+		profile: "goroutine",
+		token:   admin,
+		out:     out,
+		plane:   plane,
+	})
+	assert.NoError(err)
+	assert.Equal(2, saved)
+	for _, con := range replicas {
+		data, err := os.ReadFile(filepath.Join(out, "profiled.collectprofiles."+con.ID()+".goroutine.pb.gz"))
+		if assert.NoError(err) {
+			assert.True(len(data) > 0)
+		}
+	}
+
+	// Without a token
+	saved, err = run(ctx, config{
+		host:    "profiled.collectprofiles",
+		profile: "goroutine",
+		out:     out,
+		plane:   plane,
+	})
+	assert.NoError(err)
+	assert.Equal(0, saved)
+}
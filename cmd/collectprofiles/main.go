@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// collectprofiles collects a runtime profile from every replica of a microservice in one go,
+// via the :888/pprof control endpoint, and saves each to a file named after the replica's ID.
+// It connects to the bus per the MICROBUS_NATS and MICROBUS_PLANE env vars or env.yaml.
+// The actor token must satisfy the claims required by the endpoint, which are set by the
+// MICROBUS_PPROF_CLAIMS env var of the microservice.
+//
+//	collectprofiles -host my.service -profile heap -token $TOKEN
+//	go tool pprof my.service.abc123.heap.pb.gz
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/coreservices/control/controlapi"
+	"github.com/microbus-io/fabric/pub"
+)
+
+func main() {
+	host := flag.String("host", "", "hostname of the microservice to profile")
+	profile := flag.String("profile", "heap", "heap, goroutine, allocs, block, mutex, threadcreate, profile (CPU) or trace")
+	seconds := flag.Int("seconds", 10, "duration of the CPU profile or trace")
+	token := flag.String("token", os.Getenv("MICROBUS_PPROF_TOKEN"), "actor token of the request (default $MICROBUS_PPROF_TOKEN)")
+	out := flag.String("out", ".", "directory to save the profiles to")
+	plane := flag.String("plane", "", "plane of communication (default $MICROBUS_PLANE)")
+	flag.Parse()
+
+	if *host == "" {
+		fmt.Fprintln(os.Stderr, "collectprofiles: --host is required")
+		os.Exit(1)
+	}
+	cfg := config{
+		host:     *host,
+		profile:  *profile,
+		duration: time.Duration(*seconds) * time.Second,
+		token:    *token,
+		out:      *out,
+		plane:    *plane,
+	}
+	n, err := run(context.Background(), cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "collectprofiles:", err)
+		os.Exit(1)
+	}
+	if n == 0 {
+		fmt.Fprintln(os.Stderr, "collectprofiles: no replica of", *host, "responded")
+		os.Exit(2)
+	}
+}
+
+// config holds resolved CLI flags. Single-arg form lets tests drive run()
+// without exec'ing the binary.
+type config struct {
+	host     string
+	profile  string
+	duration time.Duration
+	token    string
+	out      string
+	plane    string
+}
+
+// run collects the profiles and returns the number of profiles saved.
+// Failures of individual replicas are reported to stderr without failing the run.
+func run(ctx context.Context, cfg config) (saved int, err error) {
+	con := connector.New("collect.profiles.cmd")
+	if cfg.plane != "" {
+		con.SetPlane(cfg.plane)
+	}
+	err = con.Startup(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer con.Shutdown(ctx)
+
+	err = os.MkdirAll(cfg.out, 0o755)
+	if err != nil {
+		return 0, err
+	}
+	ext := ".pb.gz"
+	if cfg.profile == "trace" {
+		ext = ".out"
+	}
+	client := controlapi.NewMulticastClient(con).ForHost(cfg.host)
+	if cfg.token != "" {
+		client = client.WithOptions(pub.Token(cfg.token))
+	}
+	for p := range client.CollectProfiles(ctx, cfg.profile, cfg.duration) {
+		if p.Err != nil {
+			fmt.Fprintln(os.Stderr, "collectprofiles:", p.Err)
+			continue
+		}
+		fileName := filepath.Join(cfg.out, p.Hostname+"."+p.ID+"."+cfg.profile+ext)
+		err = os.WriteFile(fileName, p.Data, 0o644)
+		if err != nil {
+			return saved, err
+		}
+		fmt.Println(fileName)
+		saved++
+	}
+	return saved, nil
+}
@@ -44,6 +44,7 @@ const (
 	impPub         = "github.com/microbus-io/fabric/pub"
 	impService     = "github.com/microbus-io/fabric/service"
 	impSub         = "github.com/microbus-io/fabric/sub"
+	impTick        = "github.com/microbus-io/fabric/tick"
 	impUtils       = "github.com/microbus-io/fabric/utils"
 	impApplication = "github.com/microbus-io/fabric/application"
 	impForeman     = "github.com/microbus-io/fabric/coreservices/foreman"
@@ -100,10 +101,20 @@ type featureView struct {
 	// Endpoint subscription options (intermediate.go only).
 	ReqClaims  string // sub.RequiredClaims expression, or ""
 	TimeBudget string // rendered sub.TimeBudget duration expr, or ""
+	MaxConc    string // rendered sub.MaxConcurrency expr, or ""
+	WaitQueue  string // rendered sub.WaitQueue expr, or ""
+	IdemTTL    string // rendered sub.IdempotencyKeys duration expr, or ""
 	Queue      string // "none" | "default" | custom queue name | ""
 	Manual     bool   // sub.Manual()
 	TagArgs    string // rendered sub.Tag arguments (e.g. `"python"`), or ""
 
+	// Function client only: the definition sets a RetryPolicy, applied as a pub.Retry option.
+	Retry bool
+	// Function client only: the definition sets a CacheTTL, applied as a pub.Cache option.
+	Cache bool
+	// OutboundEvent client only: the event is fired with pub.Durable and hooked with sub.Durable.
+	Durable bool
+
 	// Web client shape (client.go webs only): "plain" (ctx, relativeURL), "body" (ctx, relativeURL, body),
 	// or "any" (ctx, method, relativeURL, body). Selected from the endpoint's HTTP method.
 	WebShape string
@@ -209,7 +220,8 @@ func emitClient(svc *service, header string) ([]byte, error) {
 }
 
 // validateForClient reports the first feature that lacks the In/Out type carriers its client methods
-// require. Web carries neither and is exempt.
+// require. Web carries neither and is exempt. A RetryPolicy is accepted only on the idempotent methods,
+// and a CacheTTL only on the GET method.
 func validateForClient(svc *service) error {
 	for _, f := range svc.features {
 		switch f.kind {
@@ -218,6 +230,16 @@ func validateForClient(svc *service) error {
 				return fmt.Errorf("%s %q: both In and Out must be set in definition.go", f.kind, f.name)
 			}
 		}
+		if f.kind == "Function" && f.attrs["RetryPolicy"] != nil {
+			switch strings.ToUpper(attrString(f.attrs, "Method")) {
+			case "GET", "PUT", "DELETE":
+			default:
+				return fmt.Errorf("%s %q: RetryPolicy requires an idempotent GET, PUT or DELETE method", f.kind, f.name)
+			}
+		}
+		if f.kind == "Function" && f.attrs["CacheTTL"] != nil && strings.ToUpper(attrString(f.attrs, "Method")) != "GET" {
+			return fmt.Errorf("%s %q: CacheTTL requires the GET method", f.kind, f.name)
+		}
 	}
 	return nil
 }
@@ -230,6 +252,8 @@ func buildClientModel(svc *service, header string) *clientModel {
 		fv := newFeatureView(svc, f)
 		switch f.kind {
 		case "Function":
+			fv.Retry = f.attrs["RetryPolicy"] != nil
+			fv.Cache = f.attrs["CacheTTL"] != nil
 			m.Funcs = append(m.Funcs, fv)
 		case "Web":
 			fv.WebShape = webShape(attrString(f.attrs, "Method"))
@@ -239,6 +263,7 @@ func buildClientModel(svc *service, header string) *clientModel {
 		case "Workflow":
 			m.Workflows = append(m.Workflows, fv)
 		case "OutboundEvent":
+			fv.Durable = attrBool(f.attrs, "Durable")
 			m.OutboundEvents = append(m.OutboundEvents, fv)
 		}
 	}
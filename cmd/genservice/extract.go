@@ -358,6 +358,15 @@ func attrString(attrs map[string]ast.Expr, key string) string {
 	return strings.Trim(bl.Value, `"`)
 }
 
+// attrInt returns the value of an integer-literal attribute, or "".
+func attrInt(attrs map[string]ast.Expr, key string) string {
+	bl, ok := attrs[key].(*ast.BasicLit)
+	if !ok || bl.Kind != token.INT {
+		return ""
+	}
+	return bl.Value
+}
+
 // attrBool reports whether the attribute is the identifier true.
 func attrBool(attrs map[string]ast.Expr, key string) bool {
 	id, ok := attrs[key].(*ast.Ident)
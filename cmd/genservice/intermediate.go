@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"go/format"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -105,6 +106,7 @@ type tickerView struct {
 	Name       string
 	DocComment string
 	Interval   string // rendered duration expression
+	Options    string // rendered tick options, each with a leading comma, or ""
 }
 
 // emitIntermediate renders the service package's intermediate.go. resolveSource parses the api package
@@ -217,10 +219,13 @@ func emitIntermediate(svc *service, pkg, apiPath, resourcesPath, header string,
 	}
 	for _, tk := range m.Tickers {
 		addResolved(imports, svc.imports, tk.Interval)
+		if tk.Options != "" {
+			imports[impTick] = true
+		}
 	}
 	for _, group := range [][]*featureView{m.Funcs, m.Webs, m.Tasks, m.Workflows} {
 		for _, fv := range group {
-			addResolved(imports, svc.imports, fv.TimeBudget)
+			addResolved(imports, svc.imports, fv.TimeBudget, fv.IdemTTL)
 		}
 	}
 	for p := range imports {
@@ -255,6 +260,15 @@ func endpointView(svc *service, f feature) *featureView {
 		fv.TimeBudget = exprSource(svc.fset, tb)
 	}
 	fv.Queue = loadBalancingValue(f.attrs["LoadBalancing"])
+	if mc, ok := f.attrs["MaxConcurrency"]; ok {
+		fv.MaxConc = exprSource(svc.fset, mc)
+	}
+	if wq, ok := f.attrs["WaitQueue"]; ok {
+		fv.WaitQueue = exprSource(svc.fset, wq)
+	}
+	if ttl, ok := f.attrs["IdempotencyTTL"]; ok {
+		fv.IdemTTL = exprSource(svc.fset, ttl)
+	}
 	fv.Manual = attrBool(f.attrs, "Manual")
 	if tags := stringSlice(f.attrs["Tags"]); len(tags) > 0 {
 		quoted := make([]string, len(tags))
@@ -323,11 +337,21 @@ func buildMetric(svc *service, f feature) *metricView {
 
 // buildTicker builds the view for a ticker.
 func buildTicker(svc *service, f feature) *tickerView {
-	return &tickerView{
+	tv := &tickerView{
 		Name:       f.name,
 		DocComment: docComment(f.doc),
 		Interval:   exprSource(svc.fset, f.attrs["Interval"]),
 	}
+	if tv.Interval == "" {
+		tv.Interval = "0"
+	}
+	if schedule := attrString(f.attrs, "Schedule"); schedule != "" {
+		tv.Options += ", tick.Schedule(" + strconv.Quote(schedule) + ")"
+	}
+	if attrBool(f.attrs, "Singleton") {
+		tv.Options += ", tick.Singleton()"
+	}
+	return tv
 }
 
 // intermediateNeedsStrconv reports whether any config getter parses an int/bool/float64.
@@ -386,7 +410,7 @@ func inboundView(svc *service, f feature, resolveSource func(string) (*service,
 }
 
 // hookOptions renders the sub.Options for an inbound event's Hook.WithOptions call from its
-// RequiredClaims/TimeBudget/LoadBalancing fields, comma-joined, or "" when none are set.
+// RequiredClaims/TimeBudget/LoadBalancing/Manual/MaxDeliveries/Tags fields, comma-joined, or "" when none are set.
 func hookOptions(svc *service, f feature) string {
 	var opts []string
 	if claims := attrString(f.attrs, "RequiredClaims"); claims != "" {
@@ -408,9 +432,11 @@ func hookOptions(svc *service, f feature) string {
 	if attrBool(f.attrs, "Manual") {
 		opts = append(opts, "sub.Manual()")
 	}
+	if n, ok := f.attrs["MaxDeliveries"]; ok {
+		opts = append(opts, fmt.Sprintf("sub.MaxDeliveries(%s)", exprSource(svc.fset, n)))
+	}
 	for _, t := range stringSlice(f.attrs["Tags"]) {
 		opts = append(opts, fmt.Sprintf("sub.Tag(%q)", t))
 	}
 	return strings.Join(opts, ", ")
 }
-
@@ -135,7 +135,7 @@ func emitManifestSections(sb *strings.Builder, svc *service, resolveSource func(
 }
 
 // emitManifestEndpoints writes a function/outboundEvent/task section (signature, description, method,
-// route, loadBalancing, requiredClaims, timeBudget). withMethod controls whether the method line is
+// route, loadBalancing, requiredClaims, timeBudget, durable). withMethod controls whether the method line is
 // emitted (functions and outbound events carry it; tasks do not).
 func emitManifestEndpoints(sb *strings.Builder, svc *service, section string, fs []feature, withMethod bool) {
 	if len(fs) == 0 {
@@ -161,6 +161,14 @@ func emitManifestEndpoints(sb *strings.Builder, svc *service, section string, fs
 		if tb := renderDurationExpr(f.attrs["TimeBudget"]); tb != "" {
 			writeManifestKV(sb, "    ", "timeBudget", tb)
 		}
+		writeManifestKV(sb, "    ", "maxConcurrency", attrInt(f.attrs, "MaxConcurrency"))
+		writeManifestKV(sb, "    ", "waitQueue", attrInt(f.attrs, "WaitQueue"))
+		if ttl := renderDurationExpr(f.attrs["IdempotencyTTL"]); ttl != "" {
+			writeManifestKV(sb, "    ", "idempotencyTTL", ttl)
+		}
+		if attrBool(f.attrs, "Durable") {
+			sb.WriteString("    durable: true\n")
+		}
 	}
 }
 
@@ -185,6 +193,11 @@ func emitManifestWebs(sb *strings.Builder, fs []feature) {
 		if tb := renderDurationExpr(f.attrs["TimeBudget"]); tb != "" {
 			writeManifestKV(sb, "    ", "timeBudget", tb)
 		}
+		writeManifestKV(sb, "    ", "maxConcurrency", attrInt(f.attrs, "MaxConcurrency"))
+		writeManifestKV(sb, "    ", "waitQueue", attrInt(f.attrs, "WaitQueue"))
+		if ttl := renderDurationExpr(f.attrs["IdempotencyTTL"]); ttl != "" {
+			writeManifestKV(sb, "    ", "idempotencyTTL", ttl)
+		}
 	}
 }
 
@@ -207,6 +220,7 @@ func emitManifestInbound(sb *strings.Builder, svc *service, fs []feature, resolv
 		writeManifestKV(sb, "    ", "signature", endpointSignature(f.name, iv.inFields, iv.outFields))
 		writeManifestKV(sb, "    ", "description", f.doc)
 		writeManifestKV(sb, "    ", "package", srcPath)
+		writeManifestKV(sb, "    ", "maxDeliveries", attrInt(f.attrs, "MaxDeliveries"))
 	}
 	return nil
 }
@@ -296,6 +310,12 @@ func emitManifestTickers(sb *strings.Builder, fs []feature) {
 		if iv := renderDurationExpr(f.attrs["Interval"]); iv != "" {
 			writeManifestKV(sb, "    ", "interval", iv)
 		}
+		if schedule := attrString(f.attrs, "Schedule"); schedule != "" {
+			writeManifestKV(sb, "    ", "schedule", schedule)
+		}
+		if attrBool(f.attrs, "Singleton") {
+			sb.WriteString("    singleton: true\n")
+		}
 	}
 }
 
@@ -101,6 +101,7 @@ func marshalPublish(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	return func(yield func(*multicastResponse) bool) {
@@ -133,7 +134,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
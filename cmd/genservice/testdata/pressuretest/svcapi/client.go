@@ -212,6 +212,7 @@ func marshalRequest(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	if err != nil {
@@ -239,6 +240,7 @@ func marshalPublish(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	return func(yield func(*multicastResponse) bool) {
@@ -271,7 +273,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -418,11 +420,14 @@ func (_c MulticastClient) CreatePet(ctx context.Context, httpRequestBody *Pet) i
 	}
 }
 
-// LoadPet exercises the httpResponseBody / httpStatusCode magic arguments (REST load).
+// LoadPet exercises the httpResponseBody / httpStatusCode magic arguments (REST load) and a retry policy.
 func (_c Client) LoadPet(ctx context.Context, petID int64) (httpResponseBody *Pet, httpStatusCode int, err error) { // MARKER: LoadPet
 	_in := LoadPetIn{PetID: petID}
 	_out := LoadPetOut{}
-	err = marshalRequest(ctx, _c.svc, _c.opts, _c.host, LoadPet.Method, LoadPet.Route, &_in, &_out)
+	var _opts []pub.Option
+	_opts = append(_opts, pub.Retry(pub.RetryPolicy(LoadPet.RetryPolicy)))
+	_opts = append(_opts, _c.opts...)
+	err = marshalRequest(ctx, _c.svc, _opts, _c.host, LoadPet.Method, LoadPet.Route, &_in, &_out)
 	return _out.HTTPResponseBody, _out.HTTPStatusCode, err // No trace
 }
 
@@ -435,7 +440,7 @@ func (_res *LoadPetResponse) Get() (httpResponseBody *Pet, httpStatusCode int, e
 	return _d.HTTPResponseBody, _d.HTTPStatusCode, _res.err
 }
 
-// LoadPet exercises the httpResponseBody / httpStatusCode magic arguments (REST load).
+// LoadPet exercises the httpResponseBody / httpStatusCode magic arguments (REST load) and a retry policy.
 func (_c MulticastClient) LoadPet(ctx context.Context, petID int64) iter.Seq[*LoadPetResponse] { // MARKER: LoadPet
 	_in := LoadPetIn{PetID: petID}
 	_out := LoadPetOut{}
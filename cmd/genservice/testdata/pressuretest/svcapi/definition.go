@@ -69,10 +69,11 @@ type CreatePetOut struct {
 	HTTPStatusCode int    `json:"-"`
 }
 
-// LoadPet exercises the httpResponseBody / httpStatusCode magic arguments (REST load).
+// LoadPet exercises the httpResponseBody / httpStatusCode magic arguments (REST load) and a retry policy.
 var LoadPet = define.Function{
 	Host: Hostname, Method: "GET", Route: ":443/pets/{petID}",
-	In: LoadPetIn{}, Out: LoadPetOut{},
+	RetryPolicy: define.RetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond, Jitter: 0.2},
+	In:          LoadPetIn{}, Out: LoadPetOut{},
 }
 
 // LoadPetIn are the input arguments of LoadPet.
@@ -19,6 +19,7 @@ import (
 	"github.com/microbus-io/fabric/connector"
 	"github.com/microbus-io/fabric/httpx"
 	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/tick"
 )
 
 const (
@@ -43,6 +44,7 @@ type ToDo interface {
 	MainFlow(ctx context.Context) (graph *workflow.Graph, err error)                          // MARKER: MainFlow
 	OnSrcEvent(ctx context.Context, detail string, origin url.URL) (ok bool, err error)       // MARKER: OnSrcEvent
 	Reconcile(ctx context.Context) (err error)                                                // MARKER: Reconcile
+	Digest(ctx context.Context) (err error)                                                   // MARKER: Digest
 	OnObserveQueueDepth(ctx context.Context) (err error)                                      // MARKER: QueueDepth
 	OnChangedMaxItems(ctx context.Context) (err error)                                        // MARKER: MaxItems
 }
@@ -88,6 +90,8 @@ func NewIntermediate(impl ToDo) *Intermediate {
 		sub.Description(`Greet returns a greeting for a name.`),
 		sub.RequiredClaims(`roles.user`),
 		sub.TimeBudget(5*time.Second),
+		sub.MaxConcurrency(8),
+		sub.WaitQueue(16),
 		sub.Function(svcapi.GreetIn{}, svcapi.GreetOut{}),
 	)
 	svc.Subscribe( // MARKER: Adopt
@@ -95,6 +99,7 @@ func NewIntermediate(impl ToDo) *Intermediate {
 		sub.At(svcapi.Adopt.Method, svcapi.Adopt.Route),
 		sub.Description(`Adopt registers a pet and returns the adoption time. It exercises qualification of a domain type
 (Pet -> svcapi.Pet) and an external type (time.Time) in the generated service-package files.`),
+		sub.IdempotencyKeys(10*time.Minute),
 		sub.Function(svcapi.AdoptIn{}, svcapi.AdoptOut{}),
 	)
 	svc.Subscribe( // MARKER: Ping
@@ -140,11 +145,12 @@ func NewIntermediate(impl ToDo) *Intermediate {
 		sub.Description(`MainFlow is the top-level workflow graph.`),
 		sub.Workflow(svcapi.MainFlowIn{}, svcapi.MainFlowOut{}),
 	)
-	srcapi.NewHook(svc).OnSrcEvent(svc.OnSrcEvent)                                                                                     // MARKER: OnSrcEvent
+	srcapi.NewHook(svc).WithOptions(sub.MaxDeliveries(3)).OnSrcEvent(svc.OnSrcEvent)                                                   // MARKER: OnSrcEvent
 	svc.DescribeCounter("svc_requests_total", `RequestsTotal counts requests handled, labelled by status.`)                            // MARKER: RequestsTotal
 	svc.DescribeGauge("svc_queue_depth", `QueueDepth records the current queue depth, observed just-in-time via OnObserveQueueDepth.`) // MARKER: QueueDepth
 	svc.DescribeHistogram("svc_latency_seconds", `LatencySeconds records request latency in seconds.`, []float64{0.1, 0.5, 1, 5})      // MARKER: LatencySeconds
-	svc.StartTicker("Reconcile", 30*time.Second, svc.Reconcile)                                                                        // MARKER: Reconcile
+	svc.StartTicker("Reconcile", 30*time.Second, svc.Reconcile, tick.Singleton())                                                      // MARKER: Reconcile
+	svc.StartTicker("Digest", 0, svc.Digest, tick.Schedule("CRON_TZ=America/New_York 0 7 * * MON-FRI"))                                // MARKER: Digest
 	svc.DefineConfig(                                                                                                                  // MARKER: APIKey
 		"APIKey",
 		cfg.Description(`APIKey is a stored credential; never logged.`),
@@ -202,7 +208,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -33,6 +33,7 @@ type Mock struct {
 	unsubMockMainFlow       func() error                                                                        // MARKER: MainFlow
 	mockOnSrcEvent          func(ctx context.Context, detail string, origin url.URL) (ok bool, err error)       // MARKER: OnSrcEvent
 	mockReconcile           func(ctx context.Context) (err error)                                               // MARKER: Reconcile
+	mockDigest              func(ctx context.Context) (err error)                                               // MARKER: Digest
 	mockOnObserveQueueDepth func(ctx context.Context) (err error)                                               // MARKER: QueueDepth
 	mockOnChangedMaxItems   func(ctx context.Context) (err error)                                               // MARKER: MaxItems
 }
@@ -252,6 +253,20 @@ func (svc *Mock) Reconcile(ctx context.Context) (err error) { // MARKER: Reconci
 	return errors.Trace(err)
 }
 
+// MockDigest sets up a mock handler for Digest.
+func (svc *Mock) MockDigest(handler func(ctx context.Context) (err error)) *Mock { // MARKER: Digest
+	svc.mockDigest = handler
+	return svc
+}
+
+// Digest executes the mock handler.
+func (svc *Mock) Digest(ctx context.Context) (err error) { // MARKER: Digest
+	if svc.mockDigest != nil {
+		err = svc.mockDigest(ctx)
+	}
+	return errors.Trace(err)
+}
+
 // MockOnObserveQueueDepth sets up a mock handler for OnObserveQueueDepth.
 func (svc *Mock) MockOnObserveQueueDepth(handler func(ctx context.Context) (err error)) *Mock { // MARKER: QueueDepth
 	svc.mockOnObserveQueueDepth = handler
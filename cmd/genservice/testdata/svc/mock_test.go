@@ -159,6 +159,16 @@ func TestSvc_Mock(t *testing.T) {
 		assert.NoError(err)
 	})
 
+	t.Run("digest", func(t *testing.T) { // MARKER: Digest
+		assert := testarossa.For(t)
+
+		mock.MockDigest(func(ctx context.Context) (err error) {
+			return
+		})
+		err := mock.Digest(ctx)
+		assert.NoError(err)
+	})
+
 	t.Run("on_observe_queue_depth", func(t *testing.T) { // MARKER: QueueDepth
 		assert := testarossa.For(t)
 
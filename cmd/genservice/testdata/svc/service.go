@@ -117,6 +117,14 @@ MaxItems caps the number of items processed per run; changes fire OnChangedMaxIt
 func (svc *Service) OnChangedMaxItems(ctx context.Context) (err error) { return nil }
 
 /*
-Reconcile runs a periodic reconciliation.
+Reconcile runs a periodic reconciliation on a single instance.
 */
 func (svc *Service) Reconcile(ctx context.Context) (err error) { return nil }
+
+/*
+Digest sends the daily digest on weekday mornings.
+*/
+func (svc *Service) Digest(ctx context.Context) (err error) { // MARKER: Digest
+	// TODO: Implement Digest
+	return
+}
@@ -522,3 +522,31 @@ func TestSvc_Reconcile(t *testing.T) { // MARKER: Reconcile
 		})
 	*/
 }
+
+func TestSvc_Digest(t *testing.T) { // MARKER: Digest
+	t.Parallel()
+	ctx := t.Context()
+	_ = ctx
+
+	// Initialize the microservice under test
+	svc := NewService()
+
+	// Run the testing app
+	app := application.New()
+	app.Add(
+		// HINT: Add microservices or mocks required for this test
+		svc,
+	)
+	app.RunInTest(t)
+
+	/*
+		HINT: Fill in test cases using the following pattern
+
+		t.Run("test_case_name", func(t *testing.T) {
+			assert := testarossa.For(t)
+
+			err := svc.Digest(ctx)
+			assert.NoError(err)
+		})
+	*/
+}
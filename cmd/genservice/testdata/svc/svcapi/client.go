@@ -198,6 +198,7 @@ func marshalRequest(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	if err != nil {
@@ -225,6 +226,7 @@ func marshalPublish(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	return func(yield func(*multicastResponse) bool) {
@@ -257,7 +259,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -569,7 +571,8 @@ func (_res *OnPeerSeenResponse) Get() (ack bool, err error) { // MARKER: OnPeerS
 func (_c MulticastTrigger) OnPeerSeen(ctx context.Context, peer netip.Addr) iter.Seq[*OnPeerSeenResponse] { // MARKER: OnPeerSeen
 	_in := OnPeerSeenIn{Peer: peer}
 	_out := OnPeerSeenOut{}
-	_inner := marshalPublish(ctx, _c.svc, _c.opts, _c.host, OnPeerSeen.Method, OnPeerSeen.Route, &_in, &_out)
+	_opts := append([]pub.Option{pub.Durable()}, _c.opts...)
+	_inner := marshalPublish(ctx, _c.svc, _opts, _c.host, OnPeerSeen.Method, OnPeerSeen.Route, &_in, &_out)
 	return func(yield func(*OnPeerSeenResponse) bool) {
 		for _r := range _inner {
 			_clone := _out
@@ -599,6 +602,7 @@ func (c Hook) OnPeerSeen(handler func(ctx context.Context, peer netip.Addr) (ack
 	subOpts := append([]sub.Option{
 		sub.At(OnPeerSeen.Method, path),
 		sub.InboundEvent(OnPeerSeenIn{}, OnPeerSeenOut{}),
+		sub.Durable(),
 	}, c.opts...)
 	if err := c.svc.Subscribe(name, doOnPeerSeen, subOpts...); err != nil {
 		return nil, errors.Trace(err)
@@ -44,6 +44,8 @@ var Greet = define.Function{
 	Host: Hostname, Method: "POST", Route: ":443/greet",
 	RequiredClaims: "roles.user",
 	TimeBudget:     5 * time.Second,
+	MaxConcurrency: 8,
+	WaitQueue:      16,
 	In:             GreetIn{}, Out: GreetOut{},
 }
 
@@ -62,6 +64,7 @@ type GreetOut struct {
 var Adopt = define.Function{
 	Host: Hostname, Method: "POST", Route: ":443/adopt",
 	In: AdoptIn{}, Out: AdoptOut{},
+	IdempotencyTTL: 10 * time.Minute,
 }
 
 // AdoptIn are the input arguments of Adopt.
@@ -160,14 +163,15 @@ type MainFlowOut struct {
 
 // OnSrcEvent handles the upstream srcapi.OnSrcEvent event.
 var OnSrcEvent = define.InboundEvent{
-	Source: srcapi.OnSrcEvent,
+	Source:        srcapi.OnSrcEvent,
+	MaxDeliveries: 3,
 }
 
 // OnPeerSeen fires when a peer is observed. Peer is a non-scalar field whose package (net/netip) is
 // imported by no other feature, pinning that an outbound event's field types reach client.go (its
 // Trigger) but never intermediate.go, which generates no handler for an outbound event.
 var OnPeerSeen = define.OutboundEvent{
-	Host: Hostname, Method: "POST", Route: ":417/on-peer-seen",
+	Host: Hostname, Method: "POST", Route: ":417/on-peer-seen", Durable: true,
 	In: OnPeerSeenIn{}, Out: OnPeerSeenOut{},
 }
 
@@ -230,7 +234,13 @@ var RefreshInterval = define.Config{
 	Validation: "dur (0s,24h]",
 }
 
-// Reconcile runs a periodic reconciliation.
+// Reconcile runs a periodic reconciliation on a single instance.
 var Reconcile = define.Ticker{
-	Interval: 30 * time.Second,
+	Interval:  30 * time.Second,
+	Singleton: true,
+}
+
+// Digest sends the daily digest on weekday mornings.
+var Digest = define.Ticker{
+	Schedule: "CRON_TZ=America/New_York 0 7 * * MON-FRI",
 }
@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/lru"
+)
+
+// errCanceledByCaller is the cause of the cancellation of the context of a handler whose caller abandoned the request.
+var errCanceledByCaller = errors.New("canceled by caller")
+
+// inflightHandler is a handler that is processing a request on behalf of a caller that may cancel it.
+type inflightHandler struct {
+	fromID string
+	cancel context.CancelCauseFunc
+}
+
+// registerInflight registers the cancel function of a handler processing a request so that the caller can cancel it.
+// The returned function must be called to deregister the handler when it returns.
+// The handler is canceled immediately if the caller canceled the request before it was registered.
+func (c *Connector) registerInflight(msgID string, fromID string, cancel context.CancelCauseFunc) (deregister func()) {
+	h := &inflightHandler{
+		fromID: fromID,
+		cancel: cancel,
+	}
+	c.inflightLock.Lock()
+	c.inflight[msgID] = append(c.inflight[msgID], h)
+	c.inflightLock.Unlock()
+	if canceledBy, ok := c.earlyCancels.Load(msgID, lru.NoBump()); ok && canceledBy == fromID {
+		cancel(errCanceledByCaller)
+	}
+	return func() {
+		c.inflightLock.Lock()
+		handlers := c.inflight[msgID]
+		for i := range handlers {
+			if handlers[i] == h {
+				handlers = append(handlers[:i], handlers[i+1:]...)
+				break
+			}
+		}
+		if len(handlers) == 0 {
+			delete(c.inflight, msgID)
+		} else {
+			c.inflight[msgID] = handlers
+		}
+		c.inflightLock.Unlock()
+	}
+}
+
+// cancelInflight cancels the contexts of the handlers processing a request on behalf of the caller.
+// A cancellation that arrives before the handler is registered is remembered for a short while.
+func (c *Connector) cancelInflight(msgID string, fromID string) {
+	canceled := 0
+	c.inflightLock.Lock()
+	for _, h := range c.inflight[msgID] {
+		if h.fromID == fromID {
+			h.cancel(errCanceledByCaller)
+			canceled++
+		}
+	}
+	c.inflightLock.Unlock()
+	if canceled == 0 {
+		c.earlyCancels.Store(msgID, fromID)
+	}
+	c.LogDebug(c.Lifetime(), "Canceled by caller",
+		"msg", msgID,
+		"fromID", fromID,
+		"handlers", canceled,
+	)
+}
+
+// sendCancel notifies the responders that acked a request, but have not yet responded to it,
+// that the caller abandoned the request. Responders must have advertised support for cancellation in their ack. The notification is sent directly to each responder and is not acked.
+func (c *Connector) sendCancel(ctx context.Context, httpReq *http.Request, port string, host string, msgID string, responderIDs []string) {
+	for _, responderID := range responderIDs {
+		cancelReq, err := http.NewRequest(httpReq.Method, httpReq.URL.String(), http.NoBody)
+		if err != nil {
+			c.LogError(ctx, "Canceling request", "error", errors.Trace(err))
+			return
+		}
+		frm := frame.Of(cancelReq)
+		frm.SetOpCode(frame.OpCodeCancel)
+		frm.SetFromHost(c.hostname)
+		frm.SetFromID(c.id)
+		frm.SetMessageID(msgID)
+		subject := SubjectOfRequest(c.plane, port, c.hostname, host, responderID, httpReq.Method, httpReq.URL.Path)
+		err = c.transportConn.Publish(subject, cancelReq)
+		if err != nil {
+			c.LogError(ctx, "Canceling request",
+				"error", errors.Trace(err),
+				"msg", msgID,
+				"responder", responderID,
+			)
+		}
+	}
+	c.Span(ctx).AddEvent("cancel",
+		"msg", msgID,
+		"responders", len(responderIDs),
+	)
+}
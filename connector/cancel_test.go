@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_CancelPropagation(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	entered := make(chan bool, 1)
+	causes := make(chan error, 1)
+	con := New("cancel.propagation.connector")
+	con.Subscribe("Block",
+		func(w http.ResponseWriter, r *http.Request) error {
+			entered <- true
+			<-r.Context().Done()
+			causes <- context.Cause(r.Context())
+			return errors.Trace(r.Context().Err())
+		},
+		sub.At("GET", "/block"),
+		sub.Web(),
+	)
+	client := New("client.cancel.propagation.connector")
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	reqCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		_, err := client.Request(reqCtx, pub.GET("https://cancel.propagation.connector/block"))
+		done <- err
+	}()
+	<-entered
+
+	// Canceling the context of the caller cancels the context of the handler
+	t0 := time.Now()
+	cancel()
+	err = <-done
+	if assert.Error(err) {
+		assert.True(errors.Is(err, context.Canceled))
+	}
+	select {
+	case cause := <-causes:
+		assert.Equal(errCanceledByCaller, cause)
+	case <-time.After(5 * time.Second):
+		assert.True(false, "handler was not canceled")
+	}
+	assert.True(time.Since(t0) < time.Second)
+
+	// The handler is deregistered once it returns
+	time.Sleep(50 * time.Millisecond)
+	con.inflightLock.Lock()
+	assert.Len(con.inflight, 0)
+	con.inflightLock.Unlock()
+}
+
+func TestConnector_CancelBeforeRegistration(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	con := New("cancel.before.registration.connector")
+
+	// A cancellation from a different caller is ignored
+	con.cancelInflight("msg1", "caller1")
+	ctx, cancel := context.WithCancelCause(t.Context())
+	deregister := con.registerInflight("msg1", "caller2", cancel)
+	assert.NoError(ctx.Err())
+	deregister()
+
+	// A cancellation that arrives before the handler is registered cancels it upon registration
+	ctx, cancel = context.WithCancelCause(t.Context())
+	deregister = con.registerInflight("msg1", "caller1", cancel)
+	assert.Equal(errCanceledByCaller, context.Cause(ctx))
+	deregister()
+	assert.Len(con.inflight, 0)
+}
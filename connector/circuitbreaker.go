@@ -0,0 +1,207 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/errors"
+)
+
+// Circuit breaker states, as reported by the microbus_client_circuit_breaker_state gauge.
+const (
+	circuitClosed   = 0
+	circuitHalfOpen = 1
+	circuitOpen     = 2
+)
+
+// CircuitBreaker configures the circuit breakers that guard outgoing unicast requests.
+// A circuit breaker is kept per destination, with each version slot, e.g. ver-7.example.com, being a
+// separate destination. Requests addressed to a specific replica by its ID are not guarded.
+// A breaker trips open when the ratio of failed requests, i.e. those that fail with a 5xx status code
+// or an ack timeout, reaches the error ratio. Requests abandoned by the caller are not counted.
+// While open, requests to the destination fail fast with a 503 status code.
+// After the open duration, a limited number of probe requests are let through to test the destination.
+// The breaker closes if the probes succeed and opens again if any fails.
+type CircuitBreaker struct {
+	ErrorRatio   float64       // ratio of failed requests that trips the breaker, between 0 and 1; zero disables circuit breaking
+	MinRequests  int           // minimum number of requests in the window before the breaker can trip; defaults to 10
+	Window       time.Duration // duration of the window in which requests are counted; defaults to 10 seconds
+	OpenDuration time.Duration // duration the breaker stays open before probing the destination; defaults to 5 seconds
+	Probes       int           // number of successful probes required to close the breaker; defaults to 1
+	ByPort       bool          // keep a separate breaker per port of the destination
+	ByPath       bool          // keep a separate breaker per port and path of the destination
+}
+
+// SetCircuitBreaker configures the circuit breakers that guard outgoing unicast requests.
+// Circuit breaking is disabled by default.
+func (c *Connector) SetCircuitBreaker(cb CircuitBreaker) error {
+	if !c.isPhase(shutDown) {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	if cb.ErrorRatio < 0 || cb.ErrorRatio > 1 {
+		return c.captureInitErr(errors.New("error ratio must be between 0 and 1"))
+	}
+	if cb.MinRequests <= 0 {
+		cb.MinRequests = 10
+	}
+	if cb.Window <= 0 {
+		cb.Window = 10 * time.Second
+	}
+	if cb.OpenDuration <= 0 {
+		cb.OpenDuration = 5 * time.Second
+	}
+	if cb.Probes <= 0 {
+		cb.Probes = 1
+	}
+	if cb.ByPath {
+		cb.ByPort = true
+	}
+	c.circuitBreaker = cb
+	return nil
+}
+
+// circuitBreakerState is the state of the circuit breaker of a single destination.
+type circuitBreakerState struct {
+	mux         sync.Mutex
+	state       int
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+// circuitBreakerFor returns the circuit breaker of the destination, or nil if circuit breaking is disabled.
+func (c *Connector) circuitBreakerFor(host string, port string, path string) (key string, cbs *circuitBreakerState) {
+	if c.circuitBreaker.ErrorRatio <= 0 {
+		return "", nil
+	}
+	key = strings.ToLower(host)
+	if c.circuitBreaker.ByPort {
+		key += ":" + port
+	}
+	if c.circuitBreaker.ByPath {
+		key += path
+	}
+	cbs, _ = c.circuitBreakers.LoadOrStoreFunc(key, func() *circuitBreakerState {
+		return &circuitBreakerState{}
+	})
+	return key, cbs
+}
+
+// allowRequest indicates if a request may be sent to the destination.
+// In the half-open state, only a limited number of probe requests are allowed.
+func (c *Connector) allowRequest(ctx context.Context, key string, cbs *circuitBreakerState) bool {
+	cbs.mux.Lock()
+	defer cbs.mux.Unlock()
+	switch cbs.state {
+	case circuitOpen:
+		if time.Since(cbs.openedAt) < c.circuitBreaker.OpenDuration {
+			return false
+		}
+		c.transitionCircuit(ctx, key, cbs, circuitHalfOpen)
+		fallthrough
+	case circuitHalfOpen:
+		if cbs.probes >= c.circuitBreaker.Probes {
+			return false
+		}
+		cbs.probes++
+		return true
+	}
+	return true
+}
+
+// recordOutcome records the outcome of a request to the destination and trips or resets the breaker accordingly.
+func (c *Connector) recordOutcome(ctx context.Context, key string, cbs *circuitBreakerState, failed bool) {
+	cbs.mux.Lock()
+	defer cbs.mux.Unlock()
+	switch cbs.state {
+	case circuitClosed:
+		now := time.Now()
+		if now.Sub(cbs.windowStart) >= c.circuitBreaker.Window {
+			cbs.windowStart = now
+			cbs.requests = 0
+			cbs.failures = 0
+		}
+		cbs.requests++
+		if failed {
+			cbs.failures++
+		}
+		if cbs.requests >= c.circuitBreaker.MinRequests &&
+			float64(cbs.failures) >= c.circuitBreaker.ErrorRatio*float64(cbs.requests) {
+			c.transitionCircuit(ctx, key, cbs, circuitOpen)
+		}
+	case circuitHalfOpen:
+		if failed {
+			c.transitionCircuit(ctx, key, cbs, circuitOpen)
+			return
+		}
+		cbs.successes++
+		if cbs.successes >= c.circuitBreaker.Probes {
+			c.transitionCircuit(ctx, key, cbs, circuitClosed)
+		}
+	}
+}
+
+// releaseProbe releases the slot of a request whose outcome is unknown, such as one abandoned by the caller,
+// without counting it toward tripping or resetting the breaker.
+func (c *Connector) releaseProbe(cbs *circuitBreakerState) {
+	cbs.mux.Lock()
+	defer cbs.mux.Unlock()
+	if cbs.state == circuitHalfOpen && cbs.probes > cbs.successes {
+		cbs.probes--
+	}
+}
+
+// transitionCircuit moves the breaker to a new state. It must be called under the lock of the breaker.
+func (c *Connector) transitionCircuit(ctx context.Context, key string, cbs *circuitBreakerState, state int) {
+	cbs.state = state
+	cbs.probes = 0
+	cbs.successes = 0
+	switch state {
+	case circuitOpen:
+		cbs.openedAt = time.Now()
+		c.LogWarn(ctx, "Circuit breaker opened",
+			"destination", key,
+			"requests", cbs.requests,
+			"failures", cbs.failures,
+		)
+	case circuitClosed:
+		cbs.windowStart = time.Now()
+		cbs.requests = 0
+		cbs.failures = 0
+		c.LogInfo(ctx, "Circuit breaker closed",
+			"destination", key,
+		)
+	}
+	_ = c.RecordGauge(ctx, "microbus_client_circuit_breaker_state", float64(state), "destination", key)
+}
+
+// observeCircuitBreakers records the state of all circuit breakers.
+func (c *Connector) observeCircuitBreakers(ctx context.Context) {
+	for key, cbs := range c.circuitBreakers.ToMap() {
+		cbs.mux.Lock()
+		state := cbs.state
+		cbs.mux.Unlock()
+		_ = c.RecordGauge(ctx, "microbus_client_circuit_breaker_state", float64(state), "destination", key)
+	}
+}
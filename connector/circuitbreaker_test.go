@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservices
+	var count atomic.Int32
+	var healthy atomic.Bool
+	alpha := New("alpha.circuit.breaker.connector")
+	alpha.SetCircuitBreaker(CircuitBreaker{
+		ErrorRatio:   0.5,
+		MinRequests:  4,
+		OpenDuration: 200 * time.Millisecond,
+	})
+	beta := New("beta.circuit.breaker.connector")
+	beta.Subscribe("Flaky",
+		func(w http.ResponseWriter, r *http.Request) error {
+			count.Add(1)
+			if !healthy.Load() {
+				return errors.New("unavailable", http.StatusServiceUnavailable)
+			}
+			return nil
+		},
+		sub.At("GET", "flaky"),
+		sub.Web(),
+	)
+	beta.Subscribe("Slow",
+		func(w http.ResponseWriter, r *http.Request) error {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return nil
+		},
+		sub.At("GET", "slow"),
+		sub.Web(),
+	)
+
+	// Startup the microservices
+	err := alpha.Startup(ctx)
+	assert.NoError(err)
+	defer alpha.Shutdown(ctx)
+	err = beta.Startup(ctx)
+	assert.NoError(err)
+	defer beta.Shutdown(ctx)
+
+	// Failures below the minimum number of requests do not trip the breaker
+	for range 4 {
+		_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+		assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	}
+	assert.Equal(int32(4), count.Load())
+
+	// The breaker is open and requests fail fast
+	_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "circuit open")
+		assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	}
+	assert.Equal(int32(4), count.Load())
+
+	// A failed probe opens the breaker again
+	time.Sleep(200 * time.Millisecond)
+	_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+	assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	assert.Equal(int32(5), count.Load())
+	_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+	assert.Contains(err.Error(), "circuit open")
+	assert.Equal(int32(5), count.Load())
+
+	// A probe abandoned by the caller neither closes nor opens the breaker
+	time.Sleep(200 * time.Millisecond)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(50*time.Millisecond, cancel)
+	_, err = alpha.GET(cancelCtx, "https://beta.circuit.breaker.connector/slow")
+	assert.True(errors.Is(err, context.Canceled))
+	_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+	assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	assert.Equal(int32(6), count.Load())
+	_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+	assert.Contains(err.Error(), "circuit open")
+	assert.Equal(int32(6), count.Load())
+
+	// A successful probe closes the breaker
+	healthy.Store(true)
+	time.Sleep(200 * time.Millisecond)
+	for range 4 {
+		_, err = alpha.GET(ctx, "https://beta.circuit.breaker.connector/flaky")
+		assert.NoError(err)
+	}
+	assert.Equal(int32(10), count.Load())
+
+	// Other destinations are not affected
+	_, err = alpha.GET(ctx, "https://alpha.circuit.breaker.connector/nowhere")
+	assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+}
+
+func TestConnector_CircuitBreakerAckTimeout(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservice
+	con := New("circuit.breaker.ack.timeout.connector")
+	con.SetCircuitBreaker(CircuitBreaker{
+		ErrorRatio:  1,
+		MinRequests: 2,
+	})
+
+	// Startup the microservice
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// Ack timeouts trip the breaker
+	for range 2 {
+		_, err = con.GET(ctx, "https://nonexistent.circuit.breaker.ack.timeout.connector/")
+		if assert.Error(err) {
+			assert.Contains(err.Error(), "ack timeout")
+		}
+	}
+	t0 := time.Now()
+	_, err = con.GET(ctx, "https://nonexistent.circuit.breaker.ack.timeout.connector/")
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "circuit open")
+	}
+	assert.True(time.Since(t0) < con.ackTimeout)
+}
+
+func TestConnector_CircuitBreakerTargets(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservices
+	alpha := New("alpha.circuit.breaker.targets.connector")
+	alpha.SetCircuitBreaker(CircuitBreaker{
+		ErrorRatio:   1,
+		MinRequests:  2,
+		OpenDuration: time.Minute,
+	})
+	beta := New("beta.circuit.breaker.targets.connector")
+	beta.SetVersion(3)
+	beta.Subscribe("Fail",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return errors.New("unavailable", http.StatusServiceUnavailable)
+		},
+		sub.At("GET", "fail"),
+		sub.Web(),
+	)
+	beta.Subscribe("Succeed",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return nil
+		},
+		sub.At("GET", "succeed"),
+		sub.Web(),
+	)
+
+	// Startup the microservices
+	err := alpha.Startup(ctx)
+	assert.NoError(err)
+	defer alpha.Shutdown(ctx)
+	err = beta.Startup(ctx)
+	assert.NoError(err)
+	defer beta.Shutdown(ctx)
+
+	// Requests to a specific replica bypass the breaker
+	for range 4 {
+		_, err = alpha.GET(ctx, "https://"+beta.ID()+".beta.circuit.breaker.targets.connector/fail")
+		if assert.Error(err) {
+			assert.NotContains(err.Error(), "circuit open")
+		}
+	}
+
+	// Requests to a version slot are guarded by the breaker of that version
+	for range 2 {
+		_, err = alpha.GET(ctx, "https://ver-3.beta.circuit.breaker.targets.connector/fail")
+		if assert.Error(err) {
+			assert.NotContains(err.Error(), "circuit open")
+		}
+	}
+	_, err = alpha.GET(ctx, "https://ver-3.beta.circuit.breaker.targets.connector/fail")
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "circuit open")
+	}
+	_, err = alpha.GET(ctx, "https://beta.circuit.breaker.targets.connector/succeed")
+	assert.NoError(err)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"iter"
+	"net/http"
+	"slices"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// coalescedFlight is a pending unicast request whose response is shared with the identical requests
+// that arrive while it is pending.
+type coalescedFlight struct {
+	followers []*transferChan
+	err       *errors.TracedError
+}
+
+// coalesceKeyOf returns the key that identifies identical requests: their method, URL, actor, the headers that
+// negotiate their content, and body.
+// The body of the request is buffered in order to hash it.
+func coalesceKeyOf(req *pub.Request) (string, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		if br, ok := req.Body.(*httpx.BodyReader); ok {
+			body = br.Bytes()
+		} else {
+			var err error
+			body, err = io.ReadAll(req.Body)
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			req.Body = httpx.NewBodyReader(body)
+		}
+	}
+	bodyHash := sha256.Sum256(body)
+	h := sha256.New()
+	for _, part := range []string{
+		req.Method,
+		req.URL,
+		req.Header.Get(frame.HeaderActor),
+		req.Header.Get("Accept"),
+		req.Header.Get("Accept-Encoding"),
+		req.Header.Get("Accept-Language"),
+		hex.EncodeToString(bodyHash[:]),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// beginCoalesced waits for the response of a pending identical request, if there is one, and returns it.
+// Otherwise, the request leads a new flight that must be ended with endCoalesced after its response arrives.
+// If the leader of the flight is canceled, neither a response nor a flight are returned
+// and the request should be sent independently.
+func (c *Connector) beginCoalesced(ctx context.Context, key string) (shared *pub.Response, flight *coalescedFlight) {
+	c.coalesceLock.Lock()
+	leader, ok := c.coalescedFlights[key]
+	if !ok {
+		flight = &coalescedFlight{}
+		c.coalescedFlights[key] = flight
+		c.coalesceLock.Unlock()
+		return nil, flight
+	}
+	awaitCh := &transferChan{
+		C:    make(chan *http.Response, 1),
+		Done: make(chan bool),
+	}
+	leader.followers = append(leader.followers, awaitCh)
+	c.coalesceLock.Unlock()
+
+	select {
+	case <-awaitCh.Done:
+		select {
+		case res := <-awaitCh.C:
+			return pub.NewHTTPResponse(res), nil
+		default:
+		}
+		if leader.err != nil {
+			// Copy the error so that it can be traced independently of the other followers
+			dup := *leader.err
+			dup.Stack = slices.Clip(dup.Stack)
+			return pub.NewErrorResponse(errors.Trace(&dup, c.Span(ctx).TraceID())), nil
+		}
+		return nil, nil
+	case <-ctx.Done():
+		err := errors.Trace(ctx.Err(), c.Span(ctx).TraceID())
+		return pub.NewErrorResponse(err), nil
+	}
+}
+
+// endCoalesced shares the response of the leader of the flight with the identical requests
+// that arrived while it was pending.
+func (c *Connector) endCoalesced(ctx context.Context, key string, flight *coalescedFlight, queue iter.Seq[*pub.Response]) iter.Seq[*pub.Response] {
+	c.coalesceLock.Lock()
+	if c.coalescedFlights[key] == flight {
+		delete(c.coalescedFlights, key)
+	}
+	followers := flight.followers
+	c.coalesceLock.Unlock()
+	if len(followers) == 0 {
+		return queue
+	}
+	defer func() {
+		for _, awaitCh := range followers {
+			close(awaitCh.Done)
+		}
+	}()
+
+	var r *pub.Response
+	queue(func(rr *pub.Response) bool {
+		r = rr
+		return false
+	})
+	if r == nil || ctx.Err() != nil {
+		// The followers send their requests independently
+		return queue
+	}
+	c.LogDebug(ctx, "Coalesced requests",
+		"count", len(followers)+1,
+	)
+	res, err := r.Get()
+	if err != nil {
+		dup := *errors.Convert(err)
+		dup.Stack = slices.Clip(dup.Stack)
+		flight.err = &dup
+		return pub.NewSoloResponseQueue(r)
+	}
+	body, err := bodyOf(res)
+	if err != nil {
+		flight.err = errors.Convert(errors.Trace(err))
+		return pub.NewSoloResponseQueue(r)
+	}
+	for _, awaitCh := range followers {
+		dup := *res
+		dup.Header = res.Header.Clone()
+		dup.Body = httpx.NewBodyReader(body)
+		awaitCh.C <- &dup
+	}
+	return pub.NewSoloResponseQueue(r)
+}
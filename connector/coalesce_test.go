@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Coalesce(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	entered := make(chan bool, 16)
+	release := make(chan bool)
+	con := New("coalesce.connector")
+	con.Subscribe("Lookup",
+		func(w http.ResponseWriter, r *http.Request) error {
+			count.Add(1)
+			entered <- true
+			<-release
+			body, _ := io.ReadAll(r.Body)
+			if string(body) == "fail" {
+				return errors.New("failed", http.StatusConflict)
+			}
+			w.Write([]byte("value of " + r.URL.Query().Get("k") + string(body)))
+			return nil
+		},
+		sub.At("ANY", "/lookup"),
+		sub.Web(),
+	)
+	client := New("client.coalesce.connector")
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// awaitFollowers waits for the given number of requests to join the pending flight
+	awaitFollowers := func(n int) {
+		for range 200 {
+			client.coalesceLock.Lock()
+			joined := 0
+			for _, flight := range client.coalescedFlights {
+				joined += len(flight.followers)
+			}
+			client.coalesceLock.Unlock()
+			if joined >= n {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		assert.True(false, "followers did not join")
+	}
+
+	// run makes n concurrent requests and releases them once the followers joined the flight
+	run := func(n int, options ...pub.Option) (bodies []string, errs []error) {
+		var wg sync.WaitGroup
+		var mux sync.Mutex
+		request := func() {
+			defer wg.Done()
+			res, err := client.Request(ctx, options...)
+			mux.Lock()
+			defer mux.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			b, _ := io.ReadAll(res.Body)
+			bodies = append(bodies, string(b))
+		}
+		wg.Add(1)
+		go request()
+		<-entered
+		for range n - 1 {
+			wg.Add(1)
+			go request()
+		}
+		awaitFollowers(n - 1)
+		close(release)
+		wg.Wait()
+		release = make(chan bool)
+		return bodies, errs
+	}
+
+	// Identical requests are coalesced
+	count.Store(0)
+	bodies, errs := run(8, pub.GET("https://coalesce.connector/lookup?k=a"), pub.Coalesce())
+	assert.Len(errs, 0)
+	assert.Len(bodies, 8)
+	for _, b := range bodies {
+		assert.Equal("value of a", b)
+	}
+	assert.Equal(int32(1), count.Load())
+
+	// Requests with an identical body are coalesced
+	count.Store(0)
+	bodies, errs = run(4, pub.POST("https://coalesce.connector/lookup?k=b"), pub.Body("!"), pub.Coalesce())
+	assert.Len(errs, 0)
+	for _, b := range bodies {
+		assert.Equal("value of b!", b)
+	}
+	assert.Equal(int32(1), count.Load())
+
+	// Errors are shared too
+	count.Store(0)
+	bodies, errs = run(4, pub.POST("https://coalesce.connector/lookup"), pub.Body("fail"), pub.Coalesce())
+	assert.Len(bodies, 0)
+	assert.Len(errs, 4)
+	for _, err := range errs {
+		assert.Equal(http.StatusConflict, errors.StatusCode(err))
+	}
+	assert.Equal(int32(1), count.Load())
+
+	// Requests that do not opt in are not coalesced
+	count.Store(0)
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Request(ctx, pub.GET("https://coalesce.connector/lookup?k=c"))
+		}()
+	}
+	for range 3 {
+		<-entered
+	}
+	close(release)
+	wg.Wait()
+	release = make(chan bool)
+	assert.Equal(int32(3), count.Load())
+}
+
+func TestConnector_CoalesceKey(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	keyOf := func(options ...pub.Option) string {
+		req, err := pub.NewRequest(options...)
+		assert.NoError(err)
+		key, err := coalesceKeyOf(req)
+		assert.NoError(err)
+		return key
+	}
+	base := keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x"))
+	assert.Equal(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x")))
+	assert.Equal(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x"), pub.Header("X-Other", "1")))
+	assert.NotEqual(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("y")))
+	assert.NotEqual(base, keyOf(pub.PUT("https://coalesce.key/path"), pub.Body("x")))
+	assert.NotEqual(base, keyOf(pub.POST("https://coalesce.key/path?q=1"), pub.Body("x")))
+	assert.NotEqual(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x"), pub.Actor(map[string]any{"sub": "alice"})))
+	assert.NotEqual(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x"), pub.Header("Accept", "application/cbor")))
+	assert.NotEqual(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x"), pub.Header("Accept-Encoding", "gzip")))
+	assert.NotEqual(base, keyOf(pub.POST("https://coalesce.key/path"), pub.Body("x"), pub.Header("Accept-Language", "fr")))
+
+	// The body is buffered so that it can still be sent
+	req, _ := pub.NewRequest(pub.POST("https://coalesce.key/path"), pub.Body("x"))
+	_, err := coalesceKeyOf(req)
+	assert.NoError(err)
+	b, _ := io.ReadAll(req.Body)
+	assert.Equal("x", string(b))
+}
@@ -0,0 +1,199 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+const (
+	// defaultCompressionThreshold is the default size of the body at or above which payloads are compressed.
+	defaultCompressionThreshold = 32 << 10
+	// maxDecompressedSize limits the size of a decompressed body, to protect against decompression bombs.
+	maxDecompressedSize = 1 << 30
+	// minCompressionSavings is the minimum reduction in size that warrants sending the compressed body.
+	minCompressionSavings = 0.1
+)
+
+// compressionEncodings are the encodings the connector is able to compress and decompress, in order of preference.
+var compressionEncodings = []string{"zstd", "br"}
+
+var (
+	zstdEncoder = sync.OnceValue(func() *zstd.Encoder {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		return enc
+	})
+	zstdDecoder = sync.OnceValue(func() *zstd.Decoder {
+		dec, _ := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedSize), zstd.WithDecoderConcurrency(0))
+		return dec
+	})
+)
+
+/*
+SetCompressionThreshold sets the size of the body at or above which the payloads of responses
+are compressed before they are fragmented and sent over the transport.
+Compression is negotiated: a response is compressed only if the request advertised that its sender is able to decompress it.
+Requests are not compressed because the replicas of the destination may not all be able to decompress them.
+Payloads whose Content-Encoding is set by the application are not compressed again, nor are streamed responses.
+A threshold of zero disables compression. The default threshold is 32KB.
+*/
+func (c *Connector) SetCompressionThreshold(threshold int) error {
+	if !c.isPhase(shutDown) {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.compressionThreshold = max(threshold, 0)
+	return nil
+}
+
+// negotiateEncoding returns the first of the accepted encodings that the connector supports, or an empty string.
+func negotiateEncoding(accepted []string) string {
+	for _, enc := range accepted {
+		if slices.Contains(compressionEncodings, enc) {
+			return enc
+		}
+	}
+	return ""
+}
+
+// compressBody compresses the body with the encoding.
+func compressBody(encoding string, body []byte) (compressed []byte, err error) {
+	switch encoding {
+	case "zstd":
+		return zstdEncoder().EncodeAll(body, make([]byte, 0, len(body)/4)), nil
+	case "br":
+		var buf bytes.Buffer
+		buf.Grow(len(body) / 4)
+		bw := brotli.NewWriterLevel(&buf, 5)
+		_, err = bw.Write(body)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		err = bw.Close()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.New("unsupported encoding '%s'", encoding)
+	}
+}
+
+// decompressBody decompresses the body that was compressed with the encoding.
+func decompressBody(encoding string, body []byte) (decompressed []byte, err error) {
+	switch encoding {
+	case "zstd":
+		decompressed, err = zstdDecoder().DecodeAll(body, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return decompressed, nil
+	case "br":
+		var buf bytes.Buffer
+		n, err := io.Copy(&buf, io.LimitReader(brotli.NewReader(bytes.NewReader(body)), maxDecompressedSize+1))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n > maxDecompressedSize {
+			return nil, errors.New("decompressed body too large", http.StatusRequestEntityTooLarge)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, errors.New("unsupported encoding '%s'", encoding, http.StatusUnsupportedMediaType)
+	}
+}
+
+// compressPayload compresses the body with the encoding if it is large enough and if compression shrinks it meaningfully.
+// The ratio of compression is recorded as a metric.
+func (c *Connector) compressPayload(ctx context.Context, direction string, encoding string, body []byte) (compressed []byte, ok bool, err error) {
+	if c.compressionThreshold <= 0 || encoding == "" || len(body) < c.compressionThreshold {
+		return nil, false, nil
+	}
+	compressed, err = compressBody(encoding, body)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	ratio := float64(len(compressed)) / float64(len(body))
+	_ = c.RecordHistogram(
+		ctx,
+		"microbus_compression_ratio",
+		ratio,
+		"direction", direction,
+		"encoding", encoding,
+	)
+	if ratio > 1-minCompressionSavings {
+		return nil, false, nil
+	}
+	return compressed, true, nil
+}
+
+// compressResponse compresses the body of the outgoing response if the caller accepts compression.
+func (c *Connector) compressResponse(ctx context.Context, accepted []string, httpRes *http.Response) error {
+	if len(accepted) == 0 {
+		// The caller does not support compression
+		return nil
+	}
+	if c.compressionThreshold <= 0 || httpRes.Header.Get("Content-Encoding") != "" || httpRes.ContentLength < int64(c.compressionThreshold) {
+		return nil
+	}
+	br, ok := httpRes.Body.(*httpx.BodyReader)
+	if !ok {
+		return nil
+	}
+	encoding := negotiateEncoding(accepted)
+	compressed, ok, err := c.compressPayload(ctx, "response", encoding, br.Bytes())
+	if err != nil || !ok {
+		return errors.Trace(err)
+	}
+	httpRes.Body = httpx.NewBodyReader(compressed)
+	httpRes.ContentLength = int64(len(compressed))
+	httpRes.Header.Set("Content-Length", strconv.Itoa(len(compressed)))
+	frame.Of(httpRes).SetContentEncoding(encoding)
+	return nil
+}
+
+// decompressResponse decompresses the body of the incoming response, if it was compressed by the transport.
+func (c *Connector) decompressResponse(res *http.Response) error {
+	encoding := frame.Of(res).ContentEncoding()
+	if encoding == "" {
+		return nil
+	}
+	body, err := bodyOf(res)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	body, err = decompressBody(encoding, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	res.Body = httpx.NewBodyReader(body)
+	res.ContentLength = int64(len(body))
+	res.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	frame.Of(res).SetContentEncoding("")
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_CompressionCodecs(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	body := []byte(strings.Repeat(`{"name":"Harry","house":"Gryffindor"},`, 1000))
+	for _, enc := range compressionEncodings {
+		compressed, err := compressBody(enc, body)
+		if assert.NoError(err) {
+			assert.True(len(compressed) < len(body)/10, "%s", enc)
+			decompressed, err := decompressBody(enc, compressed)
+			assert.NoError(err)
+			assert.Equal(body, decompressed)
+		}
+	}
+	_, err := compressBody("gzip", body)
+	assert.Error(err)
+	_, err = decompressBody("zstd", []byte("not compressed"))
+	assert.Error(err)
+
+	assert.Equal("zstd", negotiateEncoding([]string{"gzip", "zstd", "br"}))
+	assert.Equal("br", negotiateEncoding([]string{"br"}))
+	assert.Equal("", negotiateEncoding([]string{"gzip"}))
+	assert.Equal("", negotiateEncoding(nil))
+}
+
+func TestConnector_Compression(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	large := strings.Repeat(`{"name":"Hermione","house":"Gryffindor"},`, 4000)
+	random := make([]byte, 64<<10)
+	rand.Read(random)
+
+	var lastReqLen int
+	var lastReqEncoding string
+	server := New("server.compression.connector")
+	server.Subscribe("Echo",
+		func(w http.ResponseWriter, r *http.Request) error {
+			body, _ := io.ReadAll(r.Body)
+			lastReqLen = len(body)
+			lastReqEncoding = frame.Of(r).ContentEncoding()
+			w.Write(body)
+			return nil
+		},
+		sub.At("POST", "/echo"),
+		sub.Web(),
+	)
+	server.Subscribe("Large",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(large))
+			return nil
+		},
+		sub.At("GET", "/large"),
+		sub.Web(),
+	)
+	server.Subscribe("Random",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Write(random)
+			return nil
+		},
+		sub.At("GET", "/random"),
+		sub.Web(),
+	)
+	client := New("client.compression.connector")
+
+	err := server.Startup(ctx)
+	assert.NoError(err)
+	defer server.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// The response is compressed and transparently decompressed
+	res, err := client.Request(ctx, pub.GET("https://server.compression.connector/large"))
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(large, string(body))
+		assert.Equal(int64(len(large)), res.ContentLength)
+		assert.Equal("", frame.Of(res).ContentEncoding())
+		assert.Equal("application/json", res.Header.Get("Content-Type"))
+	}
+
+	// Requests are not compressed, only their responses are
+	res, err = client.Request(ctx, pub.POST("https://server.compression.connector/echo"), pub.Body(large))
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(large, string(body))
+		assert.Equal(len(large), lastReqLen)
+		assert.Equal("", lastReqEncoding)
+	}
+
+	// Incompressible payloads are sent as is
+	res, err = client.Request(ctx, pub.GET("https://server.compression.connector/random"))
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.True(bytes.Equal(random, body))
+	}
+
+	// Small payloads are not compressed
+	res, err = client.Request(ctx, pub.POST("https://server.compression.connector/echo"), pub.Body("small"))
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal("small", string(body))
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// concurrencyLimiter limits the number of requests that a subscription processes concurrently.
+// Requests in excess of the limit wait for a slot in a bounded queue.
+type concurrencyLimiter struct {
+	slots    chan struct{}
+	maxQueue int32
+	waiting  atomic.Int32
+}
+
+// newConcurrencyLimiter returns a limiter for the subscription, or nil if it does not limit its concurrency.
+func newConcurrencyLimiter(s *sub.Subscription) *concurrencyLimiter {
+	if s.MaxConcurrency <= 0 {
+		return nil
+	}
+	return &concurrencyLimiter{
+		slots:    make(chan struct{}, s.MaxConcurrency),
+		maxQueue: int32(max(s.WaitQueue, 0)),
+	}
+}
+
+// saturated indicates if both the slots and the wait queue are full.
+// It is advisory only because the state may change as soon as it is returned.
+func (l *concurrencyLimiter) saturated() bool {
+	return len(l.slots) == cap(l.slots) && l.waiting.Load() >= l.maxQueue
+}
+
+// acquire obtains a processing slot, waiting in the queue if necessary.
+// A 503 error is returned if the queue is full or if the context is done before a slot frees up.
+// Waiting requests obtain slots in order of arrival.
+func (l *concurrencyLimiter) acquire(ctx context.Context) (waited time.Duration, err error) {
+	select {
+	case l.slots <- struct{}{}:
+		return 0, nil
+	default:
+	}
+	if l.waiting.Add(1) > l.maxQueue {
+		l.waiting.Add(-1)
+		return 0, errors.New("saturated", http.StatusServiceUnavailable)
+	}
+	defer l.waiting.Add(-1)
+	t0 := time.Now()
+	select {
+	case l.slots <- struct{}{}:
+		return time.Since(t0), nil
+	case <-ctx.Done():
+		return time.Since(t0), errors.New("saturated", http.StatusServiceUnavailable)
+	}
+}
+
+// release returns a processing slot obtained by acquire.
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// inFlight returns the number of requests being processed and the number of requests waiting in the queue.
+func (l *concurrencyLimiter) inFlight() (processing int, waiting int) {
+	return len(l.slots), int(l.waiting.Load())
+}
+
+// recordConcurrency records the saturation gauges of the subscription.
+func (c *Connector) recordConcurrency(ctx context.Context, s *sub.Subscription, l *concurrencyLimiter) {
+	processing, waiting := l.inFlight()
+	_ = c.RecordGauge(
+		ctx,
+		"microbus_server_concurrent_requests",
+		float64(processing),
+		"name", s.Name,
+		"route", s.Path,
+		"canonical", s.Canonical(),
+		"port", s.Port,
+	)
+	_ = c.RecordGauge(
+		ctx,
+		"microbus_server_queued_requests",
+		float64(waiting),
+		"name", s.Name,
+		"route", s.Path,
+		"canonical", s.Canonical(),
+		"port", s.Port,
+	)
+}
+
+// recordShed counts a request shed by a saturated subscription.
+// The reason is either "noack" if the request was not acked, "rejected" if the wait queue was full,
+// or "timeout" if the time budget of the request ran out while waiting in the queue.
+func (c *Connector) recordShed(ctx context.Context, s *sub.Subscription, reason string) {
+	_ = c.IncrementCounter(
+		ctx,
+		"microbus_server_shed_requests",
+		1,
+		"name", s.Name,
+		"route", s.Path,
+		"canonical", s.Canonical(),
+		"port", s.Port,
+		"reason", reason,
+	)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_MaxConcurrency(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var running atomic.Int32
+	var maxRunning atomic.Int32
+	entered := make(chan bool, 8)
+	unblock := make(chan bool)
+	con := New("max.concurrency.connector")
+	con.Subscribe("Block",
+		func(w http.ResponseWriter, r *http.Request) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			entered <- true
+			<-unblock
+			return nil
+		},
+		sub.At("GET", "/block"),
+		sub.Web(),
+		sub.MaxConcurrency(2),
+		sub.WaitQueue(1),
+	)
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// Occupy both processing slots
+	var wg sync.WaitGroup
+	results := make(chan error, 3)
+	for range 2 {
+		wg.Go(func() {
+			_, err := con.Request(ctx, pub.GET("https://max.concurrency.connector/block"))
+			results <- err
+		})
+	}
+	<-entered
+	<-entered
+
+	// The third request waits in the queue
+	wg.Go(func() {
+		_, err := con.Request(ctx, pub.GET("https://max.concurrency.connector/block"))
+		results <- err
+	})
+	limiter, _ := con.limiters.Load("Block")
+	for range 100 {
+		if _, waiting := limiter.inFlight(); waiting == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, waiting := limiter.inFlight()
+	assert.Equal(1, waiting)
+
+	// The fourth request is shed because the queue is full
+	t0 := time.Now()
+	_, err = con.Request(ctx, pub.GET("https://max.concurrency.connector/block"))
+	if assert.Error(err) {
+		assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	}
+	assert.True(time.Since(t0) < time.Second)
+
+	// Releasing the handlers lets the queued request through
+	close(unblock)
+	wg.Wait()
+	close(results)
+	for err := range results {
+		assert.NoError(err)
+	}
+	assert.Equal(int32(2), maxRunning.Load())
+}
+
+func TestConnector_MaxConcurrencyQueueTimeout(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	entered := make(chan bool, 1)
+	unblock := make(chan bool)
+	con := New("max.concurrency.queue.timeout.connector")
+	con.Subscribe("Block",
+		func(w http.ResponseWriter, r *http.Request) error {
+			entered <- true
+			<-unblock
+			return nil
+		},
+		sub.At("GET", "/block"),
+		sub.Web(),
+		sub.MaxConcurrency(1),
+		sub.WaitQueue(1),
+	)
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	go con.Request(ctx, pub.GET("https://max.concurrency.queue.timeout.connector/block"))
+	<-entered
+
+	// A queued request whose time budget runs out while waiting is shed
+	_, err = con.Request(ctx,
+		pub.GET("https://max.concurrency.queue.timeout.connector/block"),
+		pub.Timeout(500*time.Millisecond),
+	)
+	if assert.Error(err) {
+		assert.True(errors.StatusCode(err) == http.StatusServiceUnavailable || errors.StatusCode(err) == http.StatusRequestTimeout)
+	}
+	close(unblock)
+}
+
+func TestConnector_MaxConcurrencyNoAck(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	entered := make(chan bool, 1)
+	unblock := make(chan bool)
+	saturated := New("max.concurrency.no.ack.connector")
+	saturated.Subscribe("Block",
+		func(w http.ResponseWriter, r *http.Request) error {
+			entered <- true
+			<-unblock
+			return nil
+		},
+		sub.At("GET", "/block"),
+		sub.Web(),
+		sub.NoQueue(),
+		sub.MaxConcurrency(1),
+	)
+	free := New("max.concurrency.no.ack.connector")
+	free.Subscribe("Block",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return nil
+		},
+		sub.At("GET", "/block"),
+		sub.Web(),
+		sub.NoQueue(),
+	)
+	client := New("client.max.concurrency.no.ack.connector")
+
+	err := saturated.Startup(ctx)
+	assert.NoError(err)
+	defer saturated.Shutdown(ctx)
+	err = free.Startup(ctx)
+	assert.NoError(err)
+	defer free.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// Saturate the first replica
+	go client.Request(ctx, pub.GET("https://"+saturated.ID()+".max.concurrency.no.ack.connector/block"))
+	<-entered
+
+	// The saturated replica declines to ack the multicast, so only the free replica responds
+	count := 0
+	for r := range client.Publish(ctx, pub.GET("https://max.concurrency.no.ack.connector/block"), pub.Multicast()) {
+		res, err := r.Get()
+		if assert.NoError(err) {
+			assert.Equal(http.StatusOK, res.StatusCode)
+			assert.Equal(free.ID(), frame.Of(res).FromID())
+		}
+		count++
+	}
+	assert.Equal(1, count)
+	close(unblock)
+}
@@ -161,7 +161,19 @@ func (c *Connector) logConfigs(ctx context.Context) {
 	}
 }
 
+// connectorConfigNames returns the names of the config properties that the connector obtains
+// from the configurator for its own use, alongside those of the microservice.
+func (c *Connector) connectorConfigNames() []string {
+	names := []string{VersionRoutingConfig}
+	if c.deployment != PROD {
+		names = append(names, FaultInjectionConfig)
+	}
+	return names
+}
+
 // refreshConfig contacts the configurator microservices to fetch values for the config properties.
+// The values of the connector's own config properties, such as the version routing and the fault injection rules,
+// are fetched in the same round trip. Invalid values of these are logged and do not fail the refresh.
 func (c *Connector) refreshConfig(ctx context.Context, callback bool) (err error) {
 	if !c.isPhase(startedUp, startingUp) {
 		return errors.New("not started")
@@ -198,36 +210,50 @@ func (c *Connector) refreshConfig(ctx context.Context, callback bool) (err error
 		}
 		count := len(c.configs)
 		c.configLock.Unlock()
-		if count == 0 {
-			return nil
+		if c.hostname != "configurator.core" {
+			req.Names = append(req.Names, c.connectorConfigNames()...)
 		}
-		c.LogDebug(ctx, "Requesting config values",
-			"names", strings.Join(req.Names, " "),
-		)
-		response, err := c.Request(
-			ctx,
-			pub.POST("https://configurator.core:888/values"),
-			pub.Body(req),
-		)
-		if err != nil && errors.StatusCode(err) == http.StatusNotFound {
-			// Backward compatibility
-			response, err = c.Request(
+		if len(req.Names) > 0 {
+			c.LogDebug(ctx, "Requesting config values",
+				"names", strings.Join(req.Names, " "),
+			)
+			response, err := c.Request(
 				ctx,
-				pub.POST("https://configurator.core/values"),
+				pub.POST("https://configurator.core:888/values"),
 				pub.Body(req),
 			)
+			if err != nil && errors.StatusCode(err) == http.StatusNotFound {
+				// Backward compatibility
+				response, err = c.Request(
+					ctx,
+					pub.POST("https://configurator.core/values"),
+					pub.Body(req),
+				)
+			}
+			if err != nil && count == 0 && errors.StatusCode(err) == http.StatusNotFound {
+				// The connector's own config properties are optional and do not require a configurator
+				c.LogDebug(ctx, "No configurator")
+			} else {
+				if err != nil {
+					return errors.Trace(err)
+				}
+				var responseObj struct {
+					Values map[string]string `json:"values"`
+				}
+				err = json.NewDecoder(response.Body).Decode(&responseObj)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				maps.Copy(fetchedValues, responseObj.Values)
+			}
 		}
-		if err != nil {
-			return errors.Trace(err)
-		}
-		var responseObj struct {
-			Values map[string]string `json:"values"`
-		}
-		err = json.NewDecoder(response.Body).Decode(&responseObj)
-		if err != nil {
-			return errors.Trace(err)
+
+		// Apply the connector's own config properties
+		c.applyVersionRouting(ctx, fetchedValues[VersionRoutingConfig])
+		c.applyFaultInjection(ctx, fetchedValues[FaultInjectionConfig])
+		if count == 0 {
+			return nil
 		}
-		maps.Copy(fetchedValues, responseObj.Values)
 	}
 
 	c.configLock.Lock()
@@ -351,8 +377,13 @@ func (c *Connector) readConfigFile(ctx context.Context) (values map[string]strin
 	values = map[string]string{}
 	hostname := strings.ToLower(c.Hostname())
 	c.configLock.Lock()
+	var names []string
 	for _, config := range c.configs {
-		name := config.Name
+		names = append(names, config.Name)
+	}
+	c.configLock.Unlock()
+	names = append(names, c.connectorConfigNames()...)
+	for _, name := range names {
 		var value string
 		var ok bool
 		if amalgamated["all"] != nil {
@@ -371,6 +402,5 @@ func (c *Connector) readConfigFile(ctx context.Context) (values map[string]strin
 			values[name] = value
 		}
 	}
-	c.configLock.Unlock()
 	return values, nil
 }
@@ -18,6 +18,7 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"os"
 	"testing"
@@ -339,6 +340,10 @@ func TestConnector_ReadFromFile(t *testing.T) {
 	assert.NoError(err)
 	defer con.Shutdown(ctx)
 
+	con.versionRoutesLock.RLock()
+	assert.Len(con.versionRoutes["payments.example"], 2, "Version routing should be read from file")
+	con.versionRoutesLock.RUnlock()
+
 	assert.Expect(
 		con.Config("SubDir"), "Child Subdomain",
 		con.Config("case"), "lowercase",
@@ -349,3 +354,66 @@ func TestConnector_ReadFromFile(t *testing.T) {
 		con.Config("Undefined"), "",
 	)
 }
+
+func TestConnector_FetchConnectorConfigs(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	plane := utils.RandomIdentifier(12)
+
+	// Mock a config service
+	mockCfg := New("configurator.core")
+	mockCfg.SetDeployment(LAB) // Configs are disabled in TESTING
+	mockCfg.SetPlane(plane)
+	routing := "payments.example=7:5,*:95"
+	mockCfg.Subscribe("Values",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"values": map[string]string{
+					VersionRoutingConfig: routing,
+					FaultInjectionConfig: "payments.example=error:503",
+				},
+			})
+			return nil
+		},
+		sub.At("POST", ":888/values"),
+		sub.Web(),
+	)
+
+	// A microservice without a configurator starts up without its own config properties
+	alone := New("alone.fetch.connector.configs.connector")
+	alone.SetDeployment(LAB)
+	alone.SetPlane(plane)
+	err := alone.Startup(ctx)
+	if assert.NoError(err) {
+		alone.Shutdown(ctx)
+	}
+
+	err = mockCfg.Startup(ctx)
+	assert.NoError(err)
+	defer mockCfg.Shutdown(ctx)
+
+	// The connector's own config properties are fetched even if the microservice defines no configs
+	con := New("fetch.connector.configs.connector")
+	con.SetDeployment(LAB) // Configs are disabled in TESTING
+	con.SetPlane(plane)
+	err = con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	con.versionRoutesLock.RLock()
+	assert.Len(con.versionRoutes["payments.example"], 2)
+	con.versionRoutesLock.RUnlock()
+	httpReq, _ := http.NewRequest("GET", "https://payments.example/charge", nil)
+	assert.NotNil(con.injectedFault(faultError, httpReq))
+
+	// Invalid values are logged and do not fail the refresh
+	routing = "payments.example=x"
+	_, err = mockCfg.GET(ctx, "https://fetch.connector.configs.connector:888/config-refresh")
+	assert.NoError(err)
+	con.versionRoutesLock.RLock()
+	assert.Len(con.versionRoutes["payments.example"], 2)
+	con.versionRoutesLock.RUnlock()
+}
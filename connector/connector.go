@@ -66,6 +66,7 @@ type Connector struct {
 
 	onStartup       service.StartupHandler
 	onShutdown      service.ShutdownHandler
+	onHealthCheck   service.HealthCheckHandler
 	lifetimeCtx     context.Context
 	ctxCancel       context.CancelFunc
 	pendingOps      atomic.Int32
@@ -81,14 +82,18 @@ type Connector struct {
 	onObserveMetrics  service.ObserveMetricsHandler
 	meterOTLPKey      string
 
-	traceProvider  *sdktrace.TracerProvider
-	tracer         trace.Tracer
-	traceProcessor *selectiveProcessor
-	traceOTLPKey   string
+	traceProvider    *sdktrace.TracerProvider
+	tracer           trace.Tracer
+	traceProcessor   *selectiveProcessor
+	traceOTLPKey     string
+	traceLatency     time.Duration
+	traceRoutes      []string
+	traceRoutesRatio float64
 
 	transportConn transport.Conn
 	responseSub   *transport.Subscription
 	subs          utils.SyncMap[string, *sub.Subscription]
+	limiters      utils.SyncMap[string, *concurrencyLimiter]
 	phase         atomic.Int32
 	plane         string
 	controlSubs   bool
@@ -104,22 +109,54 @@ type Connector struct {
 
 	requestDefrags  *lru.Cache[string, *httpx.DefragRequest]
 	responseDefrags *lru.Cache[string, *httpx.DefragResponse]
+	responseStreams utils.SyncMap[string, *responseStream]
+
+	compressionThreshold int
 
 	knownResponders *lru.Cache[string, map[string]bool]
 	postRequestData *lru.Cache[string, string]
 	localResponder  *lru.Cache[string, string]
 
+	circuitBreaker  CircuitBreaker
+	circuitBreakers *lru.Cache[string, *circuitBreakerState]
+	responseCache   *lru.Cache[string, *cachedResponse]
+
+	versionRoutes        map[string][]versionWeight
+	versionRoutingConfig string
+	versionRoutesLock    sync.RWMutex
+
+	faultRules  []*faultRule
+	faultConfig string
+	faultLock   sync.RWMutex
+
+	inflight     map[string][]*inflightHandler
+	inflightLock sync.Mutex
+	earlyCancels *lru.Cache[string, string]
+
+	idempotentFlights map[string]*idempotentFlight
+	idempotentLock    sync.Mutex
+
+	coalescedFlights map[string]*coalescedFlight
+	coalesceLock     sync.Mutex
+
+	clientInterceptors []ClientInterceptor
+	serverInterceptors []ServerInterceptor
+
 	configs         map[string]*cfg.Config
 	configLock      sync.Mutex
 	onConfigChanged service.ConfigChangedHandler
 
 	logger      *slog.Logger
 	logDebug    bool
+	logOverride atomic.Pointer[logLevelOverride]
+	logSampler  *logSampler
 	logProvider *sdklog.LoggerProvider
 	logOTLPKey  string
 
 	tickers     map[string]*tickerCallback
 	tickersLock sync.Mutex
+	lease       leaderLease
+	leaseTTL    time.Duration
 
 	distribCache *dlru.Cache
 	resourcesFS  fs.FS
@@ -141,16 +178,24 @@ func NewConnector() *Connector {
 		ackTimeout:        300 * time.Millisecond,
 		maxCallDepth:      64,
 		tickers:           map[string]*tickerCallback{},
+		leaseTTL:          15 * time.Second,
 		lifetimeCtx:       context.Background(),
 		knownResponders:   lru.New[string, map[string]bool](64<<10, 24*time.Hour), // 64KB
 		postRequestData:   lru.New[string, string](256<<10, time.Minute),          // 256KB
 		localResponder:    lru.New[string, string](64<<10, 24*time.Hour),          // 64KB
+		circuitBreakers:   lru.New[string, *circuitBreakerState](4096, time.Hour), // 4096 destinations
+		responseCache:     lru.New[string, *cachedResponse](32<<20, time.Hour),    // 32MB
+		inflight:          map[string][]*inflightHandler{},
+		idempotentFlights: map[string]*idempotentFlight{},
+		coalescedFlights:  map[string]*coalescedFlight{},
+		earlyCancels:      lru.New[string, string](4096, time.Minute), // 4096 cancellations
 		multicastChanCap:  32,
 		metricInstruments: map[string]*metricInstrument{},
 		requestDefrags:    lru.New[string, *httpx.DefragRequest](1<<10, time.Minute),  // 1024 fragmented requests
 		responseDefrags:   lru.New[string, *httpx.DefragResponse](1<<10, time.Minute), // 1024 fragmented responses
 		maxFragmentSize:   1 << 20,                                                    // 1MB
 	}
+	c.compressionThreshold = defaultCompressionThreshold
 	c.SetResFSDir(".")
 	return c
 }
@@ -175,8 +220,8 @@ func (c *Connector) ID() string {
 
 // SetHostname sets the hostname of the microservice.
 // The hostname must be a canonical Microbus identity per [httpx.ValidateHostname]:
-// lowercase letters, digits, dots, and hyphens; no underscores, no "id-" or "loc-"
-// first segment, not "all" or "*.all", no leading/trailing whitespace.
+// lowercase letters, digits, dots, and hyphens; no underscores, no "id-" or "loc-" prefix or
+// "ver-N" version slot in the first segment, not "all" or "*.all", no leading/trailing whitespace.
 // For example, this.is.a.valid.host-name.123.local
 func (c *Connector) SetHostname(hostname string) error {
 	if !c.isPhase(shutDown) {
@@ -286,6 +331,19 @@ func (c *Connector) SetPlane(plane string) error {
 	return nil
 }
 
+// SetNATS sets the URL of the NATS cluster that the microservice connects to,
+// overriding the MICROBUS_NATS env var.
+// A microservice connected to an explicit NATS URL does not communicate with other microservices
+// in the same process over the short-circuit, nor over the mesh.
+// Setting an empty value will clear this override
+func (c *Connector) SetNATS(natsURL string) error {
+	if !c.isPhase(shutDown) {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.transportConn.SetNATS(natsURL)
+	return nil
+}
+
 // SetLocality sets the geographic locality of the microservice which is used to optimize routing.
 // Localities are hierarchical with the broadest identifier first, separated by hyphens, similar to AWS region/AZ
 // identifiers such as "us-west-b-1" or arbitrarily "europe-italy-rome".
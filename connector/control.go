@@ -22,10 +22,12 @@ import (
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
 	"github.com/microbus-io/fabric/openapi"
 	"github.com/microbus-io/fabric/sub"
 	"github.com/microbus-io/fabric/utils"
@@ -50,6 +52,12 @@ func (c *Connector) subscribeControl() (err error) {
 		{name: "Trace", route: ":888/trace", handler: c.handleTrace, options: []sub.Option{sub.NoQueue()}},
 		{name: "OnNewSubs", route: ":888/on-new-subs", handler: c.handleOnNewSubs, options: []sub.Option{sub.NoQueue(), sub.NoTrace()}},
 		{name: "OpenAPI", route: ":888/openapi.json", handler: c.handleOpenAPI, options: []sub.Option{sub.DefaultQueue(), sub.Method("GET")}},
+		{name: "Lease", route: ":888/lease", handler: c.handleControlLease, options: []sub.Option{sub.NoQueue(), sub.NoTrace()}},
+		{name: "Leader", route: ":888/leader", handler: c.handleControlLeader, options: []sub.Option{sub.DefaultQueue()}},
+		{name: "InjectFaults", route: ":888/inject-faults", handler: c.handleControlInjectFaults, options: []sub.Option{sub.NoQueue(), sub.Method("POST"), sub.RequiredClaims(faultInjectionClaims())}},
+		{name: "LogLevel", route: ":888/log-level", handler: c.handleControlLogLevel, options: []sub.Option{sub.NoQueue(), sub.Method("POST"), sub.RequiredClaims(logLevelClaims())}},
+		{name: "Health", route: ":888/health", handler: c.handleControlHealth, options: []sub.Option{sub.NoQueue(), sub.NoTrace()}},
+		{name: "Pprof", route: ":888/pprof/{profile}", handler: c.handleControlPprof, options: []sub.Option{sub.NoQueue(), sub.Method("GET"), sub.RequiredClaims(pprofClaims())}},
 	}
 	var registered []string
 	rollback := func() {
@@ -143,6 +151,25 @@ func (c *Connector) handleOpenAPI(w http.ResponseWriter, r *http.Request) error
 			OutputArgs:  s.Outputs,
 		})
 	}
+	c.tickersLock.Lock()
+	for _, job := range c.tickers {
+		t := &openapi.Ticker{
+			Name:      job.Name,
+			Singleton: job.Singleton,
+			NextRun:   job.NextRun,
+		}
+		if job.Schedule != nil {
+			t.Schedule = job.Schedule.String()
+		} else {
+			t.Interval = job.Interval.String()
+		}
+		oapiSvc.Tickers = append(oapiSvc.Tickers, t)
+	}
+	c.tickersLock.Unlock()
+	sort.Slice(oapiSvc.Tickers, func(i, j int) bool {
+		return oapiSvc.Tickers[i].Name < oapiSvc.Tickers[j].Name
+	})
+
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Cache-Control", "private, no-store")
 	encoder := json.NewEncoder(w)
@@ -256,7 +283,7 @@ func (c *Connector) handleOnNewSubs(w http.ResponseWriter, r *http.Request) erro
 	var payload struct {
 		Hosts []string `json:"hosts"`
 	}
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	err := httpx.ParseRequestBody(r, &payload)
 	if err != nil {
 		return errors.Trace(err)
 	}
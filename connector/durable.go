@@ -0,0 +1,194 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/transport"
+	"github.com/microbus-io/fabric/utils"
+)
+
+// defaultMaxDeliveries is the number of times a durable event is delivered to a subscription
+// that fails to process it, before the event is dead-lettered.
+const defaultMaxDeliveries = 5
+
+// durableStreamOf returns the name of the stream that persists the durable events fired to the endpoint,
+// and the subject captured by the stream. The subject matches requests from any source with any method.
+func durableStreamOf(plane, port, hostname, path string) (stream string, subject string) {
+	subject = SubjectOfRequestSub(plane, port, hostname, "", "ANY", path)
+	hash := sha256.Sum256([]byte(subject))
+	return "microbus_" + hex.EncodeToString(hash[:12]), subject
+}
+
+// deadLetterStreamOf returns the name of the stream that retains the dead-lettered durable events of the plane,
+// and the subject to which the events that exhausted their deliveries to the named consumer are published.
+func deadLetterStreamOf(plane, consumer string) (stream string, subject string) {
+	return "microbus_deadletter_" + plane, plane + ".deadletter." + consumer
+}
+
+// durableConsumerOf returns the name of the consumer of the durable events delivered to the subscription.
+// Replicas that share the queue of the subscription share the consumer, and therefore the events.
+func (c *Connector) durableConsumerOf(s *sub.Subscription, streamSubject string) string {
+	queue := s.Queue
+	if queue == "" {
+		queue = c.id + "." + c.hostname
+	}
+	hash := sha256.Sum256([]byte(streamSubject + "|" + queue))
+	return "microbus_" + hex.EncodeToString(hash[:12])
+}
+
+// publishDurable persists a multicast request in the stream of the endpoint, to be delivered
+// at least once to each of its durable subscribers. No responses are returned.
+func (c *Connector) publishDurable(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+	// Buffer the body, because durable events are not fragmented
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			err = errors.Trace(err, c.Span(ctx).TraceID())
+			return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+		}
+	}
+	if int64(len(body)) > c.maxFragmentSize {
+		err := errors.New("durable event too large: %s", req.Canonical(), http.StatusRequestEntityTooLarge, c.Span(ctx).TraceID())
+		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+	}
+	httpReq, err := http.NewRequest(req.Method, req.URL, httpx.NewBodyReader(body))
+	if err != nil {
+		err = errors.Trace(err, c.Span(ctx).TraceID())
+		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+	}
+	for name, value := range req.Header {
+		httpReq.Header[name] = value
+	}
+	httpReq.ContentLength = int64(len(body))
+	if len(httpReq.Header.Values("User-Agent")) == 0 {
+		httpReq.Header.Set("User-Agent", "")
+	}
+	msgID := utils.RandomIdentifier(16)
+	frame.Of(httpReq).SetMessageID(msgID)
+
+	port := "443"
+	if httpReq.URL.Scheme == "http" {
+		port = "80"
+	}
+	if httpReq.URL.Port() != "" {
+		port = httpReq.URL.Port()
+	}
+	host, _ := cutIDOrLocality(httpReq.URL.Hostname())
+	subject := SubjectOfRequest(c.plane, port, c.hostname, host, "", httpReq.Method, httpReq.URL.Path)
+	stream, streamSubject := durableStreamOf(c.plane, port, host, httpReq.URL.Path)
+
+	c.LogDebug(ctx, "Durable request",
+		"msg", msgID,
+		"url", req.Canonical(),
+		"method", req.Method,
+	)
+	err = c.transportConn.DurablePublish(ctx, stream, streamSubject, subject, msgID, httpReq)
+	if err != nil {
+		err = errors.Trace(err, http.StatusServiceUnavailable, c.Span(ctx).TraceID())
+		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+	}
+	return func(yield func(*pub.Response) bool) {}
+}
+
+// activateDurableSub consumes the durable events delivered to the subscription from the stream of its endpoint.
+func (c *Connector) activateDurableSub(s *sub.Subscription) (err error) {
+	budget := c.defaultTimeBudget
+	if s.TimeBudget > 0 {
+		budget = s.TimeBudget
+	}
+	budget = min(budget, c.maxTimeBudget)
+	stream, streamSubject := durableStreamOf(c.plane, s.Port, s.Host, s.Path)
+	consumer := c.durableConsumerOf(s, streamSubject)
+	deadLetterStream, deadLetterSubject := deadLetterStreamOf(c.plane, consumer)
+	cfg := transport.DurableConfig{
+		Stream:            stream,
+		Subject:           streamSubject,
+		Consumer:          consumer,
+		MaxDeliver:        defaultMaxDeliveries,
+		AckWait:           budget + c.networkRoundtrip,
+		DeadLetterStream:  deadLetterStream,
+		DeadLetterSubject: deadLetterSubject,
+	}
+	if s.MaxDeliveries > 0 {
+		cfg.MaxDeliver = s.MaxDeliveries
+	}
+	if s.MaxConcurrency > 0 {
+		cfg.MaxConcurrency = s.MaxConcurrency
+	}
+	if s.Queue == "" {
+		// The consumer of a replica that is no longer running is deleted
+		cfg.InactiveThreshold = time.Hour
+	}
+	ctx, cancel := context.WithTimeout(c.Lifetime(), 10*time.Second)
+	defer cancel()
+	transportSub, err := c.transportConn.DurableSubscribe(ctx, cfg, func(msg *transport.Msg) error {
+		return c.onDurableRequest(msg, s, budget)
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.Subs = append(s.Subs, transportSub)
+	return nil
+}
+
+// onDurableRequest is called when a durable event is delivered to the subscription from the stream.
+// The handler of the subscription is called but no response is sent to the publisher of the event.
+// Returning an error causes the event to be redelivered.
+func (c *Connector) onDurableRequest(msg *transport.Msg, s *sub.Subscription, budget time.Duration) (err error) {
+	c.pendingOps.Add(1)
+	defer c.pendingOps.Add(-1)
+
+	msg.Request, err = http.ReadRequest(bufio.NewReaderSize(bytes.NewReader(msg.Data), 64))
+	if err != nil {
+		// Redelivering a malformed message is futile
+		c.LogError(c.Lifetime(), "Parsing durable request", "error", errors.Trace(err))
+		return nil
+	}
+	if !validRequestMethods[msg.Request.Method] || !strings.EqualFold(s.Method, "ANY") && msg.Request.Method != s.Method {
+		c.LogDebug(c.Lifetime(), "Ignoring durable request",
+			"method", msg.Request.Method,
+			"url", msg.Request.URL.String(),
+		)
+		return nil
+	}
+
+	// Overwrite From-Host with the verified source from the subject.
+	_, _, _, src, _, _ := splitSubject(msg.Subject)
+	frame.Of(msg.Request).SetFromHost(src)
+	frame.Of(msg.Request).SetTimeBudget(budget)
+
+	err = c.handleRequest(msg, s)
+	return errors.Trace(err)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+)
+
+// startNATS starts an embedded NATS server, with or without JetStream, and returns its URL.
+func startNATS(t *testing.T, jetStream bool) string {
+	t.Helper()
+	srv, err := natsserver.NewServer(&natsserver.Options{
+		Host:      "127.0.0.1",
+		Port:      -1, // random
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: jetStream,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("nats new server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+	return srv.ClientURL()
+}
+
+func TestConnector_DurableWithoutJetStream(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Without a NATS connection, durable events are delivered as regular multicast events
+	con := New("durable.without.jetstream.connector")
+	var received atomic.Int32
+	con.Subscribe("OnEvent",
+		func(w http.ResponseWriter, r *http.Request) error {
+			received.Add(1)
+			w.Write([]byte("ok"))
+			return nil
+		},
+		sub.At("POST", ":417/on-event"),
+		sub.Web(),
+		sub.Durable(),
+		sub.MaxDeliveries(3),
+		sub.NoQueue(),
+	)
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	if con.transportConn.DurableEnabled() {
+		t.Skip("connected to NATS")
+	}
+
+	count := 0
+	for r := range con.Publish(ctx, pub.POST("https://durable.without.jetstream.connector:417/on-event"), pub.Durable()) {
+		_, err := r.Get()
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(1, count)
+	assert.Equal(int32(1), received.Load())
+}
+
+func TestConnector_DurableNATSWithoutJetStream(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+	natsURL := startNATS(t, false)
+
+	// With a NATS connection to a server without JetStream, durable events are delivered as regular multicast events
+	var received atomic.Int32
+	con := New("durable.nats.without.jetstream.connector")
+	con.SetNATS(natsURL)
+	con.Subscribe("OnEvent",
+		func(w http.ResponseWriter, r *http.Request) error {
+			received.Add(1)
+			w.Write([]byte("ok"))
+			return nil
+		},
+		sub.At("POST", ":417/on-event"),
+		sub.Web(),
+		sub.Durable(),
+	)
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	assert.False(con.transportConn.DurableEnabled())
+
+	count := 0
+	for r := range con.Publish(ctx, pub.POST("https://durable.nats.without.jetstream.connector:417/on-event"), pub.Durable()) {
+		_, err := r.Get()
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(1, count)
+	assert.Equal(int32(1), received.Load())
+}
+
+func TestConnector_DurableJetStream(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+	natsURL := startNATS(t, true)
+
+	var received atomic.Int32
+	var failures atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Query().Get("fail") != "" && failures.Add(1) == 1 {
+			return errors.New("failed")
+		}
+		received.Add(1)
+		return nil
+	}
+	newSubscriber := func() *Connector {
+		con := New("durable.jetstream.connector")
+		con.SetNATS(natsURL)
+		con.Subscribe("OnEvent", handler,
+			sub.At("POST", ":417/on-event"),
+			sub.Web(),
+			sub.Durable(),
+		)
+		return con
+	}
+	subscriber := newSubscriber()
+	err := subscriber.Startup(ctx)
+	assert.NoError(err)
+	publisher := New("publisher.durable.jetstream.connector")
+	publisher.SetNATS(natsURL)
+	err = publisher.Startup(ctx)
+	assert.NoError(err)
+	defer publisher.Shutdown(ctx)
+	assert.True(publisher.transportConn.DurableEnabled())
+
+	publish := func(query string) {
+		count := 0
+		for r := range publisher.Publish(ctx, pub.POST("https://durable.jetstream.connector:417/on-event"+query), pub.Durable()) {
+			_, err := r.Get()
+			assert.NoError(err)
+			count++
+		}
+		assert.Zero(count) // No responses are returned
+	}
+	waitFor := func(n int32) bool {
+		for range 200 {
+			if received.Load() >= n {
+				return true
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return false
+	}
+
+	// The event is delivered to the durable subscriber
+	publish("")
+	assert.True(waitFor(1))
+
+	// A failed event is redelivered
+	publish("?fail=1")
+	assert.True(waitFor(2))
+	assert.Equal(int32(2), failures.Load())
+
+	// Events published while the subscriber is not running are delivered once it restarts
+	err = subscriber.Shutdown(ctx)
+	assert.NoError(err)
+	publish("")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(int32(2), received.Load())
+	subscriber = newSubscriber()
+	err = subscriber.Startup(ctx)
+	assert.NoError(err)
+	defer subscriber.Shutdown(ctx)
+	assert.True(waitFor(3))
+}
+
+func TestConnector_DurableNames(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	stream, subject := durableStreamOf("plane", "417", "durable.names.connector", "/on-event")
+	assert.True(strings.HasPrefix(stream, "microbus_"))
+	assert.Equal(SubjectOfRequestSub("plane", "417", "durable.names.connector", "", "ANY", "/on-event"), subject)
+	stream2, _ := durableStreamOf("plane", "417", "durable.names.connector", "/on-other")
+	assert.NotEqual(stream, stream2)
+
+	con := New("durable.names.connector")
+	queued := &sub.Subscription{Queue: "durable.names.connector"}
+	unqueued := &sub.Subscription{}
+	assert.Equal(con.durableConsumerOf(queued, subject), con.durableConsumerOf(queued, subject))
+	assert.NotEqual(con.durableConsumerOf(queued, subject), con.durableConsumerOf(unqueued, subject))
+
+	// Replicas share the consumer only if they share the queue
+	replica := New("durable.names.connector")
+	assert.Equal(con.durableConsumerOf(queued, subject), replica.durableConsumerOf(queued, subject))
+	assert.NotEqual(con.durableConsumerOf(unqueued, subject), replica.durableConsumerOf(unqueued, subject))
+
+	deadStream, deadSubject := deadLetterStreamOf("plane", "consumer")
+	assert.Equal("microbus_deadletter_plane", deadStream)
+	assert.Equal("plane.deadletter.consumer", deadSubject)
+}
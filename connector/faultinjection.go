@@ -0,0 +1,385 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+// FaultInjectionConfig is the name of the config property that the connector obtains from the configurator
+// to inject faults into the requests it handles.
+// The value follows the format of [Connector.SetFaultInjection], for example:
+//
+//	all:
+//	  Microbus.FaultInjection: payments.example:443/charge POST=latency:2s@50%,error:503@10%
+const FaultInjectionConfig = "Microbus.FaultInjection"
+
+// defaultFaultInjectionClaims are the actor claims required of requests to the :888/inject-faults control endpoint,
+// unless overridden by the MICROBUS_FAULT_INJECTION_CLAIMS env var.
+const defaultFaultInjectionClaims = "roles.admin"
+
+// Kinds of faults that can be injected.
+const (
+	faultLatency  = "latency"
+	faultError    = "error"
+	faultDropAck  = "dropack"
+	faultTruncate = "truncate"
+)
+
+// fault is a single fault to inject into a matching request.
+type fault struct {
+	kind        string
+	latency     time.Duration
+	statusCode  int
+	truncate    int
+	probability float64
+}
+
+// faultRule matches requests by their hostname, port, path and method and lists the faults to inject into them.
+type faultRule struct {
+	hostname string
+	port     string
+	path     string
+	method   string
+	faults   []fault
+}
+
+// matches returns true if the rule matches the request.
+func (r *faultRule) matches(hostname string, port string, path string, method string) bool {
+	switch {
+	case r.hostname == "*":
+	case strings.HasPrefix(r.hostname, "*."):
+		if !strings.HasSuffix(hostname, r.hostname[1:]) {
+			return false
+		}
+	case r.hostname != hostname:
+		return false
+	}
+	if r.port != "" && r.port != port {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.path, "*"); ok {
+		if !strings.HasPrefix(path, prefix) {
+			return false
+		}
+	} else if r.path != "" && r.path != path {
+		return false
+	}
+	if r.method != "" && r.method != method {
+		return false
+	}
+	return true
+}
+
+// parseFaultInjection parses fault injection rules in the format of [Connector.SetFaultInjection].
+func parseFaultInjection(rules string) (parsed []*faultRule, err error) {
+	entries := strings.FieldsFunc(rules, func(r rune) bool {
+		return r == ';' || r == '\n'
+	})
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		match, faults, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, errors.New("invalid fault injection rule '%s'", entry)
+		}
+		rule := &faultRule{}
+		fields := strings.Fields(match)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, errors.New("invalid fault injection rule '%s'", entry)
+		}
+		if len(fields) == 2 {
+			rule.method = strings.ToUpper(fields[1])
+			if rule.method == "ANY" || rule.method == "*" {
+				rule.method = ""
+			}
+		}
+		hostPort := fields[0]
+		if i := strings.IndexByte(hostPort, '/'); i >= 0 {
+			hostPort, rule.path = hostPort[:i], hostPort[i:]
+		}
+		hostname, port, _ := strings.Cut(hostPort, ":")
+		rule.hostname = strings.ToLower(hostname)
+		if rule.hostname != "*" {
+			err = httpx.ValidateHostname(strings.TrimPrefix(rule.hostname, "*."))
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if port != "" && port != "*" {
+			p, err := strconv.Atoi(port)
+			if err != nil || p < 1 || p > 65535 {
+				return nil, errors.New("invalid port '%s' of '%s'", port, hostname)
+			}
+			rule.port = port
+		}
+		for f := range strings.SplitSeq(faults, ",") {
+			flt, err := parseFault(strings.TrimSpace(f))
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			rule.faults = append(rule.faults, flt)
+		}
+		parsed = append(parsed, rule)
+	}
+	return parsed, nil
+}
+
+// parseFault parses a single fault in the format kind[:arg][@probability%].
+func parseFault(f string) (flt fault, err error) {
+	flt.probability = 1
+	if spec, prob, ok := strings.Cut(f, "@"); ok {
+		f = spec
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(prob), "%"), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return flt, errors.New("invalid probability '%s' of fault '%s'", prob, f)
+		}
+		flt.probability = pct / 100
+	}
+	kind, arg, _ := strings.Cut(f, ":")
+	flt.kind = strings.ToLower(strings.TrimSpace(kind))
+	arg = strings.TrimSpace(arg)
+	switch flt.kind {
+	case faultLatency:
+		flt.latency, err = time.ParseDuration(arg)
+		if err != nil || flt.latency <= 0 {
+			return flt, errors.New("invalid latency '%s'", arg)
+		}
+	case faultError:
+		flt.statusCode, err = strconv.Atoi(arg)
+		if err != nil || flt.statusCode < 400 || flt.statusCode > 599 {
+			return flt, errors.New("invalid error status code '%s'", arg)
+		}
+	case faultTruncate:
+		flt.truncate, err = strconv.Atoi(arg)
+		if err != nil || flt.truncate < 0 {
+			return flt, errors.New("invalid truncation length '%s'", arg)
+		}
+	case faultDropAck:
+		if arg != "" {
+			return flt, errors.New("unexpected argument '%s' of fault '%s'", arg, flt.kind)
+		}
+	default:
+		return flt, errors.New("unknown fault '%s'", flt.kind)
+	}
+	return flt, nil
+}
+
+/*
+SetFaultInjection injects faults into the requests handled or made by this microservice, in order to test how
+callers behave when their dependencies are slow or failing. Fault injection is not allowed in the PROD deployment.
+
+Each rule matches requests by hostname, port, path and method, and lists the faults to inject into them.
+Rules are separated by a semicolon or a new line. The first rule that matches a request applies,
+and later rules are not consulted for it even if they list faults of a kind that the first rule does not.
+For example, to delay half the charge requests of payments.example by 2 seconds and to fail 10% of them with a 503:
+
+	payments.example:443/charge POST=latency:2s@50%,error:503@10%
+
+The hostname may be * to match any hostname, or start with *. to match any of its subdomains.
+The port, path and method are optional and match any when omitted. A path ending with * matches by prefix.
+The faults are:
+
+  - latency:<duration> delays the processing of the request
+  - error:<status> fails the request with the status code
+  - dropack does not acknowledge the request, causing callers to think there is no responder
+  - truncate:<bytes> cuts the body of the response short
+
+Each fault may be followed by @ and the percentage of requests to inject it into.
+Requests to the control port 888 are not subject to fault injection.
+
+The rules apply to outgoing requests made by this microservice as well. The caller injects latency,
+errors and dropped acks into a matching request before sending it, and marks it so that the responder
+does not inject them again. Truncation is injected by the responder only.
+Outside the TESTING deployment, the rules are also obtained from the configurator via the [FaultInjectionConfig]
+config property and override the ones set by this method when they change.
+*/
+func (c *Connector) SetFaultInjection(rules string) error {
+	if c.deployment == PROD {
+		return errors.New("fault injection is not allowed in %s", PROD, http.StatusForbidden)
+	}
+	parsed, err := parseFaultInjection(rules)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.faultLock.Lock()
+	c.faultRules = parsed
+	c.faultLock.Unlock()
+	return nil
+}
+
+// matchingFaultRule returns the first fault injection rule that matches the request, if any.
+func (c *Connector) matchingFaultRule(httpReq *http.Request) *faultRule {
+	if c.deployment == PROD {
+		return nil
+	}
+	c.faultLock.RLock()
+	rules := c.faultRules
+	c.faultLock.RUnlock()
+	if len(rules) == 0 {
+		return nil
+	}
+	hostname, _ := cutIDOrLocality(strings.ToLower(httpReq.URL.Hostname()))
+	port := httpReq.URL.Port()
+	if port == "" {
+		port = "443"
+		if httpReq.URL.Scheme == "http" {
+			port = "80"
+		}
+	}
+	if port == "888" {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.matches(hostname, port, httpReq.URL.Path, httpReq.Method) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// injectedFault returns the fault of the given kind to inject into the request, if any.
+// Only the first matching rule is consulted. The probability of the fault is rolled on each call.
+func (c *Connector) injectedFault(kind string, httpReq *http.Request) (flt *fault) {
+	rule := c.matchingFaultRule(httpReq)
+	if rule == nil {
+		return nil
+	}
+	for i := range rule.faults {
+		if rule.faults[i].kind == kind && rand.Float64() < rule.faults[i].probability {
+			return &rule.faults[i]
+		}
+	}
+	return nil
+}
+
+// injectLatencyOrError delays the request and fails it if so indicated by the fault injection rules.
+func (c *Connector) injectLatencyOrError(ctx context.Context, httpReq *http.Request) error {
+	if flt := c.injectedFault(faultLatency, httpReq); flt != nil {
+		timer := time.NewTimer(flt.latency)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.New("timeout", http.StatusRequestTimeout)
+		}
+	}
+	if flt := c.injectedFault(faultError, httpReq); flt != nil {
+		return errors.New("injected fault", flt.statusCode)
+	}
+	return nil
+}
+
+// injectOutgoingFault delays the outgoing request or fails it if so indicated by the fault injection rules.
+// A request that matches a rule is marked so that its responder does not inject faults into it again.
+// Dropped indicates that the request should be treated as if no responder acked it.
+func (c *Connector) injectOutgoingFault(ctx context.Context, httpReq *http.Request) (dropped bool, err error) {
+	if c.matchingFaultRule(httpReq) == nil {
+		return false, nil
+	}
+	frame.Of(httpReq).SetFaultsInjected(true)
+	err = c.injectLatencyOrError(ctx, httpReq)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return c.injectedFault(faultDropAck, httpReq) != nil, nil
+}
+
+// injectTruncation cuts the body of the response short if so indicated by the fault injection rules.
+func (c *Connector) injectTruncation(httpReq *http.Request, httpRes *http.Response) {
+	flt := c.injectedFault(faultTruncate, httpReq)
+	if flt == nil {
+		return
+	}
+	br, ok := httpRes.Body.(*httpx.BodyReader)
+	if !ok || len(br.Bytes()) <= flt.truncate {
+		return
+	}
+	httpRes.Body = httpx.NewBodyReader(br.Bytes()[:flt.truncate])
+	httpRes.ContentLength = int64(flt.truncate)
+	httpRes.Header.Set("Content-Length", strconv.Itoa(flt.truncate))
+}
+
+// faultInjectionClaims returns the actor claims required of requests to the :888/inject-faults control endpoint.
+func faultInjectionClaims() string {
+	if claims := env.Get("MICROBUS_FAULT_INJECTION_CLAIMS"); claims != "" {
+		return claims
+	}
+	return defaultFaultInjectionClaims
+}
+
+// handleControlInjectFaults responds to the :888/inject-faults control request by replacing the fault injection rules.
+func (c *Connector) handleControlInjectFaults(w http.ResponseWriter, r *http.Request) error {
+	var payload struct {
+		Rules string `json:"rules"`
+	}
+	err := httpx.ParseRequestBody(r, &payload)
+	if err != nil {
+		return errors.Trace(err, http.StatusBadRequest)
+	}
+	err = c.SetFaultInjection(payload.Rules)
+	if err != nil {
+		return errors.Trace(err, http.StatusBadRequest)
+	}
+	c.LogInfo(r.Context(), "Fault injection updated",
+		"rules", payload.Rules,
+	)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+	return nil
+}
+
+// applyFaultInjection replaces the fault injection rules with the ones obtained from the configurator, if they changed.
+// Invalid rules are logged and the current ones are left in place.
+func (c *Connector) applyFaultInjection(ctx context.Context, rules string) {
+	if c.deployment == PROD {
+		return
+	}
+	c.faultLock.RLock()
+	changed := rules != c.faultConfig
+	c.faultLock.RUnlock()
+	if !changed {
+		return
+	}
+	parsed, err := parseFaultInjection(rules)
+	if err != nil {
+		c.LogWarn(ctx, "Invalid fault injection rules",
+			"rules", rules,
+			"error", err,
+		)
+		return
+	}
+	c.faultLock.Lock()
+	c.faultRules = parsed
+	c.faultConfig = rules
+	c.faultLock.Unlock()
+	c.LogInfo(ctx, "Fault injection updated",
+		"rules", rules,
+	)
+}
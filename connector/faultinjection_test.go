@@ -0,0 +1,298 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_ParseFaultInjection(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	rules, err := parseFaultInjection(" Payments.Example:443/charge post = latency:2s@50%, error:503@10 ;*.example/api/*=dropack\n*=truncate:10\n")
+	if assert.NoError(err) && assert.Len(rules, 3) {
+		assert.Expect(
+			rules[0].hostname, "payments.example",
+			rules[0].port, "443",
+			rules[0].path, "/charge",
+			rules[0].method, "POST",
+			rules[0].faults, []fault{
+				{kind: faultLatency, latency: 2 * time.Second, probability: 0.5},
+				{kind: faultError, statusCode: 503, probability: 0.1},
+			},
+		)
+		assert.Expect(
+			rules[1].hostname, "*.example",
+			rules[1].port, "",
+			rules[1].path, "/api/*",
+			rules[1].method, "",
+			rules[1].faults, []fault{{kind: faultDropAck, probability: 1}},
+		)
+		assert.Expect(
+			rules[2].hostname, "*",
+			rules[2].faults, []fault{{kind: faultTruncate, truncate: 10, probability: 1}},
+		)
+
+		assert.True(rules[0].matches("payments.example", "443", "/charge", "POST"))
+		assert.False(rules[0].matches("payments.example", "443", "/charge", "GET"))
+		assert.False(rules[0].matches("payments.example", "444", "/charge", "POST"))
+		assert.False(rules[0].matches("payments.example", "443", "/charge/x", "POST"))
+		assert.True(rules[1].matches("catalog.example", "443", "/api/list", "GET"))
+		assert.False(rules[1].matches("catalog.example", "443", "/web/list", "GET"))
+		assert.False(rules[1].matches("example", "443", "/api/list", "GET"))
+		assert.True(rules[2].matches("anything", "8080", "/", "DELETE"))
+	}
+
+	rules, err = parseFaultInjection("")
+	assert.NoError(err)
+	assert.Len(rules, 0)
+
+	for _, bad := range []string{
+		"payments.example",
+		"payments.example=",
+		"payments.example=explode",
+		"payments.example=latency:x",
+		"payments.example=latency:-1s",
+		"payments.example=error:200",
+		"payments.example=truncate:-1",
+		"payments.example=dropack:1",
+		"payments.example=error:500@150%",
+		"payments.example:99999=dropack",
+		"payments.example GET POST=dropack",
+		"Bad_Host=dropack",
+	} {
+		_, err = parseFaultInjection(bad)
+		assert.Error(err, "%s", bad)
+	}
+}
+
+func TestConnector_FaultInjection(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	con := New("fault.injection.connector")
+	con.Subscribe("Hello",
+		func(w http.ResponseWriter, r *http.Request) error {
+			count.Add(1)
+			w.Write([]byte("Hello, World!"))
+			return nil
+		},
+		sub.At("ANY", "/hello"),
+		sub.Web(),
+	)
+	client := New("client.fault.injection.connector")
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	hello := func(method string) (string, error) {
+		res, err := client.Request(ctx, pub.Method(method), pub.URL("https://fault.injection.connector/hello"))
+		if err != nil {
+			return "", err
+		}
+		body, _ := io.ReadAll(res.Body)
+		return string(body), nil
+	}
+
+	// No faults
+	body, err := hello("GET")
+	assert.Expect(body, "Hello, World!", err, nil)
+
+	// Error
+	err = con.SetFaultInjection("fault.injection.connector/hello GET=error:503")
+	assert.NoError(err)
+	count.Store(0)
+	_, err = hello("GET")
+	assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	assert.Equal(int32(0), count.Load())
+	body, err = hello("POST")
+	assert.Expect(body, "Hello, World!", err, nil)
+
+	// Latency
+	err = con.SetFaultInjection("fault.injection.connector:443/hello=latency:200ms")
+	assert.NoError(err)
+	t0 := time.Now()
+	body, err = hello("GET")
+	assert.Expect(body, "Hello, World!", err, nil)
+	assert.True(time.Since(t0) >= 200*time.Millisecond)
+
+	// Latency exceeding the time budget
+	t0 = time.Now()
+	_, err = client.Request(ctx, pub.GET("https://fault.injection.connector/hello"), pub.Timeout(100*time.Millisecond))
+	assert.Error(err)
+	assert.True(time.Since(t0) < 200*time.Millisecond)
+
+	// Truncated response
+	err = con.SetFaultInjection("*=truncate:5")
+	assert.NoError(err)
+	body, err = hello("GET")
+	assert.Expect(body, "Hello", err, nil)
+
+	// Dropped ack
+	err = con.SetFaultInjection("*.connector=dropack")
+	assert.NoError(err)
+	_, err = hello("GET")
+	assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+
+	// Zero probability
+	err = con.SetFaultInjection("*=error:500@0%")
+	assert.NoError(err)
+	body, err = hello("GET")
+	assert.Expect(body, "Hello, World!", err, nil)
+
+	// The control port is not subject to fault injection
+	err = con.SetFaultInjection("*=error:500")
+	assert.NoError(err)
+	_, err = client.Request(ctx, pub.GET("https://fault.injection.connector:888/ping"))
+	assert.NoError(err)
+
+	// Rules set via the control endpoint
+	admin := pub.Actor(map[string]any{"roles": map[string]any{"admin": true}})
+	_, err = client.Request(ctx,
+		pub.POST("https://fault.injection.connector:888/inject-faults"),
+		pub.Body(map[string]any{"rules": ""}),
+	)
+	assert.Equal(http.StatusUnauthorized, errors.StatusCode(err))
+	_, err = client.Request(ctx,
+		pub.POST("https://fault.injection.connector:888/inject-faults"),
+		pub.Body(map[string]any{"rules": ""}),
+		admin,
+	)
+	assert.NoError(err)
+	body, err = hello("GET")
+	assert.Expect(body, "Hello, World!", err, nil)
+	_, err = client.Request(ctx,
+		pub.POST("https://fault.injection.connector:888/inject-faults"),
+		pub.Body(map[string]any{"rules": "fault.injection.connector=explode"}),
+		admin,
+	)
+	assert.Equal(http.StatusBadRequest, errors.StatusCode(err))
+	_, err = client.Request(ctx,
+		pub.POST("https://fault.injection.connector:888/inject-faults"),
+		pub.Body(map[string]any{"rules": "fault.injection.connector=error:418"}),
+		admin,
+	)
+	assert.NoError(err)
+	_, err = hello("GET")
+	assert.Equal(http.StatusTeapot, errors.StatusCode(err))
+}
+
+func TestConnector_FaultInjectionOutgoing(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	con := New("outgoing.fault.injection.connector")
+	con.Subscribe("Hello",
+		func(w http.ResponseWriter, r *http.Request) error {
+			count.Add(1)
+			w.Write([]byte("Hello, World!"))
+			return nil
+		},
+		sub.At("ANY", "/hello"),
+		sub.Web(),
+	)
+	client := New("client.outgoing.fault.injection.connector")
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	hello := func() (string, error) {
+		res, err := client.Request(ctx, pub.GET("https://outgoing.fault.injection.connector/hello"))
+		if err != nil {
+			return "", err
+		}
+		body, _ := io.ReadAll(res.Body)
+		return string(body), nil
+	}
+
+	// Error injected by the caller without reaching the responder
+	err = client.SetFaultInjection("outgoing.fault.injection.connector/hello=error:503")
+	assert.NoError(err)
+	_, err = hello()
+	assert.Equal(http.StatusServiceUnavailable, errors.StatusCode(err))
+	assert.Equal(int32(0), count.Load())
+
+	// Dropped ack
+	err = client.SetFaultInjection("outgoing.fault.injection.connector=dropack")
+	assert.NoError(err)
+	_, err = hello()
+	assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	assert.Equal(int32(0), count.Load())
+
+	// Other destinations are not affected
+	err = client.SetFaultInjection("other.connector=error:500")
+	assert.NoError(err)
+	body, err := hello()
+	assert.Expect(body, "Hello, World!", err, nil)
+	assert.Equal(int32(1), count.Load())
+
+	// A rule known to both sides is applied only once
+	err = client.SetFaultInjection("outgoing.fault.injection.connector=latency:150ms")
+	assert.NoError(err)
+	err = con.SetFaultInjection("outgoing.fault.injection.connector=latency:150ms")
+	assert.NoError(err)
+	t0 := time.Now()
+	body, err = hello()
+	assert.Expect(body, "Hello, World!", err, nil)
+	dur := time.Since(t0)
+	assert.True(dur >= 150*time.Millisecond && dur < 300*time.Millisecond, "%v", dur)
+}
+
+func TestConnector_FaultInjectionNotInProd(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	con := New("fault.injection.not.in.prod.connector")
+	err := con.SetDeployment(PROD)
+	assert.NoError(err)
+	err = con.SetFaultInjection("*=error:500")
+	assert.Equal(http.StatusForbidden, errors.StatusCode(err))
+
+	// Rules set before the deployment was known are ignored
+	con = New("fault.injection.not.in.prod.connector")
+	err = con.SetFaultInjection("*=error:500")
+	assert.NoError(err)
+	con.deployment = PROD
+	httpReq, _ := http.NewRequest("GET", "https://fault.injection.not.in.prod.connector/hello", nil)
+	assert.Nil(con.injectedFault(faultError, httpReq))
+	con.deployment = LOCAL
+	assert.NotNil(con.injectedFault(faultError, httpReq))
+}
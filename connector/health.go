@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/cfg"
+	"github.com/microbus-io/fabric/service"
+)
+
+/*
+SetOnHealthCheck sets the function to be called to check the health of the dependencies of the microservice,
+such as a database connection pool or downstream hosts. The outcomes are reported by the :888/health control
+endpoint alongside the built-in checks of the connector: startup, config and tickers.
+
+	con.SetOnHealthCheck(func(ctx context.Context) map[string]error {
+		return map[string]error{
+			"sql": db.PingContext(ctx),
+		}
+	})
+*/
+func (c *Connector) SetOnHealthCheck(handler service.HealthCheckHandler) error {
+	if !c.isPhase(shutDown) {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	c.onHealthCheck = handler
+	return nil
+}
+
+// healthCheckStatus is the outcome of a single health check.
+type healthCheckStatus struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// healthReport is the response to the :888/health control request.
+type healthReport struct {
+	Healthy bool                         `json:"healthy"`
+	Checks  map[string]healthCheckStatus `json:"checks"`
+}
+
+// checkHealth runs the built-in health checks and those of the OnHealthCheck callback, if set.
+func (c *Connector) checkHealth(ctx context.Context) (checks map[string]error) {
+	checks = map[string]error{
+		"startup": nil,
+		"config":  c.checkConfigHealth(),
+		"tickers": c.checkTickersHealth(),
+	}
+	if !c.isPhase(startedUp) {
+		checks["startup"] = errors.New("not started up", http.StatusServiceUnavailable)
+		return checks
+	}
+	if c.onHealthCheck != nil {
+		var custom map[string]error
+		err := errors.CatchPanic(func() error {
+			custom = c.onHealthCheck(ctx)
+			return nil
+		})
+		if err != nil {
+			checks["onHealthCheck"] = err
+		}
+		for name, err := range custom {
+			checks[name] = err
+		}
+	}
+	return checks
+}
+
+// checkConfigHealth verifies that the values of the config properties validate against their rules.
+func (c *Connector) checkConfigHealth() error {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+	var names []string
+	for _, config := range c.configs {
+		if !cfg.Validate(config.Validation, config.Value) {
+			names = append(names, config.Name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return errors.New("invalid value of config %v", names)
+	}
+	return nil
+}
+
+// checkTickersHealth verifies that no idle ticker is overdue by more than its interval, or a minute,
+// which indicates that it is no longer firing. A ticker whose job is running long is not overdue,
+// consistent with the beats it skips being tolerated.
+func (c *Connector) checkTickersHealth() error {
+	c.tickersLock.Lock()
+	defer c.tickersLock.Unlock()
+	var names []string
+	for _, job := range c.tickers {
+		if job.NextRun.IsZero() || job.Running {
+			continue
+		}
+		if time.Since(job.NextRun) > max(job.Interval, time.Minute) {
+			names = append(names, job.Name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return errors.New("overdue ticker %v", names)
+	}
+	return nil
+}
+
+// handleControlHealth responds to the :888/health control request with the outcome of the health checks.
+func (c *Connector) handleControlHealth(w http.ResponseWriter, r *http.Request) error {
+	report := healthReport{
+		Healthy: true,
+		Checks:  map[string]healthCheckStatus{},
+	}
+	for name, err := range c.checkHealth(r.Context()) {
+		status := healthCheckStatus{Healthy: err == nil}
+		if err != nil {
+			status.Error = err.Error()
+			report.Healthy = false
+		}
+		report.Checks[name] = status
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(report)
+	return errors.Trace(err)
+}
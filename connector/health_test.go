@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Health(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	con := New("health.connector")
+	con.DefineConfig("Greeting")
+	err := con.SetOnHealthCheck(func(ctx context.Context) map[string]error {
+		return map[string]error{
+			"sql":        nil,
+			"downstream": errors.New("connection refused"),
+		}
+	})
+	assert.NoError(err)
+	client := New("client.health.connector")
+
+	// Not started up
+	checks := con.checkHealth(ctx)
+	assert.Error(checks["startup"])
+	assert.NoError(checks["config"])
+	assert.NoError(checks["tickers"])
+
+	err = con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// Cannot set the callback after startup
+	err = con.SetOnHealthCheck(nil)
+	assert.Error(err)
+
+	res, err := client.Request(ctx, pub.GET("https://health.connector:888/health"))
+	if assert.NoError(err) {
+		var report healthReport
+		err = json.NewDecoder(res.Body).Decode(&report)
+		assert.NoError(err)
+		assert.False(report.Healthy)
+		assert.True(report.Checks["startup"].Healthy)
+		assert.True(report.Checks["config"].Healthy)
+		assert.True(report.Checks["tickers"].Healthy)
+		assert.True(report.Checks["sql"].Healthy)
+		assert.False(report.Checks["downstream"].Healthy)
+		assert.Equal("connection refused", report.Checks["downstream"].Error)
+	}
+
+	// Overdue ticker
+	con.tickersLock.Lock()
+	con.tickers["Stuck"] = &tickerCallback{Name: "Stuck", Interval: time.Second, NextRun: time.Now().Add(-time.Hour)}
+	con.tickersLock.Unlock()
+	assert.Error(con.checkTickersHealth())
+
+	// A ticker whose job is running long is not overdue
+	con.tickersLock.Lock()
+	con.tickers["Stuck"].Running = true
+	con.tickersLock.Unlock()
+	assert.NoError(con.checkTickersHealth())
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Hedge(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservice
+	var count atomic.Int32
+	con := New("hedge.connector")
+	con.Subscribe("Slow",
+		func(w http.ResponseWriter, r *http.Request) error {
+			body, _ := io.ReadAll(r.Body)
+			if count.Add(1) == 1 {
+				// Only the first attempt is slow
+				time.Sleep(time.Second)
+				w.Write([]byte("slow"))
+				return nil
+			}
+			w.Write(body)
+			return nil
+		},
+		sub.At("ANY", "slow"),
+		sub.Web(),
+	)
+
+	// Startup the microservice
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// The hedged request should return first
+	t0 := time.Now()
+	res, err := con.Request(ctx, pub.PUT("https://hedge.connector/slow"), pub.Body("fast"), pub.Hedge(100*time.Millisecond))
+	dur := time.Since(t0)
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal("fast", string(body))
+	}
+	assert.True(dur >= 100*time.Millisecond && dur < time.Second, "%v", dur)
+	assert.Equal(int32(2), count.Load())
+
+	// A fast response is not hedged
+	count.Store(1)
+	_, err = con.Request(ctx, pub.GET("https://hedge.connector/slow"), pub.Hedge(200*time.Millisecond))
+	assert.NoError(err)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(int32(2), count.Load())
+
+	// Hedging is limited to idempotent methods
+	_, err = con.Request(ctx, pub.POST("https://hedge.connector/slow"), pub.Hedge(100*time.Millisecond))
+	assert.Error(err)
+	_, err = con.Request(ctx, pub.POST("https://hedge.connector/slow"), pub.Hedge(100*time.Millisecond), pub.IdempotencyKey("k1"))
+	assert.Error(err)
+}
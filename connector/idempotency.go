@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/dlru"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// Markers of the records of idempotent requests in the distributed cache.
+const (
+	idempotencyPending  = 'P' // followed by the deadline of the execution
+	idempotencyResponse = 'R' // followed by the serialized response
+)
+
+// idempotencyPollInterval is the interval at which a duplicate request checks if a request
+// that is processing on another replica has completed.
+const idempotencyPollInterval = 50 * time.Millisecond
+
+// idempotentFlight is the local execution of a request with an idempotency key.
+// Duplicates of the request that arrive at the same replica wait for it to be done.
+type idempotentFlight struct {
+	done chan struct{}
+}
+
+// idempotentCall is a request with an idempotency key that is executed by the handler.
+type idempotentCall struct {
+	key    string
+	flight *idempotentFlight
+}
+
+// idempotencyKeyOf returns the key of the record of the idempotent request in the distributed cache.
+// The key identifies the endpoint, the actor and the idempotency key of the request.
+func idempotencyKeyOf(s *sub.Subscription, r *http.Request, claims jwt.MapClaims, idempotencyKey string) string {
+	actor := ""
+	if claims != nil {
+		iss, _ := claims["iss"].(string)
+		subject, _ := claims["sub"].(string)
+		actor = iss + "\x00" + subject
+		if subject == "" {
+			// Fall back to the token itself if it does not identify the actor
+			actor = r.Header.Get(frame.HeaderActor)
+		}
+	}
+	h := sha256.New()
+	for _, part := range []string{s.Host, s.Port, s.Path, r.Method, actor, idempotencyKey} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return "idempotency:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// beginIdempotent looks up the response to a previous request with the same idempotency key.
+// If a response is found, it is returned to be replayed. If the previous request is still processing,
+// the call waits for it to complete. Otherwise, the request is marked as pending and a call is returned
+// that must be ended with endIdempotent after the handler processes the request.
+//
+// Deduplication across replicas is best-effort. The distributed cache has no atomic insert-if-absent,
+// so the lookup and the pending mark are separate operations. Duplicates that arrive at different replicas
+// at the very same time may both find no record and both be executed. Duplicates that arrive at the same
+// replica are always deduplicated because they are serialized by the local flight.
+func (c *Connector) beginIdempotent(ctx context.Context, s *sub.Subscription, key string, deadline time.Time) (replay *http.Response, call *idempotentCall, err error) {
+	for {
+		// Wait for a duplicate that is processing on this replica
+		c.idempotentLock.Lock()
+		flight, ok := c.idempotentFlights[key]
+		if !ok {
+			flight = &idempotentFlight{done: make(chan struct{})}
+			c.idempotentFlights[key] = flight
+		}
+		c.idempotentLock.Unlock()
+		if ok {
+			select {
+			case <-flight.done:
+				continue
+			case <-ctx.Done():
+				return nil, nil, errors.Trace(ctx.Err())
+			}
+		}
+
+		// Look up the record in the distributed cache, waiting for a duplicate that is processing on another replica
+		for {
+			record, found, err := c.distribCache.Load(ctx, key, dlru.MaxAge(s.IdempotencyTTL))
+			if err != nil {
+				c.endFlight(key, flight)
+				return nil, nil, errors.Trace(err)
+			}
+			if found && len(record) > 0 && record[0] == idempotencyResponse {
+				c.endFlight(key, flight)
+				replay, err = http.ReadResponse(bufio.NewReader(bytes.NewReader(record[1:])), nil)
+				if err != nil {
+					return nil, nil, errors.Trace(err)
+				}
+				return replay, nil, nil
+			}
+			if found && len(record) > 0 && record[0] == idempotencyPending {
+				pendingDeadline, err := time.Parse(time.RFC3339Nano, string(record[1:]))
+				if err == nil && time.Now().Before(pendingDeadline) {
+					select {
+					case <-time.After(idempotencyPollInterval):
+						continue
+					case <-ctx.Done():
+						c.endFlight(key, flight)
+						return nil, nil, errors.Trace(ctx.Err())
+					}
+				}
+			}
+			break
+		}
+
+		// Mark the request as pending until its deadline
+		record := append([]byte{idempotencyPending}, deadline.UTC().Format(time.RFC3339Nano)...)
+		err = c.distribCache.Store(ctx, key, record)
+		if err != nil {
+			c.endFlight(key, flight)
+			return nil, nil, errors.Trace(err)
+		}
+		return nil, &idempotentCall{key: key, flight: flight}, nil
+	}
+}
+
+// endIdempotent records the response to the request, or clears the pending mark if there is no
+// response to retain, and releases duplicates that are waiting for the request to complete.
+func (c *Connector) endIdempotent(ctx context.Context, call *idempotentCall, res *http.Response) {
+	defer c.endFlight(call.key, call.flight)
+	if res == nil {
+		err := c.distribCache.Delete(ctx, call.key)
+		if err != nil {
+			c.LogError(ctx, "Clearing idempotent request", "error", errors.Trace(err))
+		}
+		return
+	}
+	// Dump a copy of the response so as not to consume its body
+	dup := *res
+	dup.Header = res.Header.Clone()
+	dup.Body = http.NoBody
+	if br, ok := res.Body.(*httpx.BodyReader); ok {
+		dup.Body = httpx.NewBodyReader(br.Bytes())
+	}
+	dump, err := httputil.DumpResponse(&dup, true)
+	if err == nil {
+		err = c.distribCache.Store(ctx, call.key, append([]byte{idempotencyResponse}, dump...))
+	}
+	if err != nil {
+		c.LogError(ctx, "Storing idempotent response", "error", errors.Trace(err))
+		_ = c.distribCache.Delete(ctx, call.key)
+	}
+}
+
+// endFlight releases duplicates that are waiting for the local execution of a request.
+func (c *Connector) endFlight(key string, flight *idempotentFlight) {
+	c.idempotentLock.Lock()
+	if c.idempotentFlights[key] == flight {
+		delete(c.idempotentFlights, key)
+	}
+	c.idempotentLock.Unlock()
+	close(flight.done)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_IdempotencyKey(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	con := New("idempotency.key.connector")
+	con.Subscribe("Charge",
+		func(w http.ResponseWriter, r *http.Request) error {
+			n := count.Add(1)
+			w.Header().Set("X-Count", strconv.Itoa(int(n)))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("charge " + strconv.Itoa(int(n))))
+			return nil
+		},
+		sub.At("POST", "/charge"),
+		sub.Web(),
+		sub.IdempotencyKeys(time.Minute),
+	)
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	charge := func(options ...pub.Option) (body string, replayed bool) {
+		options = append([]pub.Option{pub.POST("https://idempotency.key.connector/charge")}, options...)
+		res, err := con.Request(ctx, options...)
+		if !assert.NoError(err) {
+			return "", false
+		}
+		assert.Equal(http.StatusCreated, res.StatusCode)
+		b, _ := io.ReadAll(res.Body)
+		return string(b), res.Header.Get("Idempotent-Replayed") == "true"
+	}
+
+	// The first request is executed
+	body, replayed := charge(pub.IdempotencyKey("k1"))
+	assert.Expect(
+		body, "charge 1",
+		replayed, false,
+	)
+
+	// The duplicate is replayed, including its headers
+	res, err := con.Request(ctx, pub.POST("https://idempotency.key.connector/charge"), pub.IdempotencyKey("k1"))
+	if assert.NoError(err) {
+		b, _ := io.ReadAll(res.Body)
+		assert.Expect(
+			res.StatusCode, http.StatusCreated,
+			string(b), "charge 1",
+			res.Header.Get("X-Count"), "1",
+			res.Header.Get("Idempotent-Replayed"), "true",
+		)
+	}
+
+	// A different key is executed
+	body, replayed = charge(pub.IdempotencyKey("k2"))
+	assert.Expect(
+		body, "charge 2",
+		replayed, false,
+	)
+
+	// Requests without a key are always executed
+	body, _ = charge()
+	assert.Equal("charge 3", body)
+	body, _ = charge()
+	assert.Equal("charge 4", body)
+	assert.Equal(int32(4), count.Load())
+}
+
+func TestConnector_IdempotencyKeyConcurrent(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		n := count.Add(1)
+		time.Sleep(250 * time.Millisecond)
+		w.Write([]byte("charge " + strconv.Itoa(int(n))))
+		return nil
+	}
+	con := New("idempotency.key.concurrent.connector")
+	con.Subscribe("Charge", handler, sub.At("POST", "/charge"), sub.Web(), sub.IdempotencyKeys(time.Minute))
+	replica := New("idempotency.key.concurrent.connector")
+	replica.Subscribe("Charge", handler, sub.At("POST", "/charge"), sub.Web(), sub.IdempotencyKeys(time.Minute))
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = replica.Startup(ctx)
+	assert.NoError(err)
+	defer replica.Shutdown(ctx)
+
+	charge := func(host string, key string) string {
+		res, err := con.Request(ctx,
+			pub.POST("https://"+host+"/charge"),
+			pub.IdempotencyKey(key),
+		)
+		if !assert.NoError(err) {
+			return ""
+		}
+		b, _ := io.ReadAll(res.Body)
+		return string(b)
+	}
+
+	// Duplicates that arrive at the same replica while the first request is processing wait for its response
+	var wg sync.WaitGroup
+	bodies := make(chan string, 8)
+	for range 8 {
+		wg.Go(func() {
+			bodies <- charge(con.ID()+".idempotency.key.concurrent.connector", "k1")
+		})
+	}
+	wg.Wait()
+	close(bodies)
+	for body := range bodies {
+		assert.Equal("charge 1", body)
+	}
+	assert.Equal(int32(1), count.Load())
+
+	// A duplicate that arrives at another replica while the first request is processing waits for its response
+	done := make(chan string)
+	go func() {
+		done <- charge(con.ID()+".idempotency.key.concurrent.connector", "k2")
+	}()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal("charge 2", charge(replica.ID()+".idempotency.key.concurrent.connector", "k2"))
+	assert.Equal("charge 2", <-done)
+	assert.Equal(int32(2), count.Load())
+
+	// A duplicate that arrives at another replica after the first request completed is replayed
+	assert.Equal("charge 1", charge(replica.ID()+".idempotency.key.concurrent.connector", "k1"))
+	assert.Equal(int32(2), count.Load())
+}
+
+func TestConnector_IdempotencyKeyErrors(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	con := New("idempotency.key.errors.connector")
+	con.Subscribe("Charge",
+		func(w http.ResponseWriter, r *http.Request) error {
+			if count.Add(1) <= 2 {
+				return errors.New("declined", http.StatusServiceUnavailable)
+			}
+			w.Write([]byte("charged"))
+			return nil
+		},
+		sub.At("POST", "/charge"),
+		sub.Web(),
+		sub.IdempotencyKeys(time.Minute),
+	)
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// Errors are not retained so the duplicate is executed again
+	_, err = con.Request(ctx, pub.POST("https://idempotency.key.errors.connector/charge"), pub.IdempotencyKey("k1"))
+	assert.Error(err)
+	_, err = con.Request(ctx, pub.POST("https://idempotency.key.errors.connector/charge"), pub.IdempotencyKey("k1"))
+	assert.Error(err)
+	assert.Equal(int32(2), count.Load())
+
+	// The retries of a request without a key share a generated key, so the successful attempt is retained
+	// under a key that differs from k1
+	res, err := con.Request(ctx,
+		pub.POST("https://idempotency.key.errors.connector/charge"),
+		pub.Retry(pub.RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond}),
+	)
+	if assert.NoError(err) {
+		b, _ := io.ReadAll(res.Body)
+		assert.Equal("charged", string(b))
+	}
+	assert.Equal(int32(3), count.Load())
+	res, err = con.Request(ctx, pub.POST("https://idempotency.key.errors.connector/charge"), pub.IdempotencyKey("k1"))
+	if assert.NoError(err) {
+		assert.Equal("", res.Header.Get("Idempotent-Replayed"))
+	}
+	assert.Equal(int32(4), count.Load())
+}
+
+func TestConnector_IdempotencyKeyOf(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	s, err := sub.NewSubscription("Charge", "idempotency.key.of.connector", func(w http.ResponseWriter, r *http.Request) error { return nil }, sub.At("POST", "/charge"), sub.Web())
+	assert.NoError(err)
+	r, _ := http.NewRequest("POST", "https://idempotency.key.of.connector/charge", nil)
+
+	alice := map[string]any{"iss": "issuer", "sub": "alice"}
+	bob := map[string]any{"iss": "issuer", "sub": "bob"}
+	k := idempotencyKeyOf(s, r, alice, "k1")
+	assert.Equal(k, idempotencyKeyOf(s, r, alice, "k1"))
+	assert.NotEqual(k, idempotencyKeyOf(s, r, bob, "k1"))
+	assert.NotEqual(k, idempotencyKeyOf(s, r, alice, "k2"))
+	assert.NotEqual(k, idempotencyKeyOf(s, r, nil, "k1"))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"iter"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+)
+
+// ClientHandler sends a request over the bus and returns the responses it receives.
+type ClientHandler func(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response]
+
+// ClientInterceptor returns a function that can pre or post process an outbound request or its responses.
+// The frame of the request is accessible via frame.Of(req.Header).
+// The interceptor should generally call the next function in the chain.
+type ClientInterceptor func(next ClientHandler) ClientHandler
+
+// ServerInterceptor returns a function that can pre or post process an inbound request to the subscription, or its response.
+// The interceptor should generally call the next function in the chain.
+// It may wrap the response writer to observe or rewrite the response written by the handler.
+type ServerInterceptor func(s *sub.Subscription, next HTTPHandler) HTTPHandler
+
+/*
+AddClientInterceptor adds an interceptor to the end of the chain that wraps the outbound requests of the microservice.
+Each attempt of a retried request is intercepted. Requests served by the client-side response cache,
+or that share the response of a coalesced request, are not sent over the bus and are not intercepted.
+
+	con.AddClientInterceptor(func(next connector.ClientHandler) connector.ClientHandler {
+		return func(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+			req.Header.Set("X-Tenant", tenantOf(ctx))
+			return next(ctx, req)
+		}
+	})
+
+Interceptors are called in the order they were added, the first being outermost.
+*/
+func (c *Connector) AddClientInterceptor(interceptor ClientInterceptor) error {
+	if !c.isPhase(shutDown) {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	if interceptor == nil {
+		return nil
+	}
+	c.clientInterceptors = append(c.clientInterceptors, interceptor)
+	return nil
+}
+
+/*
+AddServerInterceptor adds an interceptor to the end of the chain that wraps the handlers of the subscriptions of the microservice.
+Interceptors are called after the actor of the request is verified and before the handler is called.
+The frame of the request is accessible via frame.Of(r).
+
+	con.AddServerInterceptor(func(s *sub.Subscription, next connector.HTTPHandler) connector.HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			err := next(w, r)
+			audit(r.Context(), s.Name, frame.Of(r).FromHost(), err)
+			return err
+		}
+	})
+
+Interceptors are called in the order they were added, the first being outermost.
+*/
+func (c *Connector) AddServerInterceptor(interceptor ServerInterceptor) error {
+	if !c.isPhase(shutDown) {
+		return c.captureInitErr(errors.New("already started"))
+	}
+	if interceptor == nil {
+		return nil
+	}
+	c.serverInterceptors = append(c.serverInterceptors, interceptor)
+	return nil
+}
+
+// sendRequest sends a request over the bus via the chain of client interceptors.
+func (c *Connector) sendRequest(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+	handler := ClientHandler(c.makeRequest)
+	for i := len(c.clientInterceptors) - 1; i >= 0; i-- {
+		handler = c.clientInterceptors[i](handler)
+	}
+	return handler(ctx, req)
+}
+
+// interceptedHandler returns the handler of the subscription wrapped by the chain of server interceptors.
+func (c *Connector) interceptedHandler(s *sub.Subscription) HTTPHandler {
+	handler := s.Handler.(HTTPHandler)
+	for i := len(c.serverInterceptors) - 1; i >= 0; i-- {
+		handler = c.serverInterceptors[i](s, handler)
+	}
+	return handler
+}
@@ -0,0 +1,180 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+// statusRecorder records the status code written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	sr.statusCode = statusCode
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func TestConnector_Interceptors(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var mux sync.Mutex
+	var trail []string
+	record := func(s string) {
+		mux.Lock()
+		trail = append(trail, s)
+		mux.Unlock()
+	}
+	popTrail := func() []string {
+		mux.Lock()
+		defer mux.Unlock()
+		t := trail
+		trail = nil
+		return t
+	}
+
+	con := New("interceptors.connector")
+	con.Subscribe("Echo",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Set("X-Tenant", r.Header.Get("X-Tenant"))
+			if r.URL.Query().Get("status") != "" {
+				w.WriteHeader(http.StatusAccepted)
+			}
+			w.Write([]byte("echo"))
+			return nil
+		},
+		sub.At("GET", "/echo"),
+		sub.Web(),
+	)
+	con.Subscribe("Secret",
+		func(w http.ResponseWriter, r *http.Request) error {
+			record("handler")
+			return nil
+		},
+		sub.At("GET", "/secret"),
+		sub.Web(),
+	)
+	for _, name := range []string{"outer", "inner"} {
+		con.AddServerInterceptor(func(s *sub.Subscription, next HTTPHandler) HTTPHandler {
+			if s.Port == "888" {
+				return next // Ignore control subscriptions
+			}
+			return func(w http.ResponseWriter, r *http.Request) error {
+				record(name + " " + s.Name + " from " + frame.Of(r).FromHost())
+				if s.Name == "Secret" && r.Header.Get("X-Tenant") == "" {
+					return errors.New("", http.StatusForbidden)
+				}
+				sr := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+				err := next(sr, r)
+				record(name + " " + s.Name + " done " + http.StatusText(sr.statusCode))
+				return err
+			}
+		})
+	}
+
+	client := New("client.interceptors.connector")
+	client.AddClientInterceptor(func(next ClientHandler) ClientHandler {
+		return func(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+			if strings.Contains(req.URL, ":888/") {
+				return next(ctx, req) // Ignore control requests
+			}
+			if req.Header.Get("X-Tenant") == "" {
+				req.Header.Set("X-Tenant", "acme")
+			}
+			record("client " + req.Canonical())
+			queue := next(ctx, req)
+			return func(yield func(*pub.Response) bool) {
+				for r := range queue {
+					res, err := r.Get()
+					if err == nil {
+						record("client response " + res.Header.Get("X-Tenant"))
+					} else {
+						record("client error " + http.StatusText(errors.StatusCode(err)))
+					}
+					if !yield(r) {
+						return
+					}
+				}
+			}
+		}
+	})
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// Interceptors cannot be added after startup
+	err = con.AddServerInterceptor(func(s *sub.Subscription, next HTTPHandler) HTTPHandler { return next })
+	assert.Error(err)
+	err = client.AddClientInterceptor(func(next ClientHandler) ClientHandler { return next })
+	assert.Error(err)
+
+	// The client interceptor injects a header, and the server interceptors observe the response
+	res, err := client.Request(ctx, pub.GET("https://interceptors.connector/echo?status=1"))
+	if assert.NoError(err) {
+		b, _ := io.ReadAll(res.Body)
+		assert.Equal("echo", string(b))
+		assert.Equal("acme", res.Header.Get("X-Tenant"))
+	}
+	assert.Equal([]string{
+		"client https://interceptors.connector:443/echo",
+		"outer Echo from client.interceptors.connector",
+		"inner Echo from client.interceptors.connector",
+		"inner Echo done Accepted",
+		"outer Echo done Accepted",
+		"client response acme",
+	}, popTrail())
+
+	// A server interceptor may reject the request before it reaches the handler
+	_, err = con.Request(ctx, pub.GET("https://interceptors.connector/secret"))
+	assert.Equal(http.StatusForbidden, errors.StatusCode(err))
+	assert.Equal([]string{
+		"outer Secret from interceptors.connector",
+	}, popTrail())
+
+	_, err = client.Request(ctx, pub.GET("https://interceptors.connector/secret"))
+	assert.NoError(err)
+	assert.Equal([]string{
+		"client https://interceptors.connector:443/secret",
+		"outer Secret from client.interceptors.connector",
+		"inner Secret from client.interceptors.connector",
+		"handler",
+		"inner Secret done OK",
+		"outer Secret done OK",
+		"client response ",
+	}, popTrail())
+}
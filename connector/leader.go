@@ -0,0 +1,264 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+)
+
+/*
+leaderLease tracks the leadership among the instances of the microservice that share its hostname.
+The leader runs the singleton tickers on behalf of all instances.
+
+An instance campaigns for leadership by multicasting a claim for a lease to all instances, itself included.
+Each instance grants the claim unless it knows of an unexpired lease held by another instance.
+The claimant becomes the leader if a majority of the responders grant the claim.
+The leader renews its lease periodically and releases it when it shuts down.
+Other instances campaign when the lease they know of expires or is released.
+*/
+type leaderLease struct {
+	mux     sync.Mutex
+	holder  string    // the ID of the instance known to hold the lease
+	expires time.Time // the expiration of the lease known to be held
+	leading bool      // indicates if this instance was elected
+	until   time.Time // the expiration of the lease of this instance, if leading
+	stop    context.CancelFunc
+	wake    chan bool
+}
+
+// leaseClaim is the payload of the :888/lease control request.
+type leaseClaim struct {
+	ID  string        `json:"id"`
+	TTL time.Duration `json:"ttl"` // zero releases the lease
+}
+
+// leaseGrant is the response to the :888/lease control request.
+type leaseGrant struct {
+	Granted bool   `json:"granted"`
+	Holder  string `json:"holder,omitzero"`
+}
+
+// isLeader indicates if this instance is the elected leader among the instances of the microservice.
+func (c *Connector) isLeader() bool {
+	c.lease.mux.Lock()
+	defer c.lease.mux.Unlock()
+	return c.lease.leading && time.Now().Before(c.lease.until)
+}
+
+// leader returns the ID of the instance known to be the leader, or an empty string if there is none.
+func (c *Connector) leader() string {
+	c.lease.mux.Lock()
+	defer c.lease.mux.Unlock()
+	if c.lease.holder == "" || !time.Now().Before(c.lease.expires) {
+		return ""
+	}
+	return c.lease.holder
+}
+
+// startLeaderElection starts campaigning for leadership, if not already started.
+func (c *Connector) startLeaderElection() {
+	c.lease.mux.Lock()
+	if c.lease.stop != nil {
+		c.lease.mux.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(c.Lifetime())
+	c.lease.stop = cancel
+	c.lease.wake = make(chan bool, 1)
+	wake := c.lease.wake
+	c.lease.mux.Unlock()
+
+	go func() {
+		for {
+			c.campaign(ctx)
+			// Renew at a third of the lease to tolerate a lost renewal
+			delay := c.leaseTTL/3 - time.Duration(rand.Int64N(int64(c.leaseTTL/10)+1))
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-wake:
+				timer.Stop()
+				// Stagger the campaigns of the instances
+				time.Sleep(time.Duration(rand.Int64N(int64(c.leaseTTL/10) + 1)))
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// stopLeaderElection stops campaigning for leadership and releases the lease if this instance is the leader.
+func (c *Connector) stopLeaderElection(ctx context.Context) {
+	c.lease.mux.Lock()
+	stop := c.lease.stop
+	leading := c.lease.leading
+	c.lease.stop = nil
+	c.lease.leading = false
+	c.lease.mux.Unlock()
+	if stop == nil {
+		return
+	}
+	stop()
+	if !leading {
+		return
+	}
+	c.LogInfo(ctx, "Resigning leadership")
+	body, _ := json.Marshal(leaseClaim{ID: c.id})
+	for range c.Publish(ctx,
+		pub.POST("https://"+c.hostname+":888/lease"),
+		pub.Body(body),
+		pub.ContentType("application/json"),
+	) {
+	}
+}
+
+// campaign claims or renews the lease if it is not held by another instance.
+func (c *Connector) campaign(ctx context.Context) {
+	c.lease.mux.Lock()
+	leading := c.lease.leading
+	heldByOther := c.lease.holder != "" && c.lease.holder != c.id && time.Now().Before(c.lease.expires)
+	c.lease.mux.Unlock()
+	if heldByOther {
+		return
+	}
+
+	start := time.Now()
+	body, _ := json.Marshal(leaseClaim{ID: c.id, TTL: c.leaseTTL})
+	granted := 0
+	responded := 0
+	holder := ""
+	for r := range c.Publish(ctx,
+		pub.POST("https://"+c.hostname+":888/lease"),
+		pub.Body(body),
+		pub.ContentType("application/json"),
+		pub.Timeout(c.leaseTTL/3),
+	) {
+		res, err := r.Get()
+		if err != nil {
+			continue
+		}
+		var grant leaseGrant
+		err = json.NewDecoder(res.Body).Decode(&grant)
+		if err != nil {
+			continue
+		}
+		responded++
+		if grant.Granted {
+			granted++
+		} else if grant.Holder != c.id {
+			holder = grant.Holder
+		}
+	}
+	elected := responded > 0 && granted*2 > responded
+
+	c.lease.mux.Lock()
+	if elected {
+		c.lease.leading = true
+		c.lease.until = start.Add(c.leaseTTL)
+	} else {
+		c.lease.leading = false
+		if holder != "" {
+			// Defer to the instance that holds the lease according to the responders
+			c.lease.holder = holder
+			c.lease.expires = start.Add(c.leaseTTL)
+		} else if c.lease.holder == c.id {
+			// Allow the other claimant to take the lease
+			c.lease.holder = ""
+		}
+	}
+	c.lease.mux.Unlock()
+
+	if elected && !leading {
+		c.LogInfo(ctx, "Elected leader",
+			"granted", granted,
+			"responded", responded,
+		)
+	}
+	if !elected && leading {
+		c.LogWarn(ctx, "Lost leadership",
+			"granted", granted,
+			"responded", responded,
+		)
+	}
+}
+
+// handleControlLease responds to the :888/lease control request by granting or rejecting a claim for the lease.
+func (c *Connector) handleControlLease(w http.ResponseWriter, r *http.Request) error {
+	if frame.Of(r).FromHost() != c.hostname {
+		return errors.New("", http.StatusForbidden)
+	}
+	var claim leaseClaim
+	err := httpx.ParseRequestBody(r, &claim)
+	if err != nil {
+		return errors.Trace(err, http.StatusBadRequest)
+	}
+	if claim.ID == "" {
+		return errors.New("missing claimant", http.StatusBadRequest)
+	}
+
+	var grant leaseGrant
+	released := false
+	now := time.Now()
+	c.lease.mux.Lock()
+	switch {
+	case claim.TTL <= 0:
+		if c.lease.holder == claim.ID {
+			c.lease.holder = ""
+			released = true
+		}
+		grant.Granted = true
+	case c.lease.holder == "" || c.lease.holder == claim.ID || !now.Before(c.lease.expires):
+		c.lease.holder = claim.ID
+		c.lease.expires = now.Add(claim.TTL)
+		grant.Granted = true
+	}
+	grant.Holder = c.lease.holder
+	wake := c.lease.wake
+	c.lease.mux.Unlock()
+
+	if released && wake != nil {
+		// Campaign without waiting for the next renewal
+		select {
+		case wake <- true:
+		default:
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return errors.Trace(json.NewEncoder(w).Encode(grant))
+}
+
+// handleControlLeader responds to the :888/leader control request with the ID of the leader among the instances of the microservice.
+func (c *Connector) handleControlLeader(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	return errors.Trace(json.NewEncoder(w).Encode(struct {
+		Leader string `json:"leader"`
+	}{
+		Leader: c.leader(),
+	}))
+}
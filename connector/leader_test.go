@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/tick"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_SingletonTicker(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	// Create the microservices
+	cons := make([]*Connector, 3)
+	counts := make([]atomic.Int32, 3)
+	for i := range cons {
+		cons[i] = New("singleton.ticker.connector")
+		cons[i].SetDeployment(LAB) // Tickers are disabled in TESTING
+		cons[i].leaseTTL = 300 * time.Millisecond
+		cons[i].StartTicker("Job", 50*time.Millisecond, func(ctx context.Context) error {
+			counts[i].Add(1)
+			return nil
+		}, tick.Singleton())
+	}
+
+	// Startup the microservices
+	for _, con := range cons {
+		err := con.Startup(ctx)
+		assert.NoError(err)
+		defer con.Shutdown(ctx)
+	}
+
+	awaitLeader := func(cons []*Connector) (leader *Connector) {
+		for range 40 {
+			n := 0
+			for _, con := range cons {
+				if con.isLeader() {
+					leader = con
+					n++
+				}
+			}
+			if n == 1 {
+				return leader
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil
+	}
+
+	// Exactly one instance should be elected
+	leader := awaitLeader(cons)
+	if !assert.NotNil(leader) {
+		return
+	}
+	res, err := cons[0].GET(ctx, "https://singleton.ticker.connector:888/leader")
+	if assert.NoError(err) {
+		var out struct {
+			Leader string `json:"leader"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&out)
+		assert.NoError(err)
+		assert.Equal(leader.ID(), out.Leader)
+	}
+
+	// Only the leader should run the ticker
+	for i := range counts {
+		counts[i].Store(0)
+	}
+	time.Sleep(300 * time.Millisecond)
+	for i, con := range cons {
+		if con == leader {
+			assert.True(counts[i].Load() > 0)
+		} else {
+			assert.Zero(counts[i].Load())
+		}
+	}
+
+	// Another instance should take over when the leader shuts down
+	err = leader.Shutdown(ctx)
+	assert.NoError(err)
+	var remaining []*Connector
+	for _, con := range cons {
+		if con != leader {
+			remaining = append(remaining, con)
+		}
+	}
+	successor := awaitLeader(remaining)
+	if !assert.NotNil(successor) {
+		return
+	}
+	assert.NotEqual(leader.ID(), successor.ID())
+
+	// The leader resigns when it no longer runs singleton tickers
+	err = successor.StopTicker("Job")
+	assert.NoError(err)
+	assert.False(successor.isLeader())
+	var last []*Connector
+	for _, con := range remaining {
+		if con != successor {
+			last = append(last, con)
+		}
+	}
+	assert.NotNil(awaitLeader(last))
+}
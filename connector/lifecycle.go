@@ -282,6 +282,9 @@ func (c *Connector) Shutdown(ctx context.Context) (err error) {
 		lastErr = errors.Trace(err)
 	}
 
+	// Hand over the leadership to another instance
+	c.stopLeaderElection(ctx)
+
 	// Deactivate the auto subscriptions. Manual subscriptions (the distributed cache plus
 	// anything the user marked sub.Manual) stay active so OnShutdown code can still use
 	// them. The connector tears down its own dlru-tagged group after OnShutdown returns;
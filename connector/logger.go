@@ -24,12 +24,14 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/env"
 	"github.com/microbus-io/fabric/mem"
+	"github.com/microbus-io/fabric/trc"
 
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
@@ -95,7 +97,8 @@ const (
 
 /*
 LogDebug logs a message at DEBUG level.
-DEBUG level messages are ignored in PROD environments or if the MICROBUS_LOG_DEBUG environment variable is not set.
+DEBUG level messages are ignored in PROD environments or if the MICROBUS_LOG_DEBUG environment variable is not set,
+unless the log level is changed at runtime via [Connector.SetLogLevel] or the :888/log-level control endpoint.
 The message should be static and concise. Optional arguments can be added for variable data.
 Arguments conform to the standard slog pattern.
 
@@ -185,7 +188,7 @@ func (c *Connector) initLogger(ctx context.Context) (err error) {
 		return nil
 	}
 
-	if v := env.Get("MICROBUS_LOG_DEBUG"); v == "1" || strings.EqualFold(v, "true") {
+	if v := env.Get("MICROBUS_LOG_DEBUG"); (v == "1" || strings.EqualFold(v, "true")) && c.Deployment() != PROD {
 		c.logDebug = true
 	}
 
@@ -204,25 +207,42 @@ func (c *Connector) initLogger(ctx context.Context) (err error) {
 			Level:     slog.LevelDebug,
 		})
 	default:
-		// Default PROD config
+		// Default PROD config. DEBUG level messages are gated by the log handler
 		console = slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{
 			AddSource: false,
-			Level:     slog.LevelInfo,
+			Level:     slog.LevelDebug,
 		})
 	}
 
+	sampling := env.Get("MICROBUS_LOG_SAMPLING")
+	if sampling == "" {
+		sampling = logSamplingDefaults[c.Deployment()]
+	}
+	first, every, err := parseLogSampling(sampling)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	otelLeg, err := c.initLogExporter(ctx)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
-	c.logger = slog.New(&logHandler{c: c, console: console, otel: otelLeg}).With(
+	handler := &logHandler{c: c, console: console, otel: otelLeg}
+	logger := slog.New(handler).With(
 		"plane", c.Plane(),
 		"service", c.Hostname(),
 		"ver", c.Version(),
 		"id", c.ID(),
 		"deployment", c.Deployment(),
 	)
+	if first > 0 {
+		c.logSampler = newLogSampler(first, every, 10*time.Second, func(key logSampleKey, suppressed int) {
+			logger.Log(context.Background(), key.level, suppressedLogsMsg, "message", key.msg, "suppressed", suppressed)
+		})
+		handler.sampler = c.logSampler
+	}
+	c.logger = logger
 	return nil
 }
 
@@ -261,10 +281,15 @@ func (c *Connector) initLogExporter(ctx context.Context) (slog.Handler, error) {
 	return otelslog.NewHandler("microbus", otelslog.WithLoggerProvider(c.logProvider)), nil
 }
 
-// termLogger flushes and shuts down the OTLP logs provider and releases its shared connection. The logger is reset so
-// a subsequent Startup rebuilds the chain. It is the first OpenTelemetry provider torn down on shutdown - the reverse
-// of initialization order, and after the final log entry - since logging feeds the trace and metric providers.
+// termLogger summarizes any suppressed log records, then flushes and shuts down the OTLP logs provider
+// and releases its shared connection. The logger is reset so a subsequent Startup rebuilds the chain.
+// It is the first OpenTelemetry provider torn down on shutdown - the reverse of initialization order,
+// and after the final log entry - since logging feeds the trace and metric providers.
 func (c *Connector) termLogger(ctx context.Context) (err error) {
+	if c.logSampler != nil {
+		c.logSampler.stop()
+		c.logSampler = nil
+	}
 	if c.logProvider != nil {
 		err = c.logProvider.Shutdown(ctx)
 		c.logProvider = nil
@@ -284,6 +309,9 @@ actor claims onto each record, mirroring the record onto the active span as an e
 dumping errors to stderr in developer deployments - and then fans the record out to the deployment's terminal
 (console/JSON) handler and, when configured, the OTLP logs handler.
 
+When log sampling is enabled, similar records beyond the allowed rate are suppressed from both legs after being
+counted, and periodically summarized instead. Records of force-traced requests are never suppressed.
+
 Enrichment happens here, in the handler, rather than in the LogXXX methods, so that records logged through the slog
 logger returned by Logger() are enriched identically. The non-context logger methods carry a background context with
 no span or actor, so they pass through un-enriched, which is the intended behavior.
@@ -296,11 +324,17 @@ type logHandler struct {
 	c       *Connector
 	console slog.Handler // Deployment terminal handler: colorful (LOCAL), text (TESTING), or JSON (LAB/PROD)
 	otel    slog.Handler // OTLP logs bridge, or nil when logs export is disabled
+	sampler *logSampler  // Rate limiter of similar records, or nil when log sampling is disabled
 }
 
-// Enabled gates DEBUG records on the MICROBUS_LOG_DEBUG flag and otherwise defers to whichever leg accepts the level.
+// Enabled gates records on the log level set at runtime, if it applies in the context, or else gates DEBUG records
+// on the MICROBUS_LOG_DEBUG flag. It otherwise defers to whichever leg accepts the level.
 func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	if level < slog.LevelInfo && !h.c.logDebug {
+	if override := h.c.logLevelOverrideOf(ctx); override != nil {
+		if level < override.level {
+			return false
+		}
+	} else if level < slog.LevelInfo && !h.c.logDebug {
 		return false
 	}
 	if h.console.Enabled(ctx, level) {
@@ -327,6 +361,9 @@ func (h *logHandler) Handle(ctx context.Context, rec slog.Record) error {
 		"message", rec.Message,
 		"severity", rec.Level.String(),
 	)
+	if h.sampler != nil && rec.Message != suppressedLogsMsg && !h.forceTraced(span) && !h.sampler.allow(rec.Level, rec.Message) {
+		return nil
+	}
 	if (h.c.deployment == LOCAL || h.c.deployment == TESTING) && rec.Level >= slog.LevelWarn {
 		dumpRecordError(h.c.deployment, rec)
 	}
@@ -357,8 +394,13 @@ func (h *logHandler) Handle(ctx context.Context, rec slog.Record) error {
 	return firstErr
 }
 
+// forceTraced indicates if the span belongs to a trace that had been selected for export, e.g. by ForceTrace.
+func (h *logHandler) forceTraced(span trc.Span) bool {
+	return !span.IsEmpty() && h.c.traceProcessor != nil && h.c.traceProcessor.IsSelected(span.TraceID())
+}
+
 func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	clone := &logHandler{c: h.c, console: h.console.WithAttrs(attrs)}
+	clone := &logHandler{c: h.c, console: h.console.WithAttrs(attrs), sampler: h.sampler}
 	if h.otel != nil {
 		clone.otel = h.otel.WithAttrs(attrs)
 	}
@@ -366,7 +408,7 @@ func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 }
 
 func (h *logHandler) WithGroup(name string) slog.Handler {
-	clone := &logHandler{c: h.c, console: h.console.WithGroup(name)}
+	clone := &logHandler{c: h.c, console: h.console.WithGroup(name), sampler: h.sampler}
 	if h.otel != nil {
 		clone.otel = h.otel.WithGroup(name)
 	}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+// defaultLogLevelDuration is how long a change of the log level lasts if no duration is specified.
+const defaultLogLevelDuration = 15 * time.Minute
+
+// defaultLogLevelClaims are the actor claims required of requests to the :888/log-level control endpoint,
+// unless overridden by the MICROBUS_LOG_LEVEL_CLAIMS env var.
+const defaultLogLevelClaims = "roles.admin"
+
+// logLevelOverride temporarily changes the minimum level of the messages that are logged.
+// It applies only to messages logged in the context of the trace or the baggage, if specified.
+type logLevelOverride struct {
+	level   slog.Level
+	until   time.Time
+	traceID string
+	baggage map[string]string
+}
+
+// SetLogLevel changes the minimum level of the messages logged by the microservice to DEBUG, INFO, WARN or ERROR,
+// overriding the level determined by the deployment and the MICROBUS_LOG_DEBUG env var.
+// The change reverts automatically after the duration, which defaults to 15 minutes.
+// If a trace ID or baggage are specified, the change applies only to messages logged in the context of
+// requests that carry that trace ID and all of those baggage values.
+// An empty level reverts the change immediately.
+func (c *Connector) SetLogLevel(level string, duration time.Duration, traceID string, baggage map[string]string) error {
+	if level == "" {
+		c.logOverride.Store(nil)
+		return nil
+	}
+	var lvl slog.Level
+	err := lvl.UnmarshalText([]byte(level))
+	if err != nil {
+		return errors.New("invalid log level '%s'", level, http.StatusBadRequest)
+	}
+	if duration < 0 {
+		return errors.New("negative duration '%v'", duration, http.StatusBadRequest)
+	}
+	if duration == 0 {
+		duration = defaultLogLevelDuration
+	}
+	override := &logLevelOverride{
+		level:   lvl,
+		until:   time.Now().Add(duration),
+		traceID: strings.ToLower(traceID),
+	}
+	if len(baggage) > 0 {
+		override.baggage = make(map[string]string, len(baggage))
+		for name, value := range baggage {
+			override.baggage[name] = value
+		}
+	}
+	c.logOverride.Store(override)
+	return nil
+}
+
+// logLevelOverrideOf returns the override of the log level that applies in the context, or nil if none applies.
+// An expired override is cleared.
+func (c *Connector) logLevelOverrideOf(ctx context.Context) *logLevelOverride {
+	override := c.logOverride.Load()
+	if override == nil {
+		return nil
+	}
+	if time.Now().After(override.until) {
+		c.logOverride.CompareAndSwap(override, nil)
+		return nil
+	}
+	if override.traceID != "" && c.Span(ctx).TraceID() != override.traceID {
+		return nil
+	}
+	if len(override.baggage) > 0 {
+		f := frame.Of(ctx)
+		for name, value := range override.baggage {
+			if f.Baggage(name) != value {
+				return nil
+			}
+		}
+	}
+	return override
+}
+
+// logLevelClaims returns the actor claims required of requests to the :888/log-level control endpoint.
+func logLevelClaims() string {
+	if claims := env.Get("MICROBUS_LOG_LEVEL_CLAIMS"); claims != "" {
+		return claims
+	}
+	return defaultLogLevelClaims
+}
+
+// handleControlLogLevel handles the control request to change the log level of the microservice.
+func (c *Connector) handleControlLogLevel(w http.ResponseWriter, r *http.Request) error {
+	var payload struct {
+		Level    string            `json:"level"`
+		Duration time.Duration     `json:"duration"`
+		TraceID  string            `json:"traceID"`
+		Baggage  map[string]string `json:"baggage"`
+	}
+	err := httpx.ParseRequestBody(r, &payload)
+	if err != nil {
+		return errors.Trace(err, http.StatusBadRequest)
+	}
+	err = c.SetLogLevel(payload.Level, payload.Duration, payload.TraceID, payload.Baggage)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.LogInfo(r.Context(), "Log level changed",
+		"level", payload.Level,
+		"duration", payload.Duration,
+		"traceID", payload.TraceID,
+		"baggage", payload.Baggage,
+	)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+	return nil
+}
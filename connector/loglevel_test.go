@@ -0,0 +1,177 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestConnector_LogLevel(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	con := New("log.level.connector")
+	con.Subscribe("Hello",
+		func(w http.ResponseWriter, r *http.Request) error {
+			con.LogDebug(r.Context(), "Debugging hello")
+			return nil
+		},
+		sub.At("GET", "/hello"),
+		sub.Web(),
+	)
+	client := New("client.log.level.connector")
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// Capture the logs
+	var buf strings.Builder
+	var mux sync.Mutex
+	logged := func(msg string) bool {
+		mux.Lock()
+		defer mux.Unlock()
+		found := strings.Contains(buf.String(), `msg="`+msg+`"`)
+		buf.Reset()
+		return found
+	}
+	con.logger = slog.New(&logHandler{c: con, console: slog.NewTextHandler(writerFunc(func(p []byte) (int, error) {
+		mux.Lock()
+		defer mux.Unlock()
+		return buf.Write(p)
+	}), &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})})
+	con.logDebug = false
+
+	con.LogDebug(ctx, "Debug message")
+	assert.False(logged("Debug message"))
+
+	// Debug everywhere
+	err = con.SetLogLevel("debug", 0, "", nil)
+	assert.NoError(err)
+	con.LogDebug(ctx, "Debug message")
+	assert.True(logged("Debug message"))
+
+	// Errors only
+	err = con.SetLogLevel("ERROR", time.Minute, "", nil)
+	assert.NoError(err)
+	con.LogInfo(ctx, "Info message")
+	assert.False(logged("Info message"))
+	con.LogError(ctx, "Error message")
+	assert.True(logged("Error message"))
+
+	// Revert
+	err = con.SetLogLevel("", 0, "", nil)
+	assert.NoError(err)
+	con.LogInfo(ctx, "Info message")
+	assert.True(logged("Info message"))
+
+	// Expiration
+	err = con.SetLogLevel("DEBUG", 50*time.Millisecond, "", nil)
+	assert.NoError(err)
+	con.LogDebug(ctx, "Debug message")
+	assert.True(logged("Debug message"))
+	time.Sleep(100 * time.Millisecond)
+	con.LogDebug(ctx, "Debug message")
+	assert.False(logged("Debug message"))
+	assert.Nil(con.logOverride.Load())
+
+	// Scoped to a trace
+	traceID, _ := trace.TraceIDFromHex("0123456789abcdef0123456789abcdef")
+	spanID, _ := trace.SpanIDFromHex("0123456789abcdef")
+	tracedCtx := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	err = con.SetLogLevel("DEBUG", time.Minute, "0123456789ABCDEF0123456789ABCDEF", nil)
+	assert.NoError(err)
+	con.LogDebug(ctx, "Debug message")
+	assert.False(logged("Debug message"))
+	con.LogDebug(tracedCtx, "Debug message")
+	assert.True(logged("Debug message"))
+
+	// Scoped to baggage, set via the control endpoint
+	admin := pub.Actor(map[string]any{"roles": map[string]any{"admin": true}})
+	_, err = client.Request(ctx,
+		pub.POST("https://log.level.connector:888/log-level"),
+		pub.Body(map[string]any{"level": "DEBUG"}),
+	)
+	assert.Equal(http.StatusUnauthorized, errors.StatusCode(err))
+	_, err = client.Request(ctx,
+		pub.POST("https://log.level.connector:888/log-level"),
+		pub.Body(map[string]any{
+			"level":   "DEBUG",
+			"baggage": map[string]string{"Debug": "on"},
+		}),
+		admin,
+	)
+	assert.NoError(err)
+	_, err = client.Request(ctx, pub.GET("https://log.level.connector/hello"))
+	assert.NoError(err)
+	assert.False(logged("Debugging hello"))
+	_, err = client.Request(ctx, pub.GET("https://log.level.connector/hello"), pub.Baggage("Debug", "on"))
+	assert.NoError(err)
+	assert.True(logged("Debugging hello"))
+
+	// A CBOR body is decoded through its codec
+	cborBody, err := httpx.CBOR.Marshal(map[string]any{"level": "DEBUG"})
+	assert.NoError(err)
+	_, err = client.Request(ctx,
+		pub.POST("https://log.level.connector:888/log-level"),
+		pub.ContentType(httpx.CBOR.ContentType()),
+		pub.Body(cborBody),
+		admin,
+	)
+	assert.NoError(err)
+	_, err = client.Request(ctx, pub.GET("https://log.level.connector/hello"))
+	assert.NoError(err)
+	assert.True(logged("Debugging hello"))
+
+	// Invalid requests
+	_, err = client.Request(ctx,
+		pub.POST("https://log.level.connector:888/log-level"),
+		pub.Body(map[string]any{"level": "LOUD"}),
+		admin,
+	)
+	assert.Equal(http.StatusBadRequest, errors.StatusCode(err))
+	err = con.SetLogLevel("DEBUG", -time.Second, "", nil)
+	assert.Error(err)
+}
+
+// writerFunc adapts a function to an io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
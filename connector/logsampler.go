@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/errors"
+)
+
+const (
+	// suppressedLogsMsg is the message of the summary record logged in place of suppressed records.
+	suppressedLogsMsg = "Suppressed similar log messages"
+	// maxLogSampleKeys bounds the number of distinct messages tracked by the sampler.
+	maxLogSampleKeys = 4096
+)
+
+// logSamplingDefaults are the log sampling settings of each deployment, in the format of the MICROBUS_LOG_SAMPLING env var.
+// Sampling is disabled in developer deployments, where every record counts.
+var logSamplingDefaults = map[string]string{
+	PROD:    "10,100",
+	LAB:     "100,10",
+	LOCAL:   "off",
+	TESTING: "off",
+}
+
+// parseLogSampling parses a log sampling setting of the format "first,every", where first is the number of similar
+// records logged each second before sampling kicks in, and every is the sampling ratio thereafter. A ratio of 0
+// suppresses all records beyond the first. A return value of 0 for first indicates that sampling is disabled.
+func parseLogSampling(setting string) (first int, every int, err error) {
+	setting = strings.TrimSpace(setting)
+	if setting == "" || strings.EqualFold(setting, "off") || setting == "0" {
+		return 0, 0, nil
+	}
+	firstStr, everyStr, ok := strings.Cut(setting, ",")
+	if !ok {
+		return 0, 0, errors.New("invalid log sampling '%s', expected 'first,every'", setting, http.StatusBadRequest)
+	}
+	first, err = strconv.Atoi(strings.TrimSpace(firstStr))
+	if err != nil || first < 0 {
+		return 0, 0, errors.New("invalid log sampling '%s', expected 'first,every'", setting, http.StatusBadRequest)
+	}
+	every, err = strconv.Atoi(strings.TrimSpace(everyStr))
+	if err != nil || every < 0 {
+		return 0, 0, errors.New("invalid log sampling '%s', expected 'first,every'", setting, http.StatusBadRequest)
+	}
+	return first, every, nil
+}
+
+// logSampleKey identifies similar log records by their level and message template.
+type logSampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+/*
+logSampler limits the rate of similar log records, which are identified by their level and message template.
+In each one-second window, the first records of each message are allowed, and thereafter only one in every so many.
+Suppressed records are counted and periodically summarized in a single record per message.
+*/
+type logSampler struct {
+	first     int
+	every     int
+	interval  time.Duration
+	summarize func(key logSampleKey, suppressed int)
+
+	mux        sync.Mutex
+	window     int64
+	counts     map[logSampleKey]int
+	suppressed map[logSampleKey]int
+	timer      *time.Timer
+	stopped    bool
+}
+
+// newLogSampler creates a new log sampler that allows the first records of each message per second and one in every
+// so many thereafter. Suppressed records are reported to the summarize callback at the interval.
+func newLogSampler(first int, every int, interval time.Duration, summarize func(key logSampleKey, suppressed int)) *logSampler {
+	return &logSampler{
+		first:      first,
+		every:      every,
+		interval:   interval,
+		summarize:  summarize,
+		counts:     map[logSampleKey]int{},
+		suppressed: map[logSampleKey]int{},
+	}
+}
+
+// allow returns true if the record should be logged, or false if it should be suppressed.
+func (s *logSampler) allow(level slog.Level, msg string) bool {
+	key := logSampleKey{level: level, msg: msg}
+	now := time.Now().Unix()
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if now != s.window || len(s.counts) >= maxLogSampleKeys {
+		s.window = now
+		clear(s.counts)
+	}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	if n <= s.first || (s.every > 0 && (n-s.first)%s.every == 0) {
+		return true
+	}
+	if _, ok := s.suppressed[key]; !ok && len(s.suppressed) >= maxLogSampleKeys {
+		return false
+	}
+	s.suppressed[key]++
+	if s.timer == nil && !s.stopped {
+		s.timer = time.AfterFunc(s.interval, s.flush)
+	}
+	return false
+}
+
+// flush reports the records suppressed since the last flush.
+func (s *logSampler) flush() {
+	s.mux.Lock()
+	suppressed := s.suppressed
+	s.suppressed = map[logSampleKey]int{}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.mux.Unlock()
+	for key, n := range suppressed {
+		s.summarize(key, n)
+	}
+}
+
+// stop stops the periodic summaries and reports any outstanding suppressed records.
+func (s *logSampler) stop() {
+	s.mux.Lock()
+	s.stopped = true
+	s.mux.Unlock()
+	s.flush()
+}
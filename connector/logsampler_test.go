@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestConnector_LogSampling(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	con := New("log.sampling.connector")
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// Capture the logs
+	var buf strings.Builder
+	var mux sync.Mutex
+	count := func(msg string) int {
+		mux.Lock()
+		defer mux.Unlock()
+		n := strings.Count(buf.String(), `msg="`+msg+`"`)
+		return n
+	}
+	reset := func() {
+		mux.Lock()
+		defer mux.Unlock()
+		buf.Reset()
+	}
+	handler := &logHandler{c: con, console: slog.NewTextHandler(writerFunc(func(p []byte) (int, error) {
+		mux.Lock()
+		defer mux.Unlock()
+		return buf.Write(p)
+	}), &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})}
+	con.logger = slog.New(handler)
+	handler.sampler = newLogSampler(5, 10, 100*time.Millisecond, func(key logSampleKey, suppressed int) {
+		con.logger.Log(ctx, key.level, suppressedLogsMsg, "message", key.msg, "suppressed", suppressed)
+	})
+
+	// Wait for the beginning of a second to avoid crossing into the next sampling window
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	// First 5 then 1 in 10
+	for range 50 {
+		con.LogError(ctx, "Hot error path")
+	}
+	con.LogError(ctx, "Cold error path")
+	assert.Equal(5+4, count("Hot error path"))
+	assert.Equal(1, count("Cold error path"))
+
+	// Summary of the suppressed messages
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(1, count(suppressedLogsMsg))
+	mux.Lock()
+	assert.Contains(buf.String(), "suppressed=41")
+	mux.Unlock()
+	reset()
+
+	// Force-traced requests are never suppressed
+	exp := &exporter{}
+	con.traceProcessor = newSelectiveProcessor(exp, 16)
+	traceID, _ := trace.TraceIDFromHex("0123456789abcdef0123456789abcdef")
+	spanID, _ := trace.SpanIDFromHex("0123456789abcdef")
+	tracedCtx := trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	con.traceProcessor.Select(traceID.String())
+	for range 20 {
+		con.LogError(tracedCtx, "Traced error path")
+	}
+	assert.Equal(20, count("Traced error path"))
+
+	// Outstanding suppressed messages are summarized on stop
+	reset()
+	for range 20 {
+		con.LogWarn(ctx, "Warm warning path")
+	}
+	handler.sampler.stop()
+	assert.Equal(1, count(suppressedLogsMsg))
+}
+
+func TestConnector_LogSamplingOfFailedRequests(t *testing.T) {
+	// No parallel - Setting envars
+	env.Push("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "nil")
+	defer env.Pop("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+
+	assert := testarossa.For(t)
+	ctx := t.Context()
+
+	con := New("failed.log.sampling.connector")
+	con.SetDeployment(PROD)
+	con.Subscribe("Fail",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return errors.New("failed")
+		},
+		sub.At("GET", "fail"),
+		sub.Web(),
+	)
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// Capture the logs
+	var buf strings.Builder
+	var mux sync.Mutex
+	handler := &logHandler{c: con, console: slog.NewTextHandler(writerFunc(func(p []byte) (int, error) {
+		mux.Lock()
+		defer mux.Unlock()
+		return buf.Write(p)
+	}), &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	})}
+	con.logger = slog.New(handler)
+	handler.sampler = newLogSampler(5, 10, time.Minute, func(key logSampleKey, suppressed int) {})
+	defer handler.sampler.stop()
+
+	// The trace of a failed request is selected before the error is logged, so the error is never suppressed
+	for range 20 {
+		_, err = con.GET(ctx, "https://failed.log.sampling.connector/fail")
+		assert.Error(err)
+	}
+	mux.Lock()
+	assert.Equal(20, strings.Count(buf.String(), `msg="Handling request"`))
+	mux.Unlock()
+}
+
+func TestConnector_ParseLogSampling(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	first, every, err := parseLogSampling("10,100")
+	assert.Expect(first, 10, every, 100, err, nil)
+	first, every, err = parseLogSampling(" 5 , 0 ")
+	assert.Expect(first, 5, every, 0, err, nil)
+	for _, off := range []string{"", "off", "OFF", "0"} {
+		first, _, err = parseLogSampling(off)
+		assert.Expect(first, 0, err, nil)
+	}
+	for _, bad := range []string{"10", "x,1", "1,x", "-1,1", "1,-1"} {
+		_, _, err = parseLogSampling(bad)
+		assert.Error(err, bad)
+	}
+}
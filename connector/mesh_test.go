@@ -0,0 +1,95 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Mesh(t *testing.T) {
+	// No t.Parallel because of env.Push
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Reserve a port for the first node so that the second can list it as a peer
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(err) {
+		return
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	env.Push("MICROBUS_SHORT_CIRCUIT", "0")
+	defer env.Pop("MICROBUS_SHORT_CIRCUIT")
+	env.Push("MICROBUS_MESH", addr)
+	defer env.Pop("MICROBUS_MESH")
+
+	server := New("server.mesh.connector")
+	server.Subscribe("Hello",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Write([]byte("Hello from " + server.ID()))
+			return nil
+		},
+		sub.At("GET", "/hello"),
+		sub.Web(),
+	)
+	err = server.Startup(ctx)
+	assert.NoError(err)
+	defer server.Shutdown(ctx)
+
+	env.Push("MICROBUS_MESH", "127.0.0.1:0")
+	defer env.Pop("MICROBUS_MESH")
+	env.Push("MICROBUS_MESH_PEERS", addr)
+	defer env.Pop("MICROBUS_MESH_PEERS")
+	client := New("client.mesh.connector")
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	// Requests cross the mesh once the nodes are linked
+	var res *http.Response
+	for range 100 {
+		res, err = client.Request(ctx, pub.GET("https://server.mesh.connector/hello"))
+		if err == nil {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal("Hello from "+server.ID(), string(body))
+	}
+
+	// Multicasts reach the subscribers across the mesh
+	count := 0
+	for r := range client.Publish(ctx, pub.GET("https://server.mesh.connector/hello"), pub.Multicast()) {
+		_, err := r.Get()
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(1, count)
+}
@@ -144,6 +144,23 @@ func (c *Connector) initMeter(ctx context.Context) (err error) {
 		"Response body size [bytes]",
 		[]float64{1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20},
 	)
+	c.DescribeGauge(
+		"microbus_server_concurrent_requests",
+		"Number of requests being processed by a subscription that limits its concurrency",
+	)
+	c.DescribeGauge(
+		"microbus_server_queued_requests",
+		"Number of requests waiting for a subscription that limits its concurrency",
+	)
+	c.DescribeHistogram(
+		"microbus_server_queue_wait_duration_seconds",
+		"Duration requests waited for a subscription that limits its concurrency [seconds]",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10},
+	)
+	c.DescribeCounter(
+		"microbus_server_shed_requests",
+		"Number of requests shed by a saturated subscription",
+	)
 	c.DescribeCounter(
 		"microbus_client_timeout_requests",
 		"Number of requests downstream that timed out",
@@ -153,6 +170,15 @@ func (c *Connector) initMeter(ctx context.Context) (err error) {
 		"Downstream ack roundtrip latency [seconds]",
 		[]float64{0.001, 0.0025, 0.005, 0.0075, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5},
 	)
+	c.DescribeHistogram(
+		"microbus_compression_ratio",
+		"Ratio of compressed to uncompressed payload size",
+		[]float64{0.05, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1},
+	)
+	c.DescribeGauge(
+		"microbus_client_circuit_breaker_state",
+		"State of the circuit breaker of a downstream destination: 0 closed, 1 half-open, 2 open",
+	)
 	c.DescribeCounter(
 		"microbus_log_messages",
 		"Number of log messages recorded",
@@ -262,6 +288,7 @@ func (c *Connector) observeMetricsJustInTime(ctx context.Context) error {
 	_ = c.RecordGauge(ctx, "microbus_uptime_duration_seconds", uptime.Seconds())
 	_ = c.RecordGauge(ctx, "microbus_cache_elements", float64(c.distribCache.LocalCache().Len()))
 	_ = c.RecordGauge(ctx, "microbus_cache_memory_bytes", float64(c.distribCache.LocalCache().Weight()))
+	c.observeCircuitBreakers(ctx)
 
 	if c.onObserveMetrics == nil {
 		return nil
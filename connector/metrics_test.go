@@ -182,12 +182,18 @@ func TestConnector_StandardMetrics(t *testing.T) {
 	assert.NoError(err)
 	defer con.Shutdown(ctx)
 
-	assert.Len(con.metricInstruments, 10)
+	assert.Len(con.metricInstruments, 16)
 	assert.NotNil(con.metricInstruments["microbus_callback_duration_seconds"])
 	assert.NotNil(con.metricInstruments["microbus_server_request_duration_seconds"])
 	assert.NotNil(con.metricInstruments["microbus_server_response_body_bytes"])
+	assert.NotNil(con.metricInstruments["microbus_server_concurrent_requests"])
+	assert.NotNil(con.metricInstruments["microbus_server_queued_requests"])
+	assert.NotNil(con.metricInstruments["microbus_server_queue_wait_duration_seconds"])
+	assert.NotNil(con.metricInstruments["microbus_server_shed_requests"])
 	assert.NotNil(con.metricInstruments["microbus_client_timeout_requests"])
 	assert.NotNil(con.metricInstruments["microbus_client_ack_roundtrip_latency_seconds"])
+	assert.NotNil(con.metricInstruments["microbus_client_circuit_breaker_state"])
+	assert.NotNil(con.metricInstruments["microbus_compression_ratio"])
 	assert.NotNil(con.metricInstruments["microbus_log_messages"])
 	assert.NotNil(con.metricInstruments["microbus_uptime_duration_seconds"])
 	assert.NotNil(con.metricInstruments["microbus_cache_memory_bytes"])
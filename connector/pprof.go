@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/env"
+)
+
+// defaultPprofClaims are the actor claims required of requests to the :888/pprof control endpoint,
+// unless overridden by the MICROBUS_PPROF_CLAIMS env var.
+const defaultPprofClaims = "roles.admin"
+
+// pprofClaims returns the actor claims required of requests to the :888/pprof control endpoint.
+func pprofClaims() string {
+	if claims := env.Get("MICROBUS_PPROF_CLAIMS"); claims != "" {
+		return claims
+	}
+	return defaultPprofClaims
+}
+
+/*
+handleControlPprof responds to the :888/pprof/{profile} control request with a runtime profile in the format of
+go tool pprof. The profile is of the entire process, which may be hosting other microservices as well.
+
+The profile is one of the runtime profiles, such as heap, goroutine, allocs, block, mutex or threadcreate,
+or "profile" for a CPU profile or "trace" for an execution trace that are collected over a duration set in the
+seconds query argument, 10 by default. The debug query argument requests a textual format of runtime profiles,
+and the gc query argument runs garbage collection before taking a heap profile.
+*/
+func (c *Connector) handleControlPprof(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := r.PathValue("profile")
+	switch name {
+	case "profile", "trace":
+		seconds := 10
+		if v := r.URL.Query().Get("seconds"); v != "" {
+			var err error
+			seconds, err = strconv.Atoi(v)
+			if err != nil || seconds <= 0 {
+				return errors.New("invalid seconds '%s'", v, http.StatusBadRequest)
+			}
+		}
+		duration := time.Duration(seconds) * time.Second
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < duration+time.Second {
+			return errors.New("duration of %ds exceeds the time budget of the request", seconds, http.StatusBadRequest)
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+		start, stop := pprof.StartCPUProfile, pprof.StopCPUProfile
+		if name == "trace" {
+			start, stop = trace.Start, trace.Stop
+		}
+		err := start(w)
+		if err != nil {
+			return errors.New("%s already in progress", name, http.StatusConflict)
+		}
+		timer := time.NewTimer(duration)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		stop()
+		return errors.Trace(ctx.Err())
+	default:
+		profile := pprof.Lookup(name)
+		if profile == nil {
+			return errors.New("unknown profile '%s'", name, http.StatusNotFound)
+		}
+		debug, _ := strconv.Atoi(r.URL.Query().Get("debug"))
+		if name == "heap" && r.URL.Query().Get("gc") != "" {
+			runtime.GC()
+		}
+		if debug > 0 {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+		}
+		err := profile.WriteTo(w, debug)
+		return errors.Trace(err)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Pprof(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	con := New("pprof.connector")
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	admin := pub.Actor(map[string]any{"roles": map[string]any{"admin": true}})
+
+	// Without the required claims
+	_, err = con.Request(ctx, pub.GET("https://pprof.connector:888/pprof/heap"))
+	assert.Equal(http.StatusUnauthorized, errors.StatusCode(err))
+
+	// Runtime profile in textual format
+	res, err := con.Request(ctx, pub.GET("https://pprof.connector:888/pprof/goroutine?debug=1"), admin)
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.True(strings.HasPrefix(string(body), "goroutine profile:"))
+	}
+
+	// Addressable by instance ID
+	res, err = con.Request(ctx, pub.GET("https://"+con.ID()+".pprof.connector:888/pprof/heap?gc=1"), admin)
+	if assert.NoError(err) {
+		assert.Equal("application/octet-stream", res.Header.Get("Content-Type"))
+	}
+
+	// CPU profile
+	res, err = con.Request(ctx, pub.GET("https://pprof.connector:888/pprof/profile?seconds=1"), admin)
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.True(len(body) > 0)
+	}
+
+	// Invalid requests
+	_, err = con.Request(ctx, pub.GET("https://pprof.connector:888/pprof/nonexistent"), admin)
+	assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	_, err = con.Request(ctx, pub.GET("https://pprof.connector:888/pprof/profile?seconds=0"), admin)
+	assert.Equal(http.StatusBadRequest, errors.StatusCode(err))
+	_, err = con.Request(ctx, pub.GET("https://pprof.connector:888/pprof/trace?seconds=3600"), admin)
+	assert.Equal(http.StatusBadRequest, errors.StatusCode(err))
+}
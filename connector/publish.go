@@ -21,8 +21,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"iter"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +38,10 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 )
 
+// errAckTimeout is the cause of the error returned when no responder acks a unicast request.
+// Unlike an error returned by a responder, it indicates that the request was not processed.
+var errAckTimeout = errors.New("ack timeout")
+
 // transferChan is intermediating between the publisher and the responses it receives.
 type transferChan struct {
 	C    chan *http.Response
@@ -114,6 +120,15 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) iter.Seq
 		err = errors.Trace(err, c.Span(ctx).TraceID())
 		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
 	}
+	if req.Hedge > 0 && !req.Multicast {
+		// An idempotency key does not qualify because only endpoints that opt in deduplicate by it
+		switch req.Method {
+		case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		default:
+			err = errors.New("hedging requires an idempotent method: %s", req.Method, c.Span(ctx).TraceID())
+			return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+		}
+	}
 
 	// Set depth
 	outboundFrame.SetCallDepth(depth + 1)
@@ -131,9 +146,58 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) iter.Seq
 		outboundFrame.Set(k, v[0])
 	}
 
-	// Locality-aware routing.
-	optimizeLocality := !req.Multicast && c.locality != ""
+	// Persist durable events in their stream
+	if req.Durable && req.Multicast && c.transportConn.DurableEnabled() {
+		return c.publishDurable(ctx, req)
+	}
+
+	// Serve from the client-side response cache, or revalidate a stale cached response
+	cacheKey := ""
+	var staleResponse *cachedResponse
+	if req.CacheTTL > 0 && !req.Multicast && req.Method == "GET" && req.Header.Get("If-None-Match") == "" {
+		cacheKey = responseCacheKey(req)
+		if cached, ok := c.responseCache.Load(cacheKey); ok {
+			if time.Now().Before(cached.expires) {
+				return pub.NewSoloResponseQueue(pub.NewHTTPResponse(cached.response()))
+			}
+			if cached.eTag != "" {
+				// Revalidate the stale cached response
+				staleResponse = cached
+				req.Header.Set("If-None-Match", cached.eTag)
+			}
+		}
+	}
+
+	// Share the response of a pending identical request, or lead a new flight of identical requests
+	coalesceKey := ""
+	var flight *coalescedFlight
+	if req.Coalesce && !req.Multicast {
+		coalesceKey, err = coalesceKeyOf(req)
+		if err != nil {
+			err = errors.Trace(err, c.Span(ctx).TraceID())
+			return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+		}
+		var shared *pub.Response
+		shared, flight = c.beginCoalesced(ctx, coalesceKey)
+		if shared != nil {
+			return pub.NewSoloResponseQueue(shared)
+		}
+	}
+
+	// Version-aware routing
 	origURL := req.URL
+	version, pinnedVersion, routedVersion := 0, false, false
+	if !req.Multicast {
+		version, pinnedVersion, routedVersion = c.routeVersion(req)
+	}
+	if routedVersion {
+		// Adjust the hostname to include the version, e.g. example.com -> ver-7.example.com
+		before, after, _ := strings.Cut(origURL, "://")
+		req.URL = before + "://" + versionPrefix + strconv.Itoa(version) + "." + after
+	}
+
+	// Locality-aware routing.
+	optimizeLocality := !req.Multicast && c.locality != "" && !routedVersion
 	localityCacheKey := ""
 	lastKnownLocality := ""
 	if optimizeLocality {
@@ -148,7 +212,26 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) iter.Seq
 	}
 
 	// Make the request
-	queue := c.makeRequest(ctx, req)
+	queue := c.makeRequestWithRetry(ctx, req)
+
+	// Version-aware routing
+	if routedVersion && !pinnedVersion {
+		var res *pub.Response
+		queue(func(r *pub.Response) bool {
+			res = r
+			return false
+		})
+		if res != nil {
+			if _, err := res.Get(); errors.Is(err, errAckTimeout) {
+				// No replica of the version acked the request so retry at the original URL.
+				// Errors returned by a replica, even a 404, are not retried because the request may have been processed
+				req.URL = origURL
+				queue = c.makeRequestWithRetry(ctx, req)
+			} else {
+				queue = pub.NewSoloResponseQueue(res)
+			}
+		}
+	}
 
 	// Locality-aware routing
 	if optimizeLocality {
@@ -165,7 +248,7 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) iter.Seq
 			c.localResponder.Delete(localityCacheKey)
 			lastKnownLocality = ""
 			req.URL = origURL
-			queue = c.makeRequest(ctx, req)
+			queue = c.makeRequestWithRetry(ctx, req)
 			res, _ = firstResponse(queue).Get()
 		}
 		responseLocality := frame.Of(res).Locality()
@@ -189,12 +272,35 @@ func (c *Connector) Publish(ctx context.Context, options ...pub.Option) iter.Seq
 		}
 	}
 
+	// Store the response in the client-side response cache
+	if cacheKey != "" {
+		queue = c.cacheResponse(ctx, cacheKey, req, staleResponse, queue)
+	}
+
+	// Share the response with identical requests that arrived while it was pending
+	if flight != nil {
+		queue = c.endCoalesced(ctx, coalesceKey, flight, queue)
+	}
+
 	// Return the iterator
 	return queue
 }
 
 // makeRequest makes an HTTP request over NATS, then awaits and pushes the responses to the output channel.
 func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+	// Buffer the body of a hedged request so that it can be published twice
+	hedge := req.Hedge > 0 && !req.Multicast
+	if hedge && req.Body != nil && req.Body != http.NoBody {
+		if _, ok := req.Body.(*httpx.BodyReader); !ok {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				err = errors.Trace(err, c.Span(ctx).TraceID())
+				return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+			}
+			req.Body = httpx.NewBodyReader(body)
+		}
+	}
+
 	// Prepare the HTTP request (first fragment only)
 	httpReq, err := http.NewRequest(req.Method, req.URL, req.Body)
 	if err != nil {
@@ -226,6 +332,29 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 	timeout = min(timeout, c.maxTimeBudget)
 	frame.Of(httpReq).SetTimeBudget(timeout)
 
+	// Fault injection: delay, fail or drop the request before it is sent
+	dropped, err := c.injectOutgoingFault(ctx, httpReq)
+	if err != nil {
+		err = errors.Trace(err, c.Span(ctx).TraceID())
+		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+	}
+	if dropped {
+		if req.Multicast {
+			noResponses := pub.NewResponseQueue(0)
+			noResponses.Close()
+			return noResponses.Q()
+		}
+		err = errors.New(
+			"%w: %s", errAckTimeout, req.Canonical(),
+			http.StatusNotFound,
+			c.Span(ctx).TraceID(),
+		)
+		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+	}
+
+	// Accept compressed responses
+	frame.Of(httpReq).SetAcceptEncoding(compressionEncodings...)
+
 	// Fragment large requests
 	fragger, err := httpx.NewFragRequest(httpReq, c.maxFragmentSize)
 	if err != nil {
@@ -271,12 +400,51 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 
 	frame.Of(httpReq).SetMessageID(msgID)
 
+	// Fail fast if the circuit breaker of the destination is open
+	var breakerKey string
+	var breaker *circuitBreakerState
+	if !req.Multicast && !strings.HasPrefix(idOrLocality, idPrefix) {
+		// Requests addressed to a specific replica by its ID are not guarded
+		breakerHost := host
+		if isVersionSlot(idOrLocality) {
+			// Each version is a separate destination
+			breakerHost = idOrLocality + "." + host
+		}
+		breakerKey, breaker = c.circuitBreakerFor(breakerHost, port, httpReq.URL.Path)
+		if breaker != nil && !c.allowRequest(ctx, breakerKey, breaker) {
+			releaseAwaitCh()
+			err = errors.New(
+				"circuit open: %s", req.Canonical(),
+				http.StatusServiceUnavailable,
+				c.Span(ctx).TraceID(),
+			)
+			return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+		}
+	}
+
 	c.LogDebug(ctx, "Request",
 		"msg", msgID,
 		"url", req.Canonical(),
 		"method", req.Method,
 	)
 
+	// Prepare a copy of the request to publish in case the original is slow to respond
+	var hedgeReq *http.Request
+	if hedge && fragger.N() == 1 && req.Hedge < timeout {
+		var body io.Reader
+		if br, ok := httpReq.Body.(*httpx.BodyReader); ok {
+			body = httpx.NewBodyReader(br.Bytes())
+		}
+		hedgeReq, err = http.NewRequest(httpReq.Method, httpReq.URL.String(), body)
+		if err != nil {
+			releaseAwaitCh()
+			err = errors.Trace(err, c.Span(ctx).TraceID())
+			return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+		}
+		hedgeReq.Header = httpReq.Header.Clone()
+		hedgeReq.ContentLength = httpReq.ContentLength
+	}
+
 	publishTime := time.Now()
 	if req.Multicast {
 		err = c.transportConn.Publish(subject, httpReq)
@@ -285,6 +453,9 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 	}
 	if err != nil {
 		releaseAwaitCh()
+		if breaker != nil {
+			c.recordOutcome(ctx, breakerKey, breaker, true)
+		}
 		err = errors.Trace(err, c.Span(ctx).TraceID())
 		return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
 	}
@@ -318,6 +489,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 	// Wait for the responses in a separate goroutine
 	var output *pub.ResponseQueue
 	var soloResponse *pub.Response
+	ackTimedOut := false
 	if req.Multicast {
 		output = pub.NewResponseQueue(c.multicastChanCap)
 	}
@@ -330,6 +502,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 		}()
 		countResponses := 0
 		seenIDs := map[string]string{} // FromID -> OpCode
+		cancelableIDs := map[string]bool{}
 		seenQueues := map[string]bool{}
 		doneWaitingForAcks := false
 		var timeoutTimer *time.Timer
@@ -343,9 +516,39 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 			}
 		}
 
+		hedgeTimer := &time.Timer{
+			C: make(<-chan time.Time),
+		}
+		if hedgeReq != nil {
+			hedgeTimer = time.NewTimer(req.Hedge)
+			defer hedgeTimer.Stop()
+		}
+
 		ackTimer := time.NewTimer(c.ackTimeout)
 		defer ackTimer.Stop()
 		ackTimerStart := time.Now()
+
+		// Expiration of the deadline of the context is handled by the timeout timer
+		canceledCh := make(chan struct{})
+		stopCanceled := context.AfterFunc(ctx, func() {
+			if ctx.Err() == context.Canceled {
+				close(canceledCh)
+			}
+		})
+		defer stopCanceled()
+
+		// pendingResponders returns the IDs of the responders that acked the request but have not yet responded to it.
+		// Only responders that advertised support for cancellation in their ack are included
+		pendingResponders := func() []string {
+			var ids []string
+			for id, op := range seenIDs {
+				if op == frame.OpCodeAck && cancelableIDs[id] {
+					ids = append(ids, id)
+				}
+			}
+			return ids
+		}
+
 		fragmentsSent := map[string]bool{}
 		for {
 			select {
@@ -385,6 +588,7 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 							"port", port,
 						)
 						seenIDs[fromID] = frame.OpCodeAck
+						cancelableIDs[fromID] = frame.Of(response).Cancelable()
 					}
 
 					// Send additional fragments (if there are any) to all those who ack'ed
@@ -460,7 +664,11 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 				// Response or error (i.e. not an ack)
 				if opCode == frame.OpCodeResponse || opCode == frame.OpCodeError {
 					if !req.Multicast {
-						// Return the first result found immediately
+						// Return the first result found immediately and cancel the other copies of a hedged request
+						seenIDs[fromID] = opCode
+						if pending := pendingResponders(); len(pending) > 0 {
+							c.sendCancel(ctx, httpReq, port, host, msgID, pending)
+						}
 						return
 					}
 					seenIDs[fromID] = opCode
@@ -511,6 +719,42 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 				}
 				return
 
+			// Caller canceled the request
+			case <-canceledCh:
+				c.LogDebug(ctx, "Request canceled",
+					"msg", msgID,
+					"subject", subject,
+				)
+				if pending := pendingResponders(); len(pending) > 0 {
+					c.sendCancel(ctx, httpReq, port, host, msgID, pending)
+				}
+				err = errors.Trace(ctx.Err(), c.Span(ctx).TraceID())
+				soloResponse = pub.NewErrorResponse(err)
+				if output != nil {
+					output.Push(soloResponse)
+				}
+				return
+
+			// Hedge timer
+			case <-hedgeTimer.C:
+				c.LogDebug(ctx, "Hedging request",
+					"msg", msgID,
+					"subject", subject,
+				)
+				c.Span(ctx).AddEvent("hedge",
+					"msg", msgID,
+					"after", req.Hedge,
+					"acks", len(seenIDs),
+				)
+				err := c.transportConn.Request(subject, hedgeReq)
+				if err != nil {
+					c.LogError(ctx, "Hedging request",
+						"error", err,
+						"url", req.Canonical(),
+						"method", req.Method,
+					)
+				}
+
 			// Ack timer
 			case <-ackTimer.C:
 				if c.deployment == LOCAL && time.Since(ackTimerStart) >= c.ackTimeout*8 {
@@ -534,8 +778,9 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 							"subject", subject,
 						)
 					} else {
+						ackTimedOut = true
 						err = errors.New(
-							"ack timeout: %s", req.Canonical(),
+							"%w: %s", errAckTimeout, req.Canonical(),
 							http.StatusNotFound,
 							c.Span(ctx).TraceID(),
 						)
@@ -577,6 +822,22 @@ func (c *Connector) makeRequest(ctx context.Context, req *pub.Request) iter.Seq[
 	} else {
 		// Return the single response after it was received
 		awaitResponses()
+		if breaker != nil && soloResponse != nil {
+			// Count failures of the destination toward tripping its circuit breaker
+			res, err := soloResponse.Get()
+			if err != nil && errors.Is(err, context.Canceled) && !ackTimedOut {
+				// Requests abandoned by the caller say nothing of the destination
+				c.releaseProbe(breaker)
+			} else {
+				failed := ackTimedOut
+				if err != nil {
+					failed = failed || errors.StatusCode(err) >= 500
+				} else {
+					failed = failed || res.StatusCode >= 500
+				}
+				c.recordOutcome(ctx, breakerKey, breaker, failed)
+			}
+		}
 		return pub.NewSoloResponseQueue(soloResponse)
 	}
 }
@@ -619,6 +880,24 @@ func (c *Connector) handleResponse(msg *transport.Msg) error {
 		return nil
 	}
 
+	// Decompress compressed responses
+	err = c.decompressResponse(response)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Feed chunks of streamed responses to the body of the first one
+	if index, _ := frame.Of(response).Stream(); index > 0 {
+		response, err = c.assembleStream(response)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if response == nil {
+			// Chunk was appended to the stream
+			return nil
+		}
+	}
+
 	// Push it to the channel matching the message ID
 	msgID := frame.Of(response).MessageID()
 	ch, ok := c.reqs.Load(msgID)
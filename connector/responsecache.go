@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/lru"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// cachedResponse is a response held in the client-side response cache.
+type cachedResponse struct {
+	header  http.Header
+	body    []byte
+	eTag    string
+	expires time.Time
+}
+
+// response reconstructs the cached response.
+func (cr *cachedResponse) response() *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(http.StatusOK) + " " + http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cr.header.Clone(),
+		Body:          httpx.NewBodyReader(cr.body),
+		ContentLength: int64(len(cr.body)),
+	}
+}
+
+// responseCacheKey returns the key of the response to the request in the client-side response cache.
+// The key identifies the URL of the request, its actor, and the headers that negotiate its content.
+func responseCacheKey(req *pub.Request) string {
+	h := sha256.New()
+	for _, part := range []string{
+		req.Method,
+		req.URL,
+		req.Header.Get(frame.HeaderActor),
+		req.Header.Get("Accept"),
+		req.Header.Get("Accept-Language"),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// eTagMatches indicates if the entity tag matches any of the tags of an If-None-Match header.
+// Tags are compared weakly, ignoring quotes and the W/ prefix.
+func eTagMatches(ifNoneMatch string, eTag string) bool {
+	normalize := func(tag string) string {
+		tag = strings.TrimSpace(tag)
+		tag = strings.TrimPrefix(tag, "W/")
+		return strings.Trim(tag, `"`)
+	}
+	eTag = normalize(eTag)
+	for tag := range strings.SplitSeq(ifNoneMatch, ",") {
+		tag = normalize(tag)
+		if tag == "*" || tag == eTag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheExpiry returns the time the response expires given the Cache-Control header of the responder,
+// capped at the TTL requested by the caller. A response that may not be stored is indicated by ok being false.
+func cacheExpiry(res *http.Response, ttl time.Duration) (expires time.Time, ok bool) {
+	for directive := range strings.SplitSeq(strings.ToLower(res.Header.Get("Cache-Control")), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch name {
+		case "no-store":
+			return time.Time{}, false
+		case "no-cache":
+			ttl = 0
+		case "max-age":
+			maxAge, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err == nil {
+				ttl = min(ttl, time.Duration(maxAge)*time.Second)
+			}
+		}
+	}
+	return time.Now().Add(ttl), true
+}
+
+// cacheableHeader returns a copy of the header of a response without the control headers of the frame,
+// such as the message ID and the ID of the responder, which pertain only to the original exchange.
+func cacheableHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for name := range clone {
+		if strings.HasPrefix(name, frame.HeaderPrefix) {
+			delete(clone, name)
+		}
+	}
+	return clone
+}
+
+// bodyOf returns the bytes of the body of the response, buffering it if needed.
+func bodyOf(res *http.Response) ([]byte, error) {
+	if res.Body == nil || res.Body == http.NoBody {
+		return nil, nil
+	}
+	if br, ok := res.Body.(*httpx.BodyReader); ok {
+		return br.Bytes(), nil
+	}
+	b, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = httpx.NewBodyReader(b)
+	return b, err
+}
+
+// cacheResponse stores a successful response to a cacheable request in the client-side response cache.
+// A 304 Not Modified response to the revalidation of a stale cached response renews it.
+func (c *Connector) cacheResponse(ctx context.Context, key string, req *pub.Request, stale *cachedResponse, queue iter.Seq[*pub.Response]) iter.Seq[*pub.Response] {
+	var r *pub.Response
+	queue(func(rr *pub.Response) bool {
+		r = rr
+		return false
+	})
+	if r == nil {
+		return queue
+	}
+	res, err := r.Get()
+	if err != nil {
+		return pub.NewSoloResponseQueue(r)
+	}
+	switch {
+	case res.StatusCode == http.StatusNotModified && stale != nil:
+		expires, ok := cacheExpiry(res, req.CacheTTL)
+		if !ok {
+			c.responseCache.Delete(key)
+			return pub.NewSoloResponseQueue(pub.NewHTTPResponse(stale.response()))
+		}
+		renewed := *stale
+		renewed.expires = expires
+		c.responseCache.Store(key, &renewed, lru.Weight(len(renewed.body)))
+		c.LogDebug(ctx, "Revalidated cached response", "url", req.Canonical())
+		return pub.NewSoloResponseQueue(pub.NewHTTPResponse(renewed.response()))
+	case res.StatusCode == http.StatusOK:
+		expires, ok := cacheExpiry(res, req.CacheTTL)
+		if index, _ := frame.Of(res).Stream(); index > 0 {
+			// Streamed responses are not cached because buffering would block until the stream ends
+			ok = false
+		}
+		if !ok {
+			c.responseCache.Delete(key)
+			break
+		}
+		body, err := bodyOf(res)
+		if err != nil {
+			break
+		}
+		c.responseCache.Store(key, &cachedResponse{
+			header:  cacheableHeader(res.Header),
+			body:    body,
+			eTag:    res.Header.Get("Etag"),
+			expires: expires,
+		}, lru.Weight(len(body)))
+	}
+	return pub.NewSoloResponseQueue(r)
+}
+
+// notModified converts a successful response to a GET request to a 304 Not Modified response
+// if its entity tag matches the If-None-Match header of the request.
+// Responses without an ETag header are left as is, sparing hashing the body of every response.
+func notModified(res *http.Response, ifNoneMatch string) bool {
+	eTag := res.Header.Get("Etag")
+	if eTag == "" || !eTagMatches(ifNoneMatch, eTag) {
+		return false
+	}
+	res.StatusCode = http.StatusNotModified
+	res.Status = strconv.Itoa(http.StatusNotModified) + " " + http.StatusText(http.StatusNotModified)
+	res.Body = http.NoBody
+	res.ContentLength = 0
+	res.Header.Del("Content-Length")
+	return true
+}
@@ -0,0 +1,253 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_ResponseCache(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	var count atomic.Int32
+	var revalidations atomic.Int32
+	con := New("response.cache.connector")
+	con.Subscribe("Lookup",
+		func(w http.ResponseWriter, r *http.Request) error {
+			count.Add(1)
+			if r.Header.Get("If-None-Match") != "" {
+				revalidations.Add(1)
+			}
+			if cc := r.URL.Query().Get("cc"); cc != "" {
+				w.Header().Set("Cache-Control", cc)
+			}
+			if tag := r.URL.Query().Get("etag"); tag != "" {
+				w.Header().Set("Etag", tag)
+			}
+			w.Write([]byte("value of " + r.URL.Query().Get("k")))
+			return nil
+		},
+		sub.At("GET", "/lookup"),
+		sub.Web(),
+	)
+	var streamCount atomic.Int32
+	con.Subscribe("Stream",
+		func(w http.ResponseWriter, r *http.Request) error {
+			streamCount.Add(1)
+			w.Write([]byte("first"))
+			w.(http.Flusher).Flush()
+			time.Sleep(500 * time.Millisecond)
+			w.Write([]byte("second"))
+			return nil
+		},
+		sub.At("GET", "/stream"),
+		sub.Web(),
+	)
+	client := New("client.response.cache.connector")
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	lookup := func(url string, options ...pub.Option) string {
+		options = append([]pub.Option{pub.GET(url)}, options...)
+		res, err := client.Request(ctx, options...)
+		if !assert.NoError(err) {
+			return ""
+		}
+		assert.Equal(http.StatusOK, res.StatusCode)
+		b, _ := io.ReadAll(res.Body)
+		return string(b)
+	}
+
+	// The second request is served from the cache
+	assert.Equal("value of a", lookup("https://response.cache.connector/lookup?k=a", pub.Cache(time.Minute)))
+	assert.Equal("value of a", lookup("https://response.cache.connector/lookup?k=a", pub.Cache(time.Minute)))
+	assert.Equal(int32(1), count.Load())
+
+	// A different URL is not served from the cache
+	assert.Equal("value of b", lookup("https://response.cache.connector/lookup?k=b", pub.Cache(time.Minute)))
+	assert.Equal(int32(2), count.Load())
+
+	// A request that does not opt in is not served from the cache
+	assert.Equal("value of a", lookup("https://response.cache.connector/lookup?k=a"))
+	assert.Equal(int32(3), count.Load())
+
+	// Responses are cached per actor
+	assert.Equal("value of a", lookup("https://response.cache.connector/lookup?k=a", pub.Cache(time.Minute), pub.Header(frame.HeaderActor, "alice")))
+	assert.Equal("value of a", lookup("https://response.cache.connector/lookup?k=a", pub.Cache(time.Minute), pub.Header(frame.HeaderActor, "bob")))
+	assert.Equal(int32(5), count.Load())
+	assert.Equal("value of a", lookup("https://response.cache.connector/lookup?k=a", pub.Cache(time.Minute), pub.Header(frame.HeaderActor, "alice")))
+	assert.Equal(int32(5), count.Load())
+
+	// The responder may prevent caching
+	for range 2 {
+		assert.Equal("value of c", lookup("https://response.cache.connector/lookup?k=c&cc=no-store", pub.Cache(time.Minute)))
+	}
+	assert.Equal(int32(7), count.Load())
+
+	// A stale response is revalidated using its ETag
+	revalidations.Store(0)
+	for i := range 3 {
+		assert.Equal("value of d", lookup("https://response.cache.connector/lookup?k=d&cc=max-age=0&etag=d1", pub.Cache(time.Minute)))
+		assert.Equal(int32(i), revalidations.Load())
+	}
+	assert.Equal(int32(10), count.Load())
+
+	// A stale response without an ETag is fetched again
+	revalidations.Store(0)
+	for range 2 {
+		assert.Equal("value of g", lookup("https://response.cache.connector/lookup?k=g&cc=max-age=0", pub.Cache(time.Minute)))
+	}
+	assert.Equal(int32(0), revalidations.Load())
+	assert.Equal(int32(12), count.Load())
+
+	// The caller's TTL caps the duration a response is cached
+	assert.Equal("value of e", lookup("https://response.cache.connector/lookup?k=e&cc=max-age=3600", pub.Cache(100*time.Millisecond)))
+	assert.Equal("value of e", lookup("https://response.cache.connector/lookup?k=e&cc=max-age=3600", pub.Cache(100*time.Millisecond)))
+	assert.Equal(int32(13), count.Load())
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal("value of e", lookup("https://response.cache.connector/lookup?k=e&cc=max-age=3600", pub.Cache(100*time.Millisecond)))
+	assert.Equal(int32(14), count.Load())
+
+	// Cached responses do not carry the frame headers of the original response
+	res, err := client.Request(ctx, pub.GET("https://response.cache.connector/lookup?k=f"), pub.Cache(time.Minute))
+	if assert.NoError(err) {
+		assert.NotEqual("", frame.Of(res).MessageID())
+		assert.NotEqual("", frame.Of(res).FromID())
+	}
+	res, err = client.Request(ctx, pub.GET("https://response.cache.connector/lookup?k=f"), pub.Cache(time.Minute))
+	if assert.NoError(err) {
+		assert.Equal(int32(15), count.Load())
+		assert.Equal("", frame.Of(res).MessageID())
+		assert.Equal("", frame.Of(res).FromID())
+	}
+
+	// Streamed responses are returned without waiting for the stream to end, and are not cached
+	for i := range 2 {
+		t0 := time.Now()
+		res, err := client.Request(ctx, pub.GET("https://response.cache.connector/stream"), pub.Cache(time.Minute))
+		if assert.NoError(err) {
+			assert.True(time.Since(t0) < 400*time.Millisecond)
+			b, _ := io.ReadAll(res.Body)
+			assert.Equal("firstsecond", string(b))
+		}
+		assert.Equal(int32(i+1), streamCount.Load())
+	}
+}
+
+func TestConnector_NotModified(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	con := New("not.modified.connector")
+	con.Subscribe("Doc",
+		func(w http.ResponseWriter, r *http.Request) error {
+			if tag := r.URL.Query().Get("etag"); tag != "" {
+				w.Header().Set("Etag", tag)
+			}
+			w.Write([]byte("doc"))
+			return nil
+		},
+		sub.At("GET", "/doc"),
+		sub.Web(),
+	)
+
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// A matching ETag set by the handler results in a 304 with no body
+	res, err := con.Request(ctx, pub.GET("https://not.modified.connector/doc?etag=v1"), pub.Header("If-None-Match", `W/"v0", "v1"`))
+	if assert.NoError(err) {
+		assert.Equal(http.StatusNotModified, res.StatusCode)
+		b, _ := io.ReadAll(res.Body)
+		assert.Len(b, 0)
+	}
+
+	// A response without an ETag is not compared
+	res, err = con.Request(ctx, pub.GET("https://not.modified.connector/doc"), pub.Header("If-None-Match", "*"))
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, res.StatusCode)
+		b, _ := io.ReadAll(res.Body)
+		assert.Equal("doc", string(b))
+	}
+
+	// A mismatching ETag results in a 200
+	res, err = con.Request(ctx, pub.GET("https://not.modified.connector/doc?etag=v2"), pub.Header("If-None-Match", `"v1"`))
+	if assert.NoError(err) {
+		assert.Equal(http.StatusOK, res.StatusCode)
+		b, _ := io.ReadAll(res.Body)
+		assert.Equal("doc", string(b))
+		assert.Equal("v2", res.Header.Get("Etag"))
+	}
+}
+
+func TestConnector_CacheExpiry(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	expiry := func(cacheControl string, ttl time.Duration) (time.Duration, bool) {
+		res := &http.Response{Header: http.Header{}}
+		if cacheControl != "" {
+			res.Header.Set("Cache-Control", cacheControl)
+		}
+		expires, ok := cacheExpiry(res, ttl)
+		return time.Until(expires).Round(time.Second), ok
+	}
+	for _, tc := range []struct {
+		cacheControl string
+		ttl          time.Duration
+		expected     time.Duration
+		ok           bool
+	}{
+		{"", time.Minute, time.Minute, true},
+		{"max-age=30", time.Minute, 30 * time.Second, true},
+		{"private, max-age=3600", time.Minute, time.Minute, true},
+		{"no-cache", time.Minute, 0, true},
+		{"No-Store", time.Minute, 0, false},
+	} {
+		d, ok := expiry(tc.cacheControl, tc.ttl)
+		assert.Equal(tc.ok, ok, "%s", tc.cacheControl)
+		if ok {
+			assert.Equal(tc.expected, d, "%s", tc.cacheControl)
+		}
+	}
+
+	assert.True(eTagMatches(`"abc"`, "abc"))
+	assert.True(eTagMatches(`W/"abc"`, `"abc"`))
+	assert.True(eTagMatches(`"x", "abc"`, "abc"))
+	assert.True(eTagMatches(`*`, "abc"))
+	assert.False(eTagMatches(`"abcd"`, "abc"))
+}
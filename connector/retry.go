@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/utils"
+)
+
+// makeRequestWithRetry makes a unicast request, retrying it according to its retry policy.
+// All attempts share the time budget of the request. Each attempt is recorded as an event on the span.
+func (c *Connector) makeRequestWithRetry(ctx context.Context, req *pub.Request) iter.Seq[*pub.Response] {
+	policy := req.Retry
+	if policy == nil || policy.MaxAttempts <= 1 || req.Multicast {
+		return c.sendRequest(ctx, req)
+	}
+
+	// Buffer the body so that it can be resent
+	body, ok := req.Body.(*httpx.BodyReader)
+	if !ok && req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			err = errors.Trace(err, c.Span(ctx).TraceID())
+			return pub.NewSoloResponseQueue(pub.NewErrorResponse(err))
+		}
+		body = httpx.NewBodyReader(b)
+		req.Body = body
+	}
+
+	// Share an idempotency key among all attempts of a non-idempotent request
+	if (req.Method == "POST" || req.Method == "PATCH") && req.Header.Get("Idempotency-Key") == "" {
+		req.Header.Set("Idempotency-Key", utils.RandomIdentifier(24))
+	}
+
+	// Fix the deadline of all attempts
+	timeout := c.defaultTimeBudget
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if req.Timeout > 0 && req.Timeout < timeout {
+		timeout = req.Timeout
+	}
+	timeout = min(timeout, c.maxTimeBudget)
+	deadline := time.Now().Add(timeout)
+	attemptCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	span := c.Span(ctx)
+	var res *pub.Response
+	for attempt := 1; attempt <= policy.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			delay := policy.Delay(attempt)
+			if time.Until(deadline)-delay <= c.networkRoundtrip {
+				// Not enough time budget for another attempt
+				break
+			}
+			c.LogDebug(ctx, "Retrying request",
+				"url", req.Canonical(),
+				"attempt", attempt,
+				"delay", delay,
+			)
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					span.AddEvent("retry", "attempt", attempt, "canceled", true)
+					return pub.NewSoloResponseQueue(res)
+				}
+			}
+			// Discard the response that is being retried
+			if httpRes, err := res.Get(); err == nil && httpRes != nil && httpRes.Body != nil {
+				httpRes.Body.Close()
+			}
+		}
+		if body != nil {
+			body.Reset()
+		}
+		for r := range c.sendRequest(attemptCtx, req) {
+			res = r
+			break
+		}
+		httpRes, err := res.Get()
+		statusCode := http.StatusOK
+		if err != nil {
+			statusCode = errors.StatusCode(err)
+		} else if httpRes != nil {
+			statusCode = httpRes.StatusCode
+		}
+		span.AddEvent("retry", "attempt", attempt, "status", statusCode)
+		if !policy.ShouldRetry(statusCode) {
+			break
+		}
+	}
+	return pub.NewSoloResponseQueue(res)
+}
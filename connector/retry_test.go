@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Retry(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservice
+	var count atomic.Int32
+	con := New("retry.connector")
+	con.Subscribe("Flaky",
+		func(w http.ResponseWriter, r *http.Request) error {
+			body, _ := io.ReadAll(r.Body)
+			if count.Add(1) < 3 {
+				return errors.New("unavailable", http.StatusServiceUnavailable)
+			}
+			w.Write(body)
+			return nil
+		},
+		sub.At("PUT", "flaky"),
+		sub.Web(),
+	)
+	con.Subscribe("Broken",
+		func(w http.ResponseWriter, r *http.Request) error {
+			count.Add(1)
+			return errors.New("broken", http.StatusInternalServerError)
+		},
+		sub.At("GET", "broken"),
+		sub.Web(),
+	)
+
+	// Startup the microservice
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	policy := pub.RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     10 * time.Millisecond,
+	}
+
+	// Without a retry policy, the first failure is returned
+	count.Store(0)
+	_, err = con.Request(ctx, pub.PUT("https://retry.connector/flaky"), pub.Body("Hello"))
+	assert.Expect(errors.StatusCode(err), http.StatusServiceUnavailable, count.Load(), int32(1))
+
+	// The third attempt succeeds and the body is resent each time
+	count.Store(0)
+	res, err := con.Request(ctx, pub.PUT("https://retry.connector/flaky"), pub.Body("Hello"), pub.Retry(policy))
+	if assert.NoError(err) {
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal("Hello", string(body))
+	}
+	assert.Equal(int32(3), count.Load())
+
+	// Status codes not in the retry set are not retried
+	count.Store(0)
+	_, err = con.Request(ctx, pub.GET("https://retry.connector/broken"), pub.Retry(policy))
+	assert.Expect(errors.StatusCode(err), http.StatusInternalServerError, count.Load(), int32(1))
+
+	policy.RetryOn = []int{http.StatusInternalServerError}
+	count.Store(0)
+	_, err = con.Request(ctx, pub.GET("https://retry.connector/broken"), pub.Retry(policy))
+	assert.Expect(errors.StatusCode(err), http.StatusInternalServerError, count.Load(), int32(3))
+
+	// Attempts are limited by the time budget
+	policy.Backoff = time.Second
+	count.Store(0)
+	_, err = con.Request(ctx, pub.GET("https://retry.connector/broken"), pub.Retry(policy), pub.Timeout(1500*time.Millisecond))
+	assert.Expect(errors.StatusCode(err), http.StatusInternalServerError, count.Load(), int32(2))
+}
@@ -137,6 +137,18 @@ func (e *selectiveProcessor) Select(traceID string) (ok bool) {
 	return true
 }
 
+// IsSelected indicates if the trace ID had been recently selected.
+func (e *selectiveProcessor) IsSelected(traceID string) bool {
+	if e.lastSelected.Load() < e.now()-maxTTLSeconds {
+		return false
+	}
+	e.mux.Lock()
+	e.lockCount++
+	selected := e.selected1[traceID] || e.selected2[traceID]
+	e.mux.Unlock()
+	return selected
+}
+
 // Shutdown prevents further spans from being processed.
 func (e *selectiveProcessor) Shutdown(ctx context.Context) error {
 	e.downstreamProcessor.Shutdown(ctx)
@@ -0,0 +1,252 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+)
+
+// Ensure interfaces
+var (
+	_ = http.ResponseWriter(&streamWriter{})
+	_ = http.Flusher(&streamWriter{})
+)
+
+// streamWriter is the response writer passed to handlers of incoming requests.
+// It records the response in memory until the handler flushes it via [http.Flusher].
+// The first flush switches the writer to streaming mode: the status code, headers and body written so far
+// are sent to the caller, and the body written thereafter is sent with each subsequent flush.
+type streamWriter struct {
+	*httpx.ResponseRecorder
+	send         func(chunk *http.Response) error
+	maxChunkSize int
+	index        int
+	bytesSent    int
+	err          error
+}
+
+// newStreamWriter creates a new stream writer that sends chunks of the response using the send function.
+func newStreamWriter(maxChunkSize int, send func(chunk *http.Response) error) *streamWriter {
+	return &streamWriter{
+		ResponseRecorder: httpx.NewResponseRecorder(),
+		send:             send,
+		maxChunkSize:     maxChunkSize,
+	}
+}
+
+// Streaming indicates if the handler flushed the response and it is being streamed to the caller.
+func (sw *streamWriter) Streaming() bool {
+	return sw.index > 0
+}
+
+// Write writes bytes to the body of the response.
+// It fails if an earlier chunk could not be sent.
+func (sw *streamWriter) Write(b []byte) (int, error) {
+	if sw.err != nil {
+		return 0, sw.err
+	}
+	return sw.ResponseRecorder.Write(b)
+}
+
+// WriteHeader sets the status code of the response. It has no effect once streaming began.
+func (sw *streamWriter) WriteHeader(statusCode int) {
+	if sw.Streaming() {
+		return
+	}
+	sw.ResponseRecorder.WriteHeader(statusCode)
+}
+
+// ContentLength returns the total number of bytes written to the body of the response, including bytes already sent.
+func (sw *streamWriter) ContentLength() int {
+	return sw.bytesSent + sw.ResponseRecorder.ContentLength()
+}
+
+// Flush sends the body written since the previous flush to the caller.
+// It implements the [http.Flusher] interface.
+func (sw *streamWriter) Flush() {
+	if sw.err != nil {
+		return
+	}
+	sw.err = sw.sendPending(false)
+}
+
+// End sends the remainder of the body as the last chunk of the stream.
+// If errRes is not nil, the stream is terminated with the error response instead.
+func (sw *streamWriter) End(errRes *http.Response) error {
+	if sw.err != nil {
+		return sw.err
+	}
+	if errRes != nil {
+		sw.index++
+		frame.Of(errRes).SetStream(sw.index, sw.index)
+		frame.Of(errRes).SetOpCode(frame.OpCodeError)
+		return errors.Trace(sw.send(errRes))
+	}
+	return errors.Trace(sw.sendPending(true))
+}
+
+// sendPending sends the pending body in one or more chunks, each no larger than the max chunk size.
+func (sw *streamWriter) sendPending(last bool) error {
+	res := sw.ResponseRecorder.Result()
+	var pending []byte
+	if br, ok := res.Body.(*httpx.BodyReader); ok {
+		pending = br.Bytes()
+	}
+	sw.ResponseRecorder.ClearBody()
+	if len(pending) == 0 && !last && sw.Streaming() {
+		return nil
+	}
+	for first := true; first || len(pending) > 0; first = false {
+		n := min(len(pending), sw.maxChunkSize)
+		chunk := &http.Response{
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			Header:     make(http.Header),
+		}
+		if sw.index == 0 {
+			// The first chunk carries the headers of the response
+			for k, vv := range res.Header {
+				if k != "Content-Length" {
+					chunk.Header[k] = vv
+				}
+			}
+		}
+		if n > 0 {
+			chunk.Body = httpx.NewBodyReader(pending[:n])
+		}
+		chunk.ContentLength = int64(n)
+		chunk.Header.Set("Content-Length", strconv.Itoa(n))
+		pending = pending[n:]
+		sw.index++
+		if last && len(pending) == 0 {
+			frame.Of(chunk).SetStream(sw.index, sw.index)
+		} else {
+			frame.Of(chunk).SetStream(sw.index, 0)
+		}
+		frame.Of(chunk).SetOpCode(frame.OpCodeResponse)
+		err := sw.send(chunk)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sw.bytesSent += n
+	}
+	return nil
+}
+
+// responseStream is the receiving end of a streamed response.
+type responseStream struct {
+	body  *httpx.StreamBody
+	index int
+	timer *time.Timer
+}
+
+// assembleStream feeds a chunk of a streamed response to the body of the response.
+// The first chunk is returned as the response whose body is the stream, to be delivered to the caller.
+// Subsequent chunks are appended to the stream and nil is returned.
+func (c *Connector) assembleStream(r *http.Response) (first *http.Response, err error) {
+	index, max := frame.Of(r).Stream()
+	fromID := frame.Of(r).FromID()
+	msgID := frame.Of(r).MessageID()
+	streamKey := fromID + "|" + msgID
+
+	var chunk []byte
+	if r.Body != nil {
+		if br, ok := r.Body.(*httpx.BodyReader); ok {
+			chunk = br.Bytes()
+		} else {
+			chunk, err = io.ReadAll(r.Body)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
+
+	var stream *responseStream
+	if index == 1 {
+		if _, ok := c.reqs.Load(msgID); !ok {
+			// Nobody is waiting for the response
+			return nil, nil
+		}
+		stream = &responseStream{
+			body: httpx.NewStreamBody(),
+		}
+		// The stream can't outlive the time budget of the handler
+		budget := frame.Of(r).TimeBudget()
+		if budget <= 0 {
+			budget = c.maxTimeBudget
+		}
+		stream.timer = time.AfterFunc(budget+c.networkRoundtrip, func() {
+			c.responseStreams.Delete(streamKey)
+			stream.body.End(errors.New("stream timeout", http.StatusRequestTimeout))
+		})
+		c.responseStreams.Store(streamKey, stream)
+		frame.Of(r).SetTimeBudget(0)
+	} else {
+		var ok bool
+		stream, ok = c.responseStreams.Load(streamKey)
+		if !ok {
+			// Most likely after a timeout
+			return nil, nil
+		}
+		if index != stream.index+1 {
+			c.responseStreams.Delete(streamKey)
+			stream.timer.Stop()
+			stream.body.End(errors.New("stream chunk out of order", http.StatusInternalServerError))
+			return nil, nil
+		}
+	}
+	stream.index = index
+
+	if frame.Of(r).OpCode() == frame.OpCodeError {
+		// Reconstitute the error that terminated the stream
+		var reconstitutedError struct {
+			Err *errors.TracedError `json:"err"`
+		}
+		err = json.Unmarshal(chunk, &reconstitutedError)
+		if err != nil || reconstitutedError.Err == nil {
+			err = errors.New("unparsable error response")
+		} else {
+			err = errors.Convert(reconstitutedError.Err)
+		}
+		chunk = nil
+		max = index
+	}
+	stream.body.Append(chunk)
+	if max > 0 && index == max {
+		c.responseStreams.Delete(streamKey)
+		stream.timer.Stop()
+		stream.body.End(err)
+	}
+	if index != 1 {
+		return nil, nil
+	}
+	r.Body = stream.body
+	r.ContentLength = -1
+	r.Header.Del("Content-Length")
+	return r, nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/utils"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_Stream(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservices
+	alpha := New("alpha.stream.connector")
+
+	proceed := make(chan bool)
+	beta := New("beta.stream.connector")
+	beta.Subscribe("Events",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusAccepted)
+			for i := range 3 {
+				w.Write([]byte("data: " + strconv.Itoa(i) + "\n\n"))
+				w.(http.Flusher).Flush()
+				<-proceed
+			}
+			return nil
+		},
+		sub.At("GET", "events"),
+		sub.Web(),
+	)
+
+	// Startup the microservices
+	err := alpha.Startup(ctx)
+	assert.NoError(err)
+	defer alpha.Shutdown(ctx)
+	err = beta.Startup(ctx)
+	assert.NoError(err)
+	defer beta.Shutdown(ctx)
+
+	// The response should return before the handler is done
+	res, err := alpha.GET(ctx, "https://beta.stream.connector/events")
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Expect(
+		res.StatusCode, http.StatusAccepted,
+		res.Header.Get("Content-Type"), "text/event-stream",
+		res.Header.Get("Content-Length"), "",
+	)
+	_, ok := res.Body.(*httpx.StreamBody)
+	assert.True(ok)
+
+	// Each event should arrive as soon as it is flushed
+	scanner := bufio.NewScanner(res.Body)
+	for i := range 3 {
+		assert.True(scanner.Scan())
+		assert.Equal("data: "+strconv.Itoa(i), scanner.Text())
+		assert.True(scanner.Scan())
+		assert.Equal("", scanner.Text())
+		proceed <- true
+	}
+	assert.False(scanner.Scan())
+	assert.NoError(scanner.Err())
+}
+
+func TestConnector_StreamError(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservice
+	con := New("stream.error.connector")
+	con.Subscribe("Fail",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Write([]byte("Hello"))
+			w.(http.Flusher).Flush()
+			return errors.New("oops", http.StatusTeapot)
+		},
+		sub.At("GET", "fail"),
+		sub.Web(),
+	)
+
+	// Startup the microservice
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// The status code was already sent when the error occurred
+	res, err := con.GET(ctx, "https://stream.error.connector/fail")
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	// The error should terminate the stream
+	body, err := io.ReadAll(res.Body)
+	assert.Equal("Hello", string(body))
+	if assert.Error(err) {
+		assert.Equal("oops", err.Error())
+		assert.Equal(http.StatusTeapot, errors.StatusCode(err))
+	}
+}
+
+func TestConnector_StreamLargeChunk(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservice
+	con := New("stream.large.chunk.connector")
+	bodySent := []byte(utils.RandomIdentifier(1024*3 + 16))
+	con.Subscribe("Big",
+		func(w http.ResponseWriter, r *http.Request) error {
+			w.Write(bodySent[:1024*2])
+			w.(http.Flusher).Flush()
+			w.Write(bodySent[1024*2:])
+			return nil
+		},
+		sub.At("GET", "big"),
+		sub.Web(),
+	)
+
+	// Startup the microservice
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+	con.maxFragmentSize = 1000
+
+	// Chunks larger than the fragment size should be split
+	res, err := con.GET(ctx, "https://stream.large.chunk.connector/big")
+	if assert.NoError(err) {
+		bodyReceived, err := io.ReadAll(res.Body)
+		assert.NoError(err)
+		assert.Equal(bodySent, bodyReceived)
+	}
+}
+
+func TestConnector_StreamNotFlushed(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	// Create the microservice
+	con := New("stream.not.flushed.connector")
+	con.Subscribe("Hello",
+		func(w http.ResponseWriter, r *http.Request) error {
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte("Hello"))
+			return nil
+		},
+		sub.At("GET", "hello"),
+		sub.Web(),
+	)
+
+	// Startup the microservice
+	err := con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// Responses that are not flushed should not be streamed
+	res, err := con.GET(ctx, "https://stream.not.flushed.connector/hello")
+	if assert.NoError(err) {
+		_, ok := res.Body.(*httpx.BodyReader)
+		assert.True(ok)
+		assert.Equal("5", res.Header.Get("Content-Length"))
+		body, err := io.ReadAll(res.Body)
+		assert.NoError(err)
+		assert.Equal("Hello", string(body))
+	}
+}
@@ -24,6 +24,7 @@ import (
 const (
 	idPrefix       = "id-"
 	localityPrefix = "loc-"
+	versionPrefix  = "ver-"
 )
 
 // subjectHexDigits supplies the lowercase hex alphabet used for percent-encoded
@@ -111,20 +112,35 @@ func splitSubject(subject string) (plane, trust, port, srcHostname, hostname, id
 	return
 }
 
-// cutIDOrLocality strips a reserved id- or loc- prefix from the hostname's
+// cutIDOrLocality strips a reserved id- or loc- prefix, or a ver-N version slot, from the hostname's
 // first segment and returns it as the slot value. Returns the hostname
 // unchanged with an empty slot when no reserved prefix is present.
 func cutIDOrLocality(hostname string) (host, idOrLocality string) {
 	if i := strings.IndexByte(hostname, '.'); i > 0 {
 		first := hostname[:i]
 		lower := strings.ToLower(first)
-		if strings.HasPrefix(lower, idPrefix) || strings.HasPrefix(lower, localityPrefix) {
+		if strings.HasPrefix(lower, idPrefix) || strings.HasPrefix(lower, localityPrefix) || isVersionSlot(lower) {
 			return hostname[i+1:], lower
 		}
 	}
 	return hostname, ""
 }
 
+// isVersionSlot returns true if the lowercase segment is a version slot, e.g. "ver-7".
+// Hostnames that merely start with "ver-", e.g. "ver-api", are not version slots.
+func isVersionSlot(segment string) bool {
+	digits, ok := strings.CutPrefix(segment, versionPrefix)
+	if !ok || digits == "" {
+		return false
+	}
+	for i := range len(digits) {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // escapeLocality wraps a hyphen-form locality prefix (e.g. "us-west") in its
 // slot form (e.g. "loc-us-west"). Returns an empty string for an empty input.
 func escapeLocality(locality string) string {
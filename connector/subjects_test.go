@@ -282,6 +282,16 @@ func TestConnector_CutIDOrLocality(t *testing.T) {
 	assert.Equal("example.com", host)
 	assert.Equal("loc-us-west", slot)
 
+	// ver- prefix
+	host, slot = cutIDOrLocality("ver-7.example.com")
+	assert.Equal("example.com", host)
+	assert.Equal("ver-7", slot)
+
+	// ver- prefix that is not followed by a version number is part of the hostname
+	host, slot = cutIDOrLocality("ver-api.example.com")
+	assert.Equal("ver-api.example.com", host)
+	assert.Equal("", slot)
+
 	// Mixed-case prefix is lowercased on the slot
 	host, slot = cutIDOrLocality("ID-ABC123.example.com")
 	assert.Equal("example.com", host)
@@ -302,12 +312,13 @@ func TestConnector_ReservedHostnamePrefixRejected(t *testing.T) {
 	t.Parallel()
 	assert := testarossa.For(t)
 
-	// Service identities cannot start with the reserved id- or loc- prefixes.
+	// Service identities cannot start with the reserved id-, loc- or ver- prefixes.
 	for _, name := range []string{
 		idPrefix + "abc.example.com",
 		idPrefix + "abc",
 		localityPrefix + "us-west.example.com",
 		localityPrefix + "us-west",
+		versionPrefix + "7.example.com",
 		"ID-ABC.example.com",
 		"LOC-US-WEST.example.com",
 	} {
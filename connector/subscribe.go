@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -57,6 +58,9 @@ type SubscriptionInfo struct {
 	Queue          string
 	Type           string
 	RequiredClaims string
+	MaxConcurrency int
+	WaitQueue      int
+	IdempotencyTTL time.Duration
 	Tags           []string
 	Manual         bool
 	NoTrace        bool
@@ -87,9 +91,13 @@ func (c *Connector) Subscribe(name string, handler sub.HTTPHandler, options ...s
 	if _, loaded := c.subs.LoadOrStore(name, newSub); loaded {
 		return c.captureInitErr(errors.New("duplicate subscription name '%s'", name))
 	}
+	if limiter := newConcurrencyLimiter(newSub); limiter != nil {
+		c.limiters.Store(name, limiter)
+	}
 	if c.isPhase(startedUp) && !newSub.Manual {
 		if err := c.activateSub(newSub); err != nil {
 			c.subs.Delete(name)
+			c.limiters.Delete(name)
 			return c.captureInitErr(errors.Trace(err))
 		}
 		c.notifyOnNewSubs(newSub)
@@ -121,6 +129,9 @@ func (c *Connector) Subscriptions() []SubscriptionInfo {
 			Queue:          s.Queue,
 			Type:           s.Type,
 			RequiredClaims: s.RequiredClaims,
+			MaxConcurrency: s.MaxConcurrency,
+			WaitQueue:      s.WaitQueue,
+			IdempotencyTTL: s.IdempotencyTTL,
 			Tags:           tags,
 			Manual:         s.Manual,
 			NoTrace:        s.NoTrace,
@@ -192,6 +203,7 @@ func (c *Connector) Unsubscribe(name string) error {
 	if !ok {
 		return errors.New("unknown subscription name '%s'", name)
 	}
+	c.limiters.Delete(name)
 	if err := c.deactivateSub(s); err != nil {
 		return errors.Trace(err)
 	}
@@ -249,6 +261,29 @@ func (c *Connector) onRequest(msg *transport.Msg, s *sub.Subscription) {
 	_, _, _, src, _, _ := splitSubject(msg.Subject)
 	frame.Of(msg.Request).SetFromHost(src)
 
+	// Cancel the handlers processing the request if the caller abandoned it
+	if frame.Of(msg.Request).OpCode() == frame.OpCodeCancel {
+		c.pendingOps.Add(-1)
+		c.cancelInflight(frame.Of(msg.Request).MessageID(), frame.Of(msg.Request).FromID())
+		return
+	}
+
+	// A saturated subscription with no queue declines to ack the request, excluding itself from the responders.
+	// Load-balanced subscriptions are shed with a 503 error by handleRequest instead.
+	if limiter, ok := c.limiters.Load(s.Name); ok && s.Queue == "" && limiter.saturated() {
+		if fragIndex, _ := frame.Of(msg.Request).Fragment(); fragIndex <= 1 {
+			c.pendingOps.Add(-1)
+			c.recordShed(c.Lifetime(), s, "noack")
+			return
+		}
+	}
+
+	// Fault injection: do not ack the request, causing the caller to think there is no responder
+	if fragIndex, _ := frame.Of(msg.Request).Fragment(); fragIndex <= 1 && !frame.Of(msg.Request).FaultsInjected() && c.injectedFault(faultDropAck, msg.Request) != nil {
+		c.pendingOps.Add(-1)
+		return
+	}
+
 	err := c.ackRequest(msg, s)
 	if err != nil {
 		c.pendingOps.Add(-1)
@@ -305,6 +340,19 @@ func (c *Connector) activateSub(s *sub.Subscription) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Consume durable events from their stream
+	if s.Durable && c.transportConn.DurableEnabled() {
+		err = c.activateDurableSub(s)
+		if err != nil {
+			c.LogError(c.Lifetime(), "Activating durable sub",
+				"error", err,
+				"url", s.Canonical(),
+				"method", s.Method,
+			)
+			return errors.Trace(err)
+		}
+		return nil
+	}
 	// Create the subscriptions
 	handler := func(msg *transport.Msg) {
 		c.onRequest(msg, s)
@@ -312,6 +360,7 @@ func (c *Connector) activateSub(s *sub.Subscription) (err error) {
 	prefixes := []string{
 		"", // bare - encoded as the `_` placeholder
 		c.id,
+		versionPrefix + strconv.Itoa(c.version),
 	}
 	if c.locality != "" {
 		loc := strings.Split(c.locality, "-")
@@ -462,6 +511,7 @@ func (c *Connector) ackRequest(msg *transport.Msg, s *sub.Subscription) (err err
 	frm.SetMessageID(msgID)
 	frm.SetQueue(queue)
 	frm.SetLocality(c.locality)
+	frm.SetCancelable(true)
 	if fragmentMax > 1 {
 		httpRes.StatusCode = http.StatusContinue
 		httpRes.Status = "100 Continue"
@@ -532,6 +582,8 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 		return nil
 	}
 
+	acceptEncoding := frame.Of(httpReq).AcceptEncoding()
+
 	// OpenTelemetry: create a child span
 	ctx = propagation.TraceContext{}.Extract(ctx, propagation.HeaderCarrier(httpReq.Header))
 	var span trc.Span
@@ -555,13 +607,39 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 		}
 	}()
 
+	// Set control headers on the response
+	setControlHeaders := func(httpResponse *http.Response, opCode string) {
+		frame.Of(httpResponse).SetMessageID(msgID)
+		frame.Of(httpResponse).SetFromHost(c.hostname)
+		frame.Of(httpResponse).SetFromID(c.id)
+		frame.Of(httpResponse).SetFromVersion(c.version)
+		frame.Of(httpResponse).SetQueue(queue)
+		frame.Of(httpResponse).SetOpCode(opCode)
+		frame.Of(httpResponse).SetLocality(c.locality)
+	}
+
 	// Execute the request
+	canonical := s.Canonical()
 	handlerStartTime := time.Now()
-	httpRecorder := httpx.NewResponseRecorder()
 	var handlerErr error
 
+	// Stream the response if the handler flushes it
+	httpRecorder := newStreamWriter(int(c.maxFragmentSize), func(chunk *http.Response) error {
+		if msg.Delivery > 0 {
+			return nil // Durable events are not responded to
+		}
+		setControlHeaders(chunk, frame.Of(chunk).OpCode())
+		if index, _ := frame.Of(chunk).Stream(); index == 1 {
+			frame.Of(chunk).SetTimeBudget(budget)
+		}
+		return c.transportConn.Response(subjectOfResponse(c.plane, c.hostname, fromHost, fromId), chunk)
+	})
+
 	// Prepare the context with a timeout set to the time budget reduced by a network hop
+	// The context is also canceled if the caller abandons the request
 	ctx = frame.ContextWithClonedFrameOf(ctx, httpReq.Header)
+	ctx, cancelByCaller := context.WithCancelCause(ctx)
+	defer c.registerInflight(msgID, fromId, cancelByCaller)()
 	ctx, cancel := context.WithTimeout(ctx, budget-c.networkRoundtrip)
 	httpReq = httpReq.WithContext(ctx)
 	httpReq.Header = frame.Of(ctx).Header()
@@ -576,6 +654,7 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 	// even if the endpoint declares no requiredClaims, so a handler that reads claims
 	// via IfActor/ParseActor can trust them. A missing token fails only when the
 	// endpoint declares requiredClaims.
+	var claims jwt.MapClaims
 	if handlerErr == nil {
 		actor := httpReq.Header.Get(frame.HeaderActor)
 		if actor == "" || !utils.LooksLikeJWT(actor) {
@@ -589,7 +668,6 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 			if requiredClaims == "" {
 				requiredClaims = "true"
 			}
-			var claims jwt.MapClaims
 			claims, handlerErr = c.verifyToken(actor, requiredClaims)
 			if handlerErr == nil {
 				ctx = logActorClaims(ctx, claims)
@@ -598,14 +676,84 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 		}
 	}
 
+	// Replay the response to a previous request with the same idempotency key
+	var idempotent *idempotentCall
+	var replay *http.Response
+	if idempotencyKey := httpReq.Header.Get("Idempotency-Key"); idempotencyKey != "" && s.IdempotencyTTL > 0 && handlerErr == nil {
+		deadline, _ := ctx.Deadline()
+		key := idempotencyKeyOf(s, httpReq, claims, idempotencyKey)
+		replay, idempotent, handlerErr = c.beginIdempotent(ctx, s, key, deadline)
+		defer func() {
+			if idempotent != nil {
+				// Response was not retained
+				c.endIdempotent(c.Lifetime(), idempotent, nil)
+			}
+		}()
+	}
+	if replay != nil {
+		for k, vv := range replay.Header {
+			httpRecorder.Header()[k] = vv
+		}
+		httpRecorder.Header().Set("Idempotent-Replayed", "true")
+		httpRecorder.WriteHeader(replay.StatusCode)
+		_, handlerErr = io.Copy(httpRecorder, replay.Body)
+		replay.Body.Close()
+	}
+
+	// Wait for a processing slot if the concurrency of the subscription is limited
+	shed := false
+	limiter, limited := c.limiters.Load(s.Name)
+	limited = limited && replay == nil
+	if limited && handlerErr == nil {
+		var waited time.Duration
+		waited, handlerErr = limiter.acquire(ctx)
+		if waited > 0 {
+			_ = c.RecordHistogram(
+				ctx,
+				"microbus_server_queue_wait_duration_seconds",
+				waited.Seconds(),
+				"name", s.Name,
+				"route", s.Path,
+				"canonical", canonical,
+				"port", s.Port,
+			)
+		}
+		if handlerErr != nil {
+			shed = true
+			if waited > 0 {
+				c.recordShed(ctx, s, "timeout")
+			} else {
+				c.recordShed(ctx, s, "rejected")
+			}
+		} else {
+			c.recordConcurrency(ctx, s, limiter)
+		}
+	}
+
+	// Fault injection: delay or fail the request
+	if handlerErr == nil && replay == nil && !frame.Of(httpReq).FaultsInjected() {
+		handlerErr = c.injectLatencyOrError(ctx, httpReq)
+		if handlerErr != nil && limited {
+			limiter.release()
+			c.recordConcurrency(ctx, s, limiter)
+		}
+	}
+
 	// Call the handler
-	if handlerErr == nil {
+	if handlerErr == nil && replay == nil {
 		handlerErr = errors.CatchPanic(func() error {
-			return s.Handler.(HTTPHandler)(httpRecorder, httpReq)
+			return c.interceptedHandler(s)(httpRecorder, httpReq)
 		})
+		if limited {
+			limiter.release()
+			c.recordConcurrency(ctx, s, limiter)
+		}
 	}
+	canceledByCaller := context.Cause(ctx) == errCanceledByCaller
 	cancel()
+	cancelByCaller(nil)
 
+	var errRes *http.Response
 	if handlerErr != nil {
 		convertedErr := errors.Convert(handlerErr)
 		handlerErr = convertedErr
@@ -617,27 +765,43 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 		if statusCode == 0 {
 			statusCode = http.StatusInternalServerError
 		}
-		c.LogError(ctx, "Handling request",
-			"error", convertedErr,
-			"path", s.Path,
-			"depth", frame.Of(httpReq).CallDepth(),
-			"code", statusCode,
-		)
-
 		// OpenTelemetry: record the error, adding the request attributes
 		span.SetAttributes("http.route", s.Path)
 		span.SetRequest(httpReq)
 		span.SetError(convertedErr)
-		c.ForceTrace(ctx)
+		if !canceledByCaller {
+			// Select the trace before logging so that log sampling does not suppress the error
+			c.ForceTrace(ctx)
+		}
+
+		if canceledByCaller {
+			// The caller is no longer waiting for the response
+			c.LogDebug(ctx, "Handling request",
+				"error", convertedErr,
+				"path", s.Path,
+				"depth", frame.Of(httpReq).CallDepth(),
+				"code", statusCode,
+			)
+		} else {
+			c.LogError(ctx, "Handling request",
+				"error", convertedErr,
+				"path", s.Path,
+				"depth", frame.Of(httpReq).CallDepth(),
+				"code", statusCode,
+			)
+		}
 
 		// Enrich error with trace ID
 		convertedErr.Trace = span.TraceID()
 
 		// Prepare an error response instead
-		httpRecorder = httpx.NewResponseRecorder()
-		httpRecorder.Header().Set("Content-Type", "application/json")
-		httpRecorder.WriteHeader(statusCode)
-		encoder := json.NewEncoder(httpRecorder)
+		errRecorder := httpx.NewResponseRecorder()
+		errRecorder.Header().Set("Content-Type", "application/json")
+		if shed {
+			errRecorder.Header().Set("Retry-After", "1")
+		}
+		errRecorder.WriteHeader(statusCode)
+		encoder := json.NewEncoder(errRecorder)
 		if c.Deployment() == LOCAL {
 			encoder.SetIndent("", "  ")
 		}
@@ -650,10 +814,20 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 		if err != nil {
 			return errors.Trace(err)
 		}
+		if httpRecorder.Streaming() {
+			// The error terminates the stream
+			errRes = errRecorder.Result()
+		} else {
+			httpRecorder.ResponseRecorder = errRecorder
+		}
+	}
+
+	// OpenTelemetry: select the trace of a slow request or of a designated route, as is done for errors
+	if handlerErr == nil && !span.IsEmpty() && c.isInterestingRequest(canonical, time.Since(handlerStartTime)) {
+		c.ForceTrace(ctx)
 	}
 
 	// Meter
-	canonical := s.Canonical()
 	_ = c.RecordHistogram(
 		ctx,
 		"microbus_server_request_duration_seconds",
@@ -691,26 +865,52 @@ func (c *Connector) handleRequest(msg *transport.Msg, s *sub.Subscription) (err
 		}(),
 	)
 
-	// Set control headers on the response
+	// OpenTelemetry: record the status code
+	if handlerErr == nil {
+		span.SetOK(httpRecorder.StatusCode())
+	}
+	span.End()
+	spanEnded = true
+
+	// Durable events are acknowledged to the stream rather than responded to
+	if msg.Delivery > 0 {
+		return handlerErr // No trace
+	}
+
+	// Send the remainder of a streamed response
+	if httpRecorder.Streaming() {
+		err = httpRecorder.End(errRes)
+		return errors.Trace(err)
+	}
+
 	httpResponse := httpRecorder.Result()
-	frame.Of(httpResponse).SetMessageID(msgID)
-	frame.Of(httpResponse).SetFromHost(c.hostname)
-	frame.Of(httpResponse).SetFromID(c.id)
-	frame.Of(httpResponse).SetFromVersion(c.version)
-	frame.Of(httpResponse).SetQueue(queue)
+	if idempotent != nil {
+		// Retain successful responses only, so that failed requests may be retried
+		if handlerErr == nil && httpResponse.StatusCode < 500 {
+			c.endIdempotent(c.Lifetime(), idempotent, httpResponse)
+			idempotent = nil
+		}
+	}
+	if handlerErr == nil && httpReq.Method == "GET" && httpResponse.StatusCode == http.StatusOK {
+		// Spare sending the body if it matches the cached copy held by the caller
+		if ifNoneMatch := httpReq.Header.Get("If-None-Match"); ifNoneMatch != "" {
+			notModified(httpResponse, ifNoneMatch)
+		}
+	}
 	if handlerErr != nil {
-		frame.Of(httpResponse).SetOpCode(frame.OpCodeError)
+		setControlHeaders(httpResponse, frame.OpCodeError)
 	} else {
-		frame.Of(httpResponse).SetOpCode(frame.OpCodeResponse)
+		setControlHeaders(httpResponse, frame.OpCodeResponse)
 	}
-	frame.Of(httpResponse).SetLocality(c.locality)
 
-	// OpenTelemetry: record the status code
-	if handlerErr == nil {
-		span.SetOK(httpResponse.StatusCode)
+	// Fault injection: cut the response short
+	c.injectTruncation(httpReq, httpResponse)
+
+	// Compress large responses
+	err = c.compressResponse(ctx, acceptEncoding, httpResponse)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	span.End()
-	spanEnded = true
 
 	// Send back the response, in fragments if needed
 	fragger, err := httpx.NewFragResponse(httpResponse, c.maxFragmentSize)
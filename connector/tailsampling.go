@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/env"
+)
+
+/*
+initTailSampling reads the criteria by which a microservice marks a trace as interesting, in addition to errors.
+Once marked, the trace is selected for export by all microservices, which push out the spans they had buffered.
+
+MICROBUS_TRACE_LATENCY is the duration of handling a request beyond which its trace is selected, e.g. 2s.
+MICROBUS_TRACE_ROUTES is a comma-separated list of routes whose traces are always selected,
+e.g. my.service/path,my.service:444/prefix/*.
+MICROBUS_TRACE_ROUTES_RATIO is the ratio of the requests to those routes whose traces are selected, between 0 and 1.
+It defaults to 1. Selecting a trace multicasts a notice to all microservices, so busy routes should be sampled.
+*/
+func (c *Connector) initTailSampling() (err error) {
+	c.traceLatency = 0
+	if v := env.Get("MICROBUS_TRACE_LATENCY"); v != "" {
+		c.traceLatency, err = time.ParseDuration(v)
+		if err != nil || c.traceLatency < 0 {
+			return errors.New("invalid trace latency threshold '%s'", v, http.StatusBadRequest)
+		}
+	}
+	c.traceRoutes, err = parseTraceRoutes(env.Get("MICROBUS_TRACE_ROUTES"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.traceRoutesRatio = 1
+	if v := env.Get("MICROBUS_TRACE_ROUTES_RATIO"); v != "" {
+		c.traceRoutesRatio, err = strconv.ParseFloat(v, 64)
+		if err != nil || c.traceRoutesRatio <= 0 || c.traceRoutesRatio > 1 {
+			return errors.New("invalid trace routes ratio '%s'", v, http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+// parseTraceRoutes parses a comma-separated list of routes into their canonical host:port/path form.
+// A missing port defaults to 443 and a trailing * matches any path with that prefix.
+func parseTraceRoutes(routes string) ([]string, error) {
+	var result []string
+	for _, route := range strings.Split(routes, ",") {
+		route = strings.TrimSpace(route)
+		if route == "" {
+			continue
+		}
+		route = strings.TrimPrefix(route, "https://")
+		route = strings.TrimPrefix(route, "//")
+		host, path, _ := strings.Cut(route, "/")
+		if host == "" {
+			return nil, errors.New("missing hostname in trace route '%s'", route, http.StatusBadRequest)
+		}
+		if !strings.Contains(host, ":") {
+			host += ":443"
+		}
+		result = append(result, strings.ToLower(host)+"/"+path)
+	}
+	return result, nil
+}
+
+// isInterestingRequest indicates if the trace of a successful request should be selected for export,
+// based on the canonical route of its subscription and the time it took to handle it.
+// Requests to designated routes are sampled by the trace routes ratio.
+func (c *Connector) isInterestingRequest(canonical string, elapsed time.Duration) bool {
+	if c.traceLatency > 0 && elapsed >= c.traceLatency {
+		return true
+	}
+	for _, route := range c.traceRoutes {
+		matched := canonical == route
+		if prefix, ok := strings.CutSuffix(route, "*"); ok {
+			matched = strings.HasPrefix(canonical, prefix)
+		}
+		if matched {
+			return c.traceRoutesRatio <= 0 || c.traceRoutesRatio >= 1 || rand.Float64() < c.traceRoutesRatio
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_TailSampling(t *testing.T) {
+	// No parallel - Setting envars
+	env.Push("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "nil")
+	defer env.Pop("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	env.Push("MICROBUS_TRACE_LATENCY", "200ms")
+	defer env.Pop("MICROBUS_TRACE_LATENCY")
+	env.Push("MICROBUS_TRACE_ROUTES", "beta.tail.sampling.connector/designated/*")
+	defer env.Pop("MICROBUS_TRACE_ROUTES")
+
+	assert := testarossa.For(t)
+	ctx := t.Context()
+
+	// Create the microservices
+	alpha := New("alpha.tail.sampling.connector")
+	alpha.SetDeployment(PROD)
+
+	var traceID string
+	beta := New("beta.tail.sampling.connector")
+	beta.SetDeployment(PROD)
+	beta.Subscribe("Fast",
+		func(w http.ResponseWriter, r *http.Request) error {
+			traceID = beta.Span(r.Context()).TraceID()
+			return nil
+		},
+		sub.At("GET", "fast"),
+		sub.Web(),
+	)
+	beta.Subscribe("Slow",
+		func(w http.ResponseWriter, r *http.Request) error {
+			traceID = beta.Span(r.Context()).TraceID()
+			time.Sleep(250 * time.Millisecond)
+			return nil
+		},
+		sub.At("GET", "slow"),
+		sub.Web(),
+	)
+	beta.Subscribe("Designated",
+		func(w http.ResponseWriter, r *http.Request) error {
+			traceID = beta.Span(r.Context()).TraceID()
+			return nil
+		},
+		sub.At("GET", "designated/{id}"),
+		sub.Web(),
+	)
+
+	// Startup the microservices
+	err := alpha.Startup(ctx)
+	assert.NoError(err)
+	defer alpha.Shutdown(ctx)
+	err = beta.Startup(ctx)
+	assert.NoError(err)
+	defer beta.Shutdown(ctx)
+
+	// selected waits for the trace to be selected by both microservices
+	selected := func(traceID string) bool {
+		for range 20 {
+			if alpha.traceProcessor.IsSelected(traceID) && beta.traceProcessor.IsSelected(traceID) {
+				return true
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return false
+	}
+
+	// Fast requests are not selected
+	_, err = alpha.GET(ctx, "https://beta.tail.sampling.connector/fast")
+	if assert.NoError(err) && assert.NotEqual("", traceID) {
+		assert.False(selected(traceID))
+	}
+
+	// Slow requests are selected by all microservices
+	_, err = alpha.GET(ctx, "https://beta.tail.sampling.connector/slow")
+	if assert.NoError(err) && assert.NotEqual("", traceID) {
+		assert.True(selected(traceID))
+	}
+
+	// Requests to designated routes are selected by all microservices
+	_, err = alpha.GET(ctx, "https://beta.tail.sampling.connector/designated/123")
+	if assert.NoError(err) && assert.NotEqual("", traceID) {
+		assert.True(selected(traceID))
+	}
+}
+
+func TestConnector_ParseTraceRoutes(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	routes, err := parseTraceRoutes(" https://My.Service/path , //my.service:444/prefix/*,my.service")
+	assert.Expect(
+		routes, []string{"my.service:443/path", "my.service:444/prefix/*", "my.service:443/"},
+		err, nil,
+	)
+	_, err = parseTraceRoutes("/path")
+	assert.Error(err)
+
+	con := New("parse.trace.routes.connector")
+	con.traceRoutes = routes
+	assert.True(con.isInterestingRequest("my.service:443/path", 0))
+	assert.False(con.isInterestingRequest("my.service:443/path/more", 0))
+	assert.True(con.isInterestingRequest("my.service:444/prefix/more", 0))
+	assert.False(con.isInterestingRequest("my.service:443/prefix/more", 0))
+	assert.False(con.isInterestingRequest("my.service:443/other", time.Hour))
+	con.traceLatency = time.Second
+	assert.True(con.isInterestingRequest("my.service:443/other", time.Hour))
+
+	// Requests to designated routes are sampled
+	con.traceRoutesRatio = 0.5
+	selected := 0
+	for range 1000 {
+		if con.isInterestingRequest("my.service:443/path", 0) {
+			selected++
+		}
+	}
+	assert.True(selected > 350 && selected < 650)
+}
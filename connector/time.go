@@ -23,30 +23,50 @@ import (
 
 	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/service"
+	"github.com/microbus-io/fabric/tick"
 	"github.com/microbus-io/fabric/trc"
 	"github.com/microbus-io/fabric/utils"
 )
 
 // tickerCallback holds settings for a user tickerCallback handler, such as the OnStartup and OnShutdown callbacks.
 type tickerCallback struct {
-	Name     string
-	Handler  service.TickerHandler
-	Interval time.Duration
-	Ticker   *time.Ticker
+	Name      string
+	Handler   service.TickerHandler
+	Interval  time.Duration
+	Schedule  *tick.Cron
+	Singleton bool
+	Ticker    *time.Ticker
+	Cancel    context.CancelFunc
+	NextRun   time.Time
+	Running   bool
 }
 
-// StartTicker initiates a recurring job at a set interval.
+// stop stops the ticker if it is running. It must be called under the tickers lock.
+func (job *tickerCallback) stop() {
+	if job.Ticker != nil {
+		job.Ticker.Stop()
+		job.Ticker = nil
+	}
+	if job.Cancel != nil {
+		job.Cancel()
+		job.Cancel = nil
+	}
+	job.NextRun = time.Time{}
+}
+
+// StartTicker initiates a recurring job at a set interval, or on a cron schedule if the [tick.Schedule] option is provided.
 // Tickers do not run when the connector is running in the TESTING deployment environment.
 // Ticker names are case sensitive.
-func (c *Connector) StartTicker(name string, interval time.Duration, handler service.TickerHandler) error {
+func (c *Connector) StartTicker(name string, interval time.Duration, handler service.TickerHandler, options ...tick.Option) error {
 	if name == "" {
 		return c.captureInitErr(errors.New("ticker name is required"))
 	}
 	if handler == nil {
 		return nil
 	}
-	if interval <= 0 {
-		return c.captureInitErr(errors.New("non-positive interval '%v'", interval))
+	tkr, err := tick.NewTicker(name, interval, options...)
+	if err != nil {
+		return c.captureInitErr(errors.Trace(err))
 	}
 
 	c.tickersLock.Lock()
@@ -56,9 +76,11 @@ func (c *Connector) StartTicker(name string, interval time.Duration, handler ser
 		return c.captureInitErr(errors.New("ticker '%s' is already started", name))
 	}
 	c.tickers[name] = &tickerCallback{
-		Name:     name,
-		Handler:  handler,
-		Interval: interval,
+		Name:      name,
+		Handler:   handler,
+		Interval:  tkr.Interval,
+		Schedule:  tkr.Schedule,
+		Singleton: tkr.Singleton,
 	}
 	if c.isPhase(startedUp) {
 		c.runTicker(c.tickers[name])
@@ -73,17 +95,24 @@ func (c *Connector) StartTicker(name string, interval time.Duration, handler ser
 // Ticker names are case sensitive.
 func (c *Connector) StopTicker(name string) error {
 	c.tickersLock.Lock()
-	defer c.tickersLock.Unlock()
 	job, ok := c.tickers[name]
 	if !ok {
+		c.tickersLock.Unlock()
 		err := errors.New("unknown ticker '%s'", name)
 		return c.captureInitErr(err)
 	}
-	if job.Ticker != nil {
-		job.Ticker.Stop()
-		job.Ticker = nil
-	}
+	job.stop()
 	delete(c.tickers, name)
+	singletons := false
+	for _, job := range c.tickers {
+		singletons = singletons || job.Singleton
+	}
+	c.tickersLock.Unlock()
+
+	// Leadership is needed only to run singleton tickers
+	if job.Singleton && !singletons {
+		c.stopLeaderElection(c.Lifetime())
+	}
 	return nil
 }
 
@@ -91,10 +120,7 @@ func (c *Connector) StopTicker(name string) error {
 func (c *Connector) stopTickers() error {
 	c.tickersLock.Lock()
 	for _, job := range c.tickers {
-		if job.Ticker != nil {
-			job.Ticker.Stop()
-			job.Ticker = nil
-		}
+		job.stop()
 	}
 	c.tickersLock.Unlock()
 	return nil
@@ -109,7 +135,7 @@ func (c *Connector) runTickers() {
 	c.tickersLock.Unlock()
 }
 
-// runTicker starts a goroutine to run the ticker.
+// runTicker starts a goroutine to run the ticker. It must be called under the tickers lock.
 func (c *Connector) runTicker(job *tickerCallback) {
 	if c.deployment == TESTING {
 		c.LogDebug(c.Lifetime(), "Ticker disabled while testing",
@@ -120,10 +146,18 @@ func (c *Connector) runTicker(job *tickerCallback) {
 	if job.Handler == nil {
 		return
 	}
-	if job.Ticker != nil {
+	if job.Ticker != nil || job.Cancel != nil {
 		return // Already running
 	}
+	if job.Singleton {
+		c.startLeaderElection()
+	}
+	if job.Schedule != nil {
+		c.runScheduledTicker(job)
+		return
+	}
 	job.Ticker = time.NewTicker(job.Interval)
+	job.NextRun = time.Now().Add(job.Interval)
 	ticker := job.Ticker
 	go func() {
 		c.LogDebug(c.Lifetime(), "Ticker started",
@@ -132,54 +166,23 @@ func (c *Connector) runTicker(job *tickerCallback) {
 		defer c.LogDebug(c.Lifetime(), "Ticker stopped",
 			"name", job.Name,
 		)
-		for range ticker.C {
+		for t := range ticker.C {
+			c.setNextRun(job, t.Add(job.Interval))
 			if !c.isPhase(startedUp) {
 				continue
 			}
-
-			// OpenTelemetry: create a span for the callback
-			handlerName := utils.ToKebabCase(job.Name)
-			ctx, span := c.StartSpan(c.Lifetime(), handlerName, trc.Internal())
-
-			c.pendingOps.Add(1)
-			startTime := time.Now()
-			err := errors.CatchPanic(func() error {
-				return job.Handler(ctx)
-			})
-			if err != nil {
-				c.LogError(ctx, "Running ticker",
-					"error", err,
-					"name", job.Name,
-				)
-				// OpenTelemetry: record the error
-				span.SetError(err)
-				c.ForceTrace(ctx)
-			} else {
-				span.SetOK(http.StatusOK)
+			if job.Singleton && !c.isLeader() {
+				continue
 			}
-			dur := time.Since(startTime)
-			c.pendingOps.Add(-1)
-			_ = c.RecordHistogram(
-				ctx,
-				"microbus_callback_duration_seconds",
-				dur.Seconds(),
-				"name", job.Name,
-				"type", "ticker",
-				"error", func() string {
-					if err != nil {
-						return "ERROR"
-					}
-					return "OK"
-				}(),
-			)
-			span.End()
+			dur := c.fireTicker(job)
 
 			// Drain ticker, in case of a long-running job that spans multiple intervals
 			skipped := 0
 			done := false
 			for !done {
 				select {
-				case <-ticker.C:
+				case t = <-ticker.C:
+					c.setNextRun(job, t.Add(job.Interval))
 					skipped++
 				default:
 					done = true
@@ -196,6 +199,118 @@ func (c *Connector) runTicker(job *tickerCallback) {
 	}()
 }
 
+// runScheduledTicker starts a goroutine to run the ticker on its cron schedule. It must be called under the tickers lock.
+func (c *Connector) runScheduledTicker(job *tickerCallback) {
+	ctx, cancel := context.WithCancel(c.Lifetime())
+	job.Cancel = cancel
+	next := job.Schedule.Next(time.Now())
+	job.NextRun = next
+	go func() {
+		c.LogDebug(c.Lifetime(), "Ticker started",
+			"name", job.Name,
+			"schedule", job.Schedule.String(),
+		)
+		defer c.LogDebug(c.Lifetime(), "Ticker stopped",
+			"name", job.Name,
+		)
+		for !next.IsZero() {
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			var dur time.Duration
+			if c.isPhase(startedUp) && (!job.Singleton || c.isLeader()) {
+				dur = c.fireTicker(job)
+			}
+
+			// Skip the beats that elapsed while the job was running
+			skipped := 0
+			now := time.Now()
+			next = job.Schedule.Next(next)
+			for !next.IsZero() && !next.After(now) {
+				skipped++
+				next = job.Schedule.Next(next)
+			}
+			if skipped > 0 {
+				c.LogWarn(c.Lifetime(), "Ticker skipped",
+					"name", job.Name,
+					"beats", skipped,
+					"runtime", dur,
+				)
+			}
+			if ctx.Err() == nil {
+				c.setNextRun(job, next)
+			}
+		}
+		c.LogWarn(c.Lifetime(), "Ticker schedule has no future beats",
+			"name", job.Name,
+			"schedule", job.Schedule.String(),
+		)
+	}()
+}
+
+// setNextRun records the time the ticker is next due to run.
+func (c *Connector) setNextRun(job *tickerCallback, next time.Time) {
+	c.tickersLock.Lock()
+	if job.Ticker != nil || job.Cancel != nil {
+		job.NextRun = next
+	}
+	c.tickersLock.Unlock()
+}
+
+// setRunning records whether the handler of the ticker is running.
+func (c *Connector) setRunning(job *tickerCallback, running bool) {
+	c.tickersLock.Lock()
+	job.Running = running
+	c.tickersLock.Unlock()
+}
+
+// fireTicker runs the handler of the ticker and returns its runtime.
+func (c *Connector) fireTicker(job *tickerCallback) time.Duration {
+	// OpenTelemetry: create a span for the callback
+	handlerName := utils.ToKebabCase(job.Name)
+	ctx, span := c.StartSpan(c.Lifetime(), handlerName, trc.Internal())
+
+	c.pendingOps.Add(1)
+	startTime := time.Now()
+	c.setRunning(job, true)
+	err := errors.CatchPanic(func() error {
+		return job.Handler(ctx)
+	})
+	c.setRunning(job, false)
+	if err != nil {
+		// OpenTelemetry: record the error
+		span.SetError(err)
+		c.ForceTrace(ctx)
+		c.LogError(ctx, "Running ticker",
+			"error", err,
+			"name", job.Name,
+		)
+	} else {
+		span.SetOK(http.StatusOK)
+	}
+	dur := time.Since(startTime)
+	c.pendingOps.Add(-1)
+	_ = c.RecordHistogram(
+		ctx,
+		"microbus_callback_duration_seconds",
+		dur.Seconds(),
+		"name", job.Name,
+		"type", "ticker",
+		"error", func() string {
+			if err != nil {
+				return "ERROR"
+			}
+			return "OK"
+		}(),
+	)
+	span.End()
+	return dur
+}
+
 // Sleep pauses the current goroutine for the specified duration,
 // or until the provided context or the lifetime context of the microservice is canceled or its deadline is exceeded.
 // It returns nil if the full duration elapsed, or the canceling context's error
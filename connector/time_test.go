@@ -18,11 +18,14 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/openapi"
+	"github.com/microbus-io/fabric/tick"
 	"github.com/microbus-io/testarossa"
 )
 
@@ -269,6 +272,63 @@ func TestConnector_TickerStop(t *testing.T) {
 	<-exit
 }
 
+func TestConnector_TickerSchedule(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	con := New("ticker.schedule.connector")
+	con.SetDeployment(LAB) // Tickers are disabled in TESTING
+
+	noop := func(ctx context.Context) error { return nil }
+	err := con.StartTicker("Digest", 0, noop, tick.Schedule("CRON_TZ=America/New_York 0 7 * * MON-FRI"))
+	assert.NoError(err)
+	err = con.StartTicker("Poll", time.Hour, noop)
+	assert.NoError(err)
+	err = con.StartTicker("Both", time.Hour, noop, tick.Schedule("0 7 * * *"))
+	assert.Error(err)
+	err = con.StartTicker("Invalid", 0, noop, tick.Schedule("0 7 * *"))
+	assert.Error(err)
+	con.initErr = nil
+
+	err = con.Startup(ctx)
+	assert.NoError(err)
+	defer con.Shutdown(ctx)
+
+	// The next run of the tickers should be listed in the OpenAPI document
+	cron, _ := tick.ParseCron("CRON_TZ=America/New_York 0 7 * * MON-FRI")
+	res, err := con.GET(ctx, "https://ticker.schedule.connector:888/openapi.json")
+	if assert.NoError(err) {
+		var doc openapi.Document
+		err = json.NewDecoder(res.Body).Decode(&doc)
+		assert.NoError(err)
+		if assert.Len(doc.XTickers, 2) {
+			assert.Expect(
+				doc.XTickers[0].Name, "Digest",
+				doc.XTickers[0].Schedule, "CRON_TZ=America/New_York 0 7 * * MON-FRI",
+				doc.XTickers[0].Interval, "",
+				doc.XTickers[0].NextRun.Equal(cron.Next(time.Now())), true,
+				doc.XTickers[1].Name, "Poll",
+				doc.XTickers[1].Interval, "1h0m0s",
+			)
+			assert.True(doc.XTickers[1].NextRun.After(time.Now().Add(59 * time.Minute)))
+		}
+	}
+
+	// Stopped tickers should not be listed
+	err = con.StopTicker("Digest")
+	assert.NoError(err)
+	res, err = con.GET(ctx, "https://ticker.schedule.connector:888/openapi.json")
+	if assert.NoError(err) {
+		var doc openapi.Document
+		err = json.NewDecoder(res.Body).Decode(&doc)
+		assert.NoError(err)
+		if assert.Len(doc.XTickers, 1) {
+			assert.Equal("Poll", doc.XTickers[0].Name)
+		}
+	}
+}
+
 func TestConnector_Sleep(t *testing.T) {
 	t.Parallel()
 	ctx := t.Context()
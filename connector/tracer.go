@@ -88,6 +88,10 @@ func (c *Connector) initTracer(ctx context.Context) (err error) {
 		// Trace only explicitly selected transactions
 		c.traceProcessor = newSelectiveProcessor(exp, 8192) // Approx 10MB per microservice
 		sp = c.traceProcessor
+		err = c.initTailSampling()
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	c.traceProvider = sdktrace.NewTracerProvider(
 		sdktrace.WithSampler(sdktrace.ParentBased(newMuffler())),
@@ -148,7 +152,8 @@ func (c *Connector) TracerProvider() trace.TracerProvider {
 	return c.traceProvider
 }
 
-// ForceTrace forces the trace containing the span to be exported
+// ForceTrace forces the trace containing the span to be exported.
+// The selection is broadcast to all microservices so that they too export the spans they hold for the trace.
 func (c *Connector) ForceTrace(ctx context.Context) {
 	if c.traceProcessor != nil {
 		traceID := c.Span(ctx).TraceID()
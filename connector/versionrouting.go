@@ -0,0 +1,186 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+)
+
+// VersionRoutingConfig is the name of the config property that the connector obtains from the configurator
+// to route unicast requests among the versions of the microservices it calls.
+// The value follows the format of [Connector.SetVersionRouting], for example:
+//
+//	all:
+//	  Microbus.VersionRouting: payments.example=7:5,*:95
+const VersionRoutingConfig = "Microbus.VersionRouting"
+
+// anyVersion designates the replicas of all versions of a microservice in the routing table.
+const anyVersion = -1
+
+// versionWeight is the relative share of unicast requests routed to a version of a microservice.
+type versionWeight struct {
+	version int
+	weight  int
+}
+
+// parseVersionRouting parses a routing table in the format of [Connector.SetVersionRouting].
+func parseVersionRouting(routing string) (routes map[string][]versionWeight, err error) {
+	routes = map[string][]versionWeight{}
+	entries := strings.FieldsFunc(routing, func(r rune) bool {
+		return r == ';' || r == '\n'
+	})
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		hostname, weights, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, errors.New("invalid version routing '%s'", entry)
+		}
+		hostname = strings.ToLower(strings.TrimSpace(hostname))
+		err = httpx.ValidateHostname(hostname)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var vws []versionWeight
+		total := 0
+		for w := range strings.SplitSeq(weights, ",") {
+			ver, weight, ok := strings.Cut(strings.TrimSpace(w), ":")
+			if !ok {
+				return nil, errors.New("invalid version weight '%s' of '%s'", w, hostname)
+			}
+			vw := versionWeight{version: anyVersion}
+			if ver = strings.TrimSpace(ver); ver != "*" {
+				vw.version, err = strconv.Atoi(ver)
+				if err != nil || vw.version < 0 {
+					return nil, errors.New("invalid version '%s' of '%s'", ver, hostname)
+				}
+			}
+			vw.weight, err = strconv.Atoi(strings.TrimSpace(weight))
+			if err != nil || vw.weight < 0 {
+				return nil, errors.New("invalid weight '%s' of '%s'", weight, hostname)
+			}
+			total += vw.weight
+			vws = append(vws, vw)
+		}
+		if total == 0 {
+			return nil, errors.New("zero total weight of '%s'", hostname)
+		}
+		routes[hostname] = vws
+	}
+	return routes, nil
+}
+
+/*
+SetVersionRouting routes the unicast requests that this microservice makes to other microservices
+among the versions of their replicas by weight. It is used to canary a new version of a microservice,
+or to shift traffic between two versions in a blue/green deployment, without a separate hostname.
+
+The routing table lists the relative weights of the versions of each hostname.
+Entries are separated by a semicolon or a new line. The version * designates the replicas of all versions.
+For example, to route 5% of requests to version 7 of payments.example and the rest to any of its replicas:
+
+	payments.example=7:5,*:95
+
+Requests routed to a version that has no running replicas to acknowledge them are routed to replicas of any version instead.
+Errors returned by the replicas of the version, including 404, are not retried.
+Requests pinned to a version with [pub.Version] are not subject to routing.
+Outside the TESTING deployment, the routing table is also obtained from the configurator
+via the [VersionRoutingConfig] config property and overrides the one set by this method when it changes.
+*/
+func (c *Connector) SetVersionRouting(routing string) error {
+	routes, err := parseVersionRouting(routing)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.versionRoutesLock.Lock()
+	c.versionRoutes = routes
+	c.versionRoutesLock.Unlock()
+	return nil
+}
+
+// routeVersion returns the version of the destination of a unicast request, if it is pinned or routed by weight.
+// The request is not routed if its hostname addresses a specific replica, locality or version.
+func (c *Connector) routeVersion(req *pub.Request) (version int, pinned bool, routed bool) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return 0, false, false
+	}
+	host, idOrLocality := cutIDOrLocality(u.Hostname())
+	if idOrLocality != "" {
+		return 0, false, false
+	}
+	if req.Version != nil {
+		return *req.Version, true, true
+	}
+	c.versionRoutesLock.RLock()
+	vws := c.versionRoutes[strings.ToLower(host)]
+	c.versionRoutesLock.RUnlock()
+	if len(vws) == 0 {
+		return 0, false, false
+	}
+	total := 0
+	for _, vw := range vws {
+		total += vw.weight
+	}
+	r := rand.IntN(total)
+	for _, vw := range vws {
+		if r < vw.weight {
+			if vw.version == anyVersion {
+				return 0, false, false
+			}
+			return vw.version, false, true
+		}
+		r -= vw.weight
+	}
+	return 0, false, false
+}
+
+// applyVersionRouting replaces the routing table with the one obtained from the configurator, if it changed.
+// An invalid routing table is logged and the current one is left in place.
+func (c *Connector) applyVersionRouting(ctx context.Context, routing string) {
+	c.versionRoutesLock.RLock()
+	changed := routing != c.versionRoutingConfig
+	c.versionRoutesLock.RUnlock()
+	if !changed {
+		return
+	}
+	routes, err := parseVersionRouting(routing)
+	if err != nil {
+		c.LogWarn(ctx, "Invalid version routing",
+			"routing", routing,
+			"error", err,
+		)
+		return
+	}
+	c.versionRoutesLock.Lock()
+	c.versionRoutes = routes
+	c.versionRoutingConfig = routing
+	c.versionRoutesLock.Unlock()
+	c.LogInfo(ctx, "Version routing updated",
+		"routing", routing,
+	)
+}
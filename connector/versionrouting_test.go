@@ -0,0 +1,159 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestConnector_ParseVersionRouting(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	routes, err := parseVersionRouting(" Payments.Example = 7:5, *:95 ;catalog.example=3:1\ninventory.example=1:0,2:1\n")
+	if assert.NoError(err) {
+		assert.Expect(
+			routes["payments.example"], []versionWeight{{7, 5}, {anyVersion, 95}},
+			routes["catalog.example"], []versionWeight{{3, 1}},
+			routes["inventory.example"], []versionWeight{{1, 0}, {2, 1}},
+		)
+		assert.Len(routes, 3)
+	}
+
+	routes, err = parseVersionRouting("")
+	assert.NoError(err)
+	assert.Len(routes, 0)
+
+	for _, bad := range []string{
+		"payments.example",
+		"payments.example=7",
+		"payments.example=x:5",
+		"payments.example=-1:5",
+		"payments.example=7:-5",
+		"payments.example=7:0",
+		"Bad_Host=7:5",
+	} {
+		_, err = parseVersionRouting(bad)
+		assert.Error(err, "%s", bad)
+	}
+}
+
+func TestConnector_VersionRouting(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	ctx := t.Context()
+
+	handler := func(version int) HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			w.Write([]byte(strconv.Itoa(version)))
+			return nil
+		}
+	}
+	var missingCalls atomic.Int32
+	missing := func(w http.ResponseWriter, r *http.Request) error {
+		missingCalls.Add(1)
+		return errors.New("", http.StatusNotFound)
+	}
+	v1 := New("version.routing.connector")
+	v1.SetVersion(1)
+	v1.Subscribe("Version", handler(1), sub.At("GET", "/version"), sub.Web())
+	v1.Subscribe("Missing", missing, sub.At("POST", "/missing"), sub.Web())
+	v2 := New("version.routing.connector")
+	v2.SetVersion(2)
+	v2.Subscribe("Version", handler(2), sub.At("GET", "/version"), sub.Web())
+	v2.Subscribe("Missing", missing, sub.At("POST", "/missing"), sub.Web())
+	client := New("client.version.routing.connector")
+
+	err := v1.Startup(ctx)
+	assert.NoError(err)
+	defer v1.Shutdown(ctx)
+	err = v2.Startup(ctx)
+	assert.NoError(err)
+	defer v2.Shutdown(ctx)
+	err = client.Startup(ctx)
+	assert.NoError(err)
+	defer client.Shutdown(ctx)
+
+	versionsOf := func(n int, options ...pub.Option) map[string]int {
+		counts := map[string]int{}
+		options = append([]pub.Option{pub.GET("https://version.routing.connector/version")}, options...)
+		for range n {
+			res, err := client.Request(ctx, options...)
+			if assert.NoError(err) {
+				b, _ := io.ReadAll(res.Body)
+				counts[string(b)]++
+			}
+		}
+		return counts
+	}
+
+	// Without routing, requests are load balanced among all versions
+	counts := versionsOf(64)
+	assert.True(counts["1"] > 0 && counts["2"] > 0, "%v", counts)
+
+	// All requests are routed to version 2
+	err = client.SetVersionRouting("version.routing.connector=2:1")
+	assert.NoError(err)
+	counts = versionsOf(32)
+	assert.Equal(32, counts["2"])
+
+	// Requests are split between the versions by weight
+	err = client.SetVersionRouting("version.routing.connector=1:1,2:3")
+	assert.NoError(err)
+	counts = versionsOf(64)
+	assert.True(counts["1"] > 0 && counts["2"] > counts["1"], "%v", counts)
+
+	// Pinning overrides the routing
+	counts = versionsOf(16, pub.Version(1))
+	assert.Equal(16, counts["1"])
+
+	// Requests routed to a version that is not running fall back to any version
+	err = client.SetVersionRouting("version.routing.connector=3:1")
+	assert.NoError(err)
+	counts = versionsOf(16)
+	assert.Equal(16, counts["1"]+counts["2"])
+
+	// Requests pinned to a version that is not running fail
+	_, err = client.Request(ctx, pub.GET("https://version.routing.connector/version"), pub.Version(3))
+	if assert.Error(err) {
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	}
+
+	// Addressing a specific replica is not subject to routing
+	err = client.SetVersionRouting("version.routing.connector=2:1")
+	assert.NoError(err)
+	res, err := client.Request(ctx, pub.GET("https://"+v1.ID()+".version.routing.connector/version"))
+	if assert.NoError(err) {
+		b, _ := io.ReadAll(res.Body)
+		assert.Equal("1", string(b))
+	}
+
+	// A 404 returned by the handler of the routed version is not retried on another version
+	_, err = client.Request(ctx, pub.POST("https://version.routing.connector/missing"))
+	assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	assert.Equal(int32(1), missingCalls.Load())
+}
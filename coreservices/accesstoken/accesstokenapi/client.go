@@ -97,6 +97,7 @@ func marshalRequest(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	if err != nil {
@@ -124,6 +125,7 @@ func marshalPublish(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	return func(yield func(*multicastResponse) bool) {
@@ -149,7 +149,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
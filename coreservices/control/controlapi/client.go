@@ -23,6 +23,7 @@ import (
 	"iter"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/httpx"
@@ -142,6 +143,7 @@ func marshalRequest(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	if err != nil {
@@ -169,6 +171,7 @@ func marshalPublish(ctx context.Context, svc service.Publisher, opts []pub.Optio
 		pub.URL(u),
 		pub.Query(query),
 		pub.Body(body),
+		pub.Header("Accept", httpx.AcceptHeader(httpx.CBOR)),
 		pub.Options(opts...),
 	)
 	return func(yield func(*multicastResponse) bool) {
@@ -201,7 +204,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -338,6 +341,148 @@ func (_c MulticastClient) OpenAPI(ctx context.Context) iter.Seq[*OpenAPIResponse
 	}
 }
 
+// Leader returns the ID of the instance elected leader among the instances of the microservice. Only microservices that run singleton tickers elect a leader.
+func (_c Client) Leader(ctx context.Context) (leader string, err error) { // MARKER: Leader
+	_in := LeaderIn{}
+	_out := LeaderOut{}
+	err = marshalRequest(ctx, _c.svc, _c.opts, _c.host, Leader.Method, Leader.Route, &_in, &_out)
+	return _out.Leader, err // No trace
+}
+
+// LeaderResponse packs the response of Leader.
+type LeaderResponse multicastResponse // MARKER: Leader
+
+// Get unpacks the return arguments of Leader.
+func (_res *LeaderResponse) Get() (leader string, err error) { // MARKER: Leader
+	_d := _res.data.(*LeaderOut)
+	return _d.Leader, _res.err
+}
+
+// Leader returns the ID of the instance elected leader among the instances of the microservice. Only microservices that run singleton tickers elect a leader.
+func (_c MulticastClient) Leader(ctx context.Context) iter.Seq[*LeaderResponse] { // MARKER: Leader
+	_in := LeaderIn{}
+	_out := LeaderOut{}
+	_queue := marshalPublish(ctx, _c.svc, _c.opts, _c.host, Leader.Method, Leader.Route, &_in, &_out)
+	return func(yield func(*LeaderResponse) bool) {
+		for _r := range _queue {
+			_clone := _out
+			_r.data = &_clone
+			if !yield((*LeaderResponse)(_r)) {
+				return
+			}
+		}
+	}
+}
+
+// InjectFaults replaces the fault injection rules of the microservice. Fault injection is not allowed in the PROD deployment.
+// The actor of the request must satisfy the claims set in the MICROBUS_FAULT_INJECTION_CLAIMS env var, roles.admin by default.
+func (_c Client) InjectFaults(ctx context.Context, rules string) (err error) { // MARKER: InjectFaults
+	_in := InjectFaultsIn{Rules: rules}
+	_out := InjectFaultsOut{}
+	err = marshalRequest(ctx, _c.svc, _c.opts, _c.host, InjectFaults.Method, InjectFaults.Route, &_in, &_out)
+	return err // No trace
+}
+
+// InjectFaultsResponse packs the response of InjectFaults.
+type InjectFaultsResponse multicastResponse // MARKER: InjectFaults
+
+// Get unpacks the return arguments of InjectFaults.
+func (_res *InjectFaultsResponse) Get() (err error) { // MARKER: InjectFaults
+	return _res.err
+}
+
+// InjectFaults replaces the fault injection rules of the microservice. Fault injection is not allowed in the PROD deployment.
+// The actor of the request must satisfy the claims set in the MICROBUS_FAULT_INJECTION_CLAIMS env var, roles.admin by default.
+func (_c MulticastClient) InjectFaults(ctx context.Context, rules string) iter.Seq[*InjectFaultsResponse] { // MARKER: InjectFaults
+	_in := InjectFaultsIn{Rules: rules}
+	_out := InjectFaultsOut{}
+	_queue := marshalPublish(ctx, _c.svc, _c.opts, _c.host, InjectFaults.Method, InjectFaults.Route, &_in, &_out)
+	return func(yield func(*InjectFaultsResponse) bool) {
+		for _r := range _queue {
+			_clone := _out
+			_r.data = &_clone
+			if !yield((*InjectFaultsResponse)(_r)) {
+				return
+			}
+		}
+	}
+}
+
+// LogLevel changes the minimum level of the messages logged by the microservice to DEBUG, INFO, WARN or ERROR.
+// The change reverts automatically after the duration, which defaults to 15 minutes.
+// If a trace ID or baggage are specified, the change applies only to messages logged in the context of
+// requests that carry that trace ID and all of those baggage values.
+// An empty level reverts the change immediately.
+// The actor of the request must satisfy the claims set in the MICROBUS_LOG_LEVEL_CLAIMS env var, roles.admin by default.
+func (_c Client) LogLevel(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error) { // MARKER: LogLevel
+	_in := LogLevelIn{Level: level, Duration: duration, TraceID: traceID, Baggage: baggage}
+	_out := LogLevelOut{}
+	err = marshalRequest(ctx, _c.svc, _c.opts, _c.host, LogLevel.Method, LogLevel.Route, &_in, &_out)
+	return err // No trace
+}
+
+// LogLevelResponse packs the response of LogLevel.
+type LogLevelResponse multicastResponse // MARKER: LogLevel
+
+// Get unpacks the return arguments of LogLevel.
+func (_res *LogLevelResponse) Get() (err error) { // MARKER: LogLevel
+	return _res.err
+}
+
+// LogLevel changes the minimum level of the messages logged by the microservice to DEBUG, INFO, WARN or ERROR.
+// The change reverts automatically after the duration, which defaults to 15 minutes.
+// If a trace ID or baggage are specified, the change applies only to messages logged in the context of
+// requests that carry that trace ID and all of those baggage values.
+// An empty level reverts the change immediately.
+// The actor of the request must satisfy the claims set in the MICROBUS_LOG_LEVEL_CLAIMS env var, roles.admin by default.
+func (_c MulticastClient) LogLevel(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) iter.Seq[*LogLevelResponse] { // MARKER: LogLevel
+	_in := LogLevelIn{Level: level, Duration: duration, TraceID: traceID, Baggage: baggage}
+	_out := LogLevelOut{}
+	_queue := marshalPublish(ctx, _c.svc, _c.opts, _c.host, LogLevel.Method, LogLevel.Route, &_in, &_out)
+	return func(yield func(*LogLevelResponse) bool) {
+		for _r := range _queue {
+			_clone := _out
+			_r.data = &_clone
+			if !yield((*LogLevelResponse)(_r)) {
+				return
+			}
+		}
+	}
+}
+
+// Health reports the outcome of the health checks of the microservice, including those set with SetOnHealthCheck.
+func (_c Client) Health(ctx context.Context) (healthy bool, checks map[string]HealthCheck, err error) { // MARKER: Health
+	_in := HealthIn{}
+	_out := HealthOut{}
+	err = marshalRequest(ctx, _c.svc, _c.opts, _c.host, Health.Method, Health.Route, &_in, &_out)
+	return _out.Healthy, _out.Checks, err // No trace
+}
+
+// HealthResponse packs the response of Health.
+type HealthResponse multicastResponse // MARKER: Health
+
+// Get unpacks the return arguments of Health.
+func (_res *HealthResponse) Get() (healthy bool, checks map[string]HealthCheck, err error) { // MARKER: Health
+	_d := _res.data.(*HealthOut)
+	return _d.Healthy, _d.Checks, _res.err
+}
+
+// Health reports the outcome of the health checks of the microservice, including those set with SetOnHealthCheck.
+func (_c MulticastClient) Health(ctx context.Context) iter.Seq[*HealthResponse] { // MARKER: Health
+	_in := HealthIn{}
+	_out := HealthOut{}
+	_queue := marshalPublish(ctx, _c.svc, _c.opts, _c.host, Health.Method, Health.Route, &_in, &_out)
+	return func(yield func(*HealthResponse) bool) {
+		for _r := range _queue {
+			_clone := _out
+			_r.data = &_clone
+			if !yield((*HealthResponse)(_r)) {
+				return
+			}
+		}
+	}
+}
+
 // Metrics returns the Prometheus metrics collected by the microservice.
 func (_c Client) Metrics(ctx context.Context, method string, relativeURL string, body any) (res *http.Response, err error) { // MARKER: Metrics
 	if method == "" {
@@ -374,6 +519,34 @@ func (_c MulticastClient) Metrics(ctx context.Context, method string, relativeUR
 	)
 }
 
+// Pprof returns a runtime profile of the process of the microservice in the format of go tool pprof.
+// The profile is one of heap, goroutine, allocs, block, mutex, threadcreate, or profile for a CPU profile
+// or trace for an execution trace that are collected over the number of seconds in the query argument.
+// The actor of the request must satisfy the claims set in the MICROBUS_PPROF_CLAIMS env var, roles.admin by default.
+func (_c Client) Pprof(ctx context.Context, relativeURL string) (res *http.Response, err error) { // MARKER: Pprof
+	return _c.svc.Request(
+		ctx,
+		pub.Method(Pprof.Method),
+		pub.URL(httpx.JoinHostAndPath(_c.host, Pprof.Route)),
+		pub.RelativeURL(relativeURL),
+		pub.Options(_c.opts...),
+	)
+}
+
+// Pprof returns a runtime profile of the process of the microservice in the format of go tool pprof.
+// The profile is one of heap, goroutine, allocs, block, mutex, threadcreate, or profile for a CPU profile
+// or trace for an execution trace that are collected over the number of seconds in the query argument.
+// The actor of the request must satisfy the claims set in the MICROBUS_PPROF_CLAIMS env var, roles.admin by default.
+func (_c MulticastClient) Pprof(ctx context.Context, relativeURL string) iter.Seq[*pub.Response] { // MARKER: Pprof
+	return _c.svc.Publish(
+		ctx,
+		pub.Method(Pprof.Method),
+		pub.URL(httpx.JoinHostAndPath(_c.host, Pprof.Route)),
+		pub.RelativeURL(relativeURL),
+		pub.Options(_c.opts...),
+	)
+}
+
 // OnNewSubsResponse packs the response of OnNewSubs.
 type OnNewSubsResponse multicastResponse // MARKER: OnNewSubs
 
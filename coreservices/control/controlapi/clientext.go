@@ -26,9 +26,15 @@ package controlapi
 import (
 	"context"
 	"fmt"
+	"io"
 	"iter"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
 )
 
 // ServiceInfo is a descriptor of the microservice that answers the ping.
@@ -110,3 +116,51 @@ func (_c MulticastClient) PingInstances(ctx context.Context) iter.Seq[*ServiceIn
 		}
 	}
 }
+
+// Profile is a runtime profile collected from an instance of a microservice.
+type Profile struct {
+	Hostname string
+	ID       string
+	Data     []byte
+	Err      error
+}
+
+/*
+CollectProfiles collects the named runtime profile from all instances of the microservice in one go.
+The profile is one of heap, goroutine, allocs, block, mutex, threadcreate, or profile for a CPU profile
+or trace for an execution trace that are collected over the duration, which is otherwise ignored.
+The actor of the request must satisfy the claims required by the :888/pprof control endpoint.
+
+	client := controlapi.NewMulticastClient(svc).ForHost("my.service").WithOptions(pub.Token(adminToken))
+	for profile := range client.CollectProfiles(ctx, "heap", 0) {
+		os.WriteFile(profile.ID+".heap.pb.gz", profile.Data, 0666)
+	}
+*/
+func (_c MulticastClient) CollectProfiles(ctx context.Context, profile string, duration time.Duration) iter.Seq[*Profile] {
+	relativeURL := url.PathEscape(profile)
+	client := _c
+	if profile == "profile" || profile == "trace" {
+		seconds := max(int(duration.Seconds()), 1)
+		relativeURL += "?seconds=" + strconv.Itoa(seconds)
+		client = _c.WithOptions(pub.Timeout(time.Duration(seconds)*time.Second + 10*time.Second))
+	}
+	return func(yield func(*Profile) bool) {
+		for res := range client.Pprof(ctx, relativeURL) {
+			p := &Profile{}
+			httpRes, err := res.Get()
+			if err != nil {
+				p.Err = errors.Trace(err)
+			} else {
+				p.Hostname = frame.Of(httpRes).FromHost()
+				p.ID = frame.Of(httpRes).FromID()
+				p.Data, err = io.ReadAll(httpRes.Body)
+				if err != nil {
+					p.Err = errors.Trace(err)
+				}
+			}
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package controlapi
 
 import (
+	"time"
+
 	"github.com/microbus-io/fabric/define"
 )
 
@@ -114,8 +116,96 @@ type OpenAPIOut struct { // MARKER: OpenAPI
 	HTTPStatusCode   int       `json:"-"`
 }
 
+// Leader returns the ID of the instance elected leader among the instances of the microservice. Only microservices that run singleton tickers elect a leader.
+var Leader = define.Function{ // MARKER: Leader
+	Host: Hostname, Method: "ANY", Route: ":888/leader",
+	In: LeaderIn{}, Out: LeaderOut{},
+}
+
+// LeaderIn are the input arguments of Leader.
+type LeaderIn struct { // MARKER: Leader
+}
+
+// LeaderOut are the output arguments of Leader.
+type LeaderOut struct { // MARKER: Leader
+	Leader string `json:"leader,omitzero"`
+}
+
+/*
+InjectFaults replaces the fault injection rules of the microservice. Fault injection is not allowed in the PROD deployment.
+The actor of the request must satisfy the claims set in the MICROBUS_FAULT_INJECTION_CLAIMS env var, roles.admin by default.
+*/
+var InjectFaults = define.Function{ // MARKER: InjectFaults
+	Host: Hostname, Method: "POST", Route: ":888/inject-faults",
+	LoadBalancing: define.None,
+	In:            InjectFaultsIn{}, Out: InjectFaultsOut{},
+}
+
+// InjectFaultsIn are the input arguments of InjectFaults.
+type InjectFaultsIn struct { // MARKER: InjectFaults
+	Rules string `json:"rules,omitzero"`
+}
+
+// InjectFaultsOut are the output arguments of InjectFaults.
+type InjectFaultsOut struct { // MARKER: InjectFaults
+}
+
+/*
+LogLevel changes the minimum level of the messages logged by the microservice to DEBUG, INFO, WARN or ERROR.
+The change reverts automatically after the duration, which defaults to 15 minutes.
+If a trace ID or baggage are specified, the change applies only to messages logged in the context of
+requests that carry that trace ID and all of those baggage values.
+An empty level reverts the change immediately.
+The actor of the request must satisfy the claims set in the MICROBUS_LOG_LEVEL_CLAIMS env var, roles.admin by default.
+*/
+var LogLevel = define.Function{ // MARKER: LogLevel
+	Host: Hostname, Method: "POST", Route: ":888/log-level",
+	LoadBalancing: define.None,
+	In:            LogLevelIn{}, Out: LogLevelOut{},
+}
+
+// LogLevelIn are the input arguments of LogLevel.
+type LogLevelIn struct { // MARKER: LogLevel
+	Level    string            `json:"level,omitzero"`
+	Duration time.Duration     `json:"duration,omitzero"`
+	TraceID  string            `json:"traceID,omitzero"`
+	Baggage  map[string]string `json:"baggage,omitzero"`
+}
+
+// LogLevelOut are the output arguments of LogLevel.
+type LogLevelOut struct { // MARKER: LogLevel
+}
+
+// Health reports the outcome of the health checks of the microservice, including those set with SetOnHealthCheck.
+var Health = define.Function{ // MARKER: Health
+	Host: Hostname, Method: "ANY", Route: ":888/health",
+	LoadBalancing: define.None,
+	In:            HealthIn{}, Out: HealthOut{},
+}
+
+// HealthIn are the input arguments of Health.
+type HealthIn struct { // MARKER: Health
+}
+
+// HealthOut are the output arguments of Health.
+type HealthOut struct { // MARKER: Health
+	Healthy bool                   `json:"healthy,omitzero"`
+	Checks  map[string]HealthCheck `json:"checks,omitzero"`
+}
+
 // Metrics returns the Prometheus metrics collected by the microservice.
 var Metrics = define.Web{ // MARKER: Metrics
 	Host: Hostname, Method: "ANY", Route: ":888/metrics",
 	LoadBalancing: define.None,
 }
+
+/*
+Pprof returns a runtime profile of the process of the microservice in the format of go tool pprof.
+The profile is one of heap, goroutine, allocs, block, mutex, threadcreate, or profile for a CPU profile
+or trace for an execution trace that are collected over the number of seconds in the query argument.
+The actor of the request must satisfy the claims set in the MICROBUS_PPROF_CLAIMS env var, roles.admin by default.
+*/
+var Pprof = define.Web{ // MARKER: Pprof
+	Host: Hostname, Method: "GET", Route: ":888/pprof/{profile}",
+	LoadBalancing: define.None,
+}
@@ -21,6 +21,7 @@ package control
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/connector"
@@ -41,11 +42,16 @@ const (
 type ToDo interface {
 	OnStartup(ctx context.Context) (err error)
 	OnShutdown(ctx context.Context) (err error)
-	Ping(ctx context.Context) (pong int, err error)                                                     // MARKER: Ping
-	ConfigRefresh(ctx context.Context) (err error)                                                      // MARKER: ConfigRefresh
-	Trace(ctx context.Context, id string) (err error)                                                   // MARKER: Trace
-	OpenAPI(ctx context.Context) (httpResponseBody *controlapi.Document, httpStatusCode int, err error) // MARKER: OpenAPI
-	Metrics(w http.ResponseWriter, r *http.Request) (err error)                                         // MARKER: Metrics
+	Ping(ctx context.Context) (pong int, err error)                                                                            // MARKER: Ping
+	ConfigRefresh(ctx context.Context) (err error)                                                                             // MARKER: ConfigRefresh
+	Trace(ctx context.Context, id string) (err error)                                                                          // MARKER: Trace
+	OpenAPI(ctx context.Context) (httpResponseBody *controlapi.Document, httpStatusCode int, err error)                        // MARKER: OpenAPI
+	Leader(ctx context.Context) (leader string, err error)                                                                     // MARKER: Leader
+	InjectFaults(ctx context.Context, rules string) (err error)                                                                // MARKER: InjectFaults
+	LogLevel(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error) // MARKER: LogLevel
+	Health(ctx context.Context) (healthy bool, checks map[string]controlapi.HealthCheck, err error)                            // MARKER: Health
+	Metrics(w http.ResponseWriter, r *http.Request) (err error)                                                                // MARKER: Metrics
+	Pprof(w http.ResponseWriter, r *http.Request) (err error)                                                                  // MARKER: Pprof
 }
 
 // NewService creates a new instance of the microservice.
@@ -110,6 +116,39 @@ func NewIntermediate(impl ToDo) *Intermediate {
 		sub.Description(`OpenAPI returns the OpenAPI 3.1 document of the microservice. Returns endpoints across all ports filtered by the caller's claims; consumers (portal/MCP) apply any port-based filtering at their ingress boundary.`),
 		sub.Function(controlapi.OpenAPIIn{}, controlapi.OpenAPIOut{}),
 	)
+	svc.Subscribe( // MARKER: Leader
+		"Leader", svc.doLeader,
+		sub.At(controlapi.Leader.Method, controlapi.Leader.Route),
+		sub.Description(`Leader returns the ID of the instance elected leader among the instances of the microservice. Only microservices that run singleton tickers elect a leader.`),
+		sub.Function(controlapi.LeaderIn{}, controlapi.LeaderOut{}),
+	)
+	svc.Subscribe( // MARKER: InjectFaults
+		"InjectFaults", svc.doInjectFaults,
+		sub.At(controlapi.InjectFaults.Method, controlapi.InjectFaults.Route),
+		sub.Description(`InjectFaults replaces the fault injection rules of the microservice. Fault injection is not allowed in the PROD deployment.
+The actor of the request must satisfy the claims set in the MICROBUS_FAULT_INJECTION_CLAIMS env var, roles.admin by default.`),
+		sub.NoQueue(),
+		sub.Function(controlapi.InjectFaultsIn{}, controlapi.InjectFaultsOut{}),
+	)
+	svc.Subscribe( // MARKER: LogLevel
+		"LogLevel", svc.doLogLevel,
+		sub.At(controlapi.LogLevel.Method, controlapi.LogLevel.Route),
+		sub.Description(`LogLevel changes the minimum level of the messages logged by the microservice to DEBUG, INFO, WARN or ERROR.
+The change reverts automatically after the duration, which defaults to 15 minutes.
+If a trace ID or baggage are specified, the change applies only to messages logged in the context of
+requests that carry that trace ID and all of those baggage values.
+An empty level reverts the change immediately.
+The actor of the request must satisfy the claims set in the MICROBUS_LOG_LEVEL_CLAIMS env var, roles.admin by default.`),
+		sub.NoQueue(),
+		sub.Function(controlapi.LogLevelIn{}, controlapi.LogLevelOut{}),
+	)
+	svc.Subscribe( // MARKER: Health
+		"Health", svc.doHealth,
+		sub.At(controlapi.Health.Method, controlapi.Health.Route),
+		sub.Description(`Health reports the outcome of the health checks of the microservice, including those set with SetOnHealthCheck.`),
+		sub.NoQueue(),
+		sub.Function(controlapi.HealthIn{}, controlapi.HealthOut{}),
+	)
 	svc.Subscribe( // MARKER: Metrics
 		"Metrics", svc.Metrics,
 		sub.At(controlapi.Metrics.Method, controlapi.Metrics.Route),
@@ -117,6 +156,16 @@ func NewIntermediate(impl ToDo) *Intermediate {
 		sub.NoQueue(),
 		sub.Web(),
 	)
+	svc.Subscribe( // MARKER: Pprof
+		"Pprof", svc.Pprof,
+		sub.At(controlapi.Pprof.Method, controlapi.Pprof.Route),
+		sub.Description(`Pprof returns a runtime profile of the process of the microservice in the format of go tool pprof.
+The profile is one of heap, goroutine, allocs, block, mutex, threadcreate, or profile for a CPU profile
+or trace for an execution trace that are collected over the number of seconds in the query argument.
+The actor of the request must satisfy the claims set in the MICROBUS_PPROF_CLAIMS env var, roles.admin by default.`),
+		sub.NoQueue(),
+		sub.Web(),
+	)
 
 	return svc
 }
@@ -141,7 +190,7 @@ func marshalFunction(w http.ResponseWriter, r *http.Request, route string, in an
 	if err != nil {
 		return err // No trace
 	}
-	err = httpx.WriteOutputPayload(w, out)
+	err = httpx.WriteNegotiatedOutputPayload(w, r, out)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -191,3 +240,47 @@ func (svc *Intermediate) doOpenAPI(w http.ResponseWriter, r *http.Request) (err
 	})
 	return err // No trace
 }
+
+// doLeader handles marshaling for Leader.
+func (svc *Intermediate) doLeader(w http.ResponseWriter, r *http.Request) (err error) { // MARKER: Leader
+	var in controlapi.LeaderIn
+	var out controlapi.LeaderOut
+	err = marshalFunction(w, r, controlapi.Leader.Route, &in, &out, func(_ any, _ any) error {
+		out.Leader, err = svc.Leader(r.Context())
+		return err // No trace
+	})
+	return err // No trace
+}
+
+// doInjectFaults handles marshaling for InjectFaults.
+func (svc *Intermediate) doInjectFaults(w http.ResponseWriter, r *http.Request) (err error) { // MARKER: InjectFaults
+	var in controlapi.InjectFaultsIn
+	var out controlapi.InjectFaultsOut
+	err = marshalFunction(w, r, controlapi.InjectFaults.Route, &in, &out, func(_ any, _ any) error {
+		err = svc.InjectFaults(r.Context(), in.Rules)
+		return err // No trace
+	})
+	return err // No trace
+}
+
+// doLogLevel handles marshaling for LogLevel.
+func (svc *Intermediate) doLogLevel(w http.ResponseWriter, r *http.Request) (err error) { // MARKER: LogLevel
+	var in controlapi.LogLevelIn
+	var out controlapi.LogLevelOut
+	err = marshalFunction(w, r, controlapi.LogLevel.Route, &in, &out, func(_ any, _ any) error {
+		err = svc.LogLevel(r.Context(), in.Level, in.Duration, in.TraceID, in.Baggage)
+		return err // No trace
+	})
+	return err // No trace
+}
+
+// doHealth handles marshaling for Health.
+func (svc *Intermediate) doHealth(w http.ResponseWriter, r *http.Request) (err error) { // MARKER: Health
+	var in controlapi.HealthIn
+	var out controlapi.HealthOut
+	err = marshalFunction(w, r, controlapi.Health.Route, &in, &out, func(_ any, _ any) error {
+		out.Healthy, out.Checks, err = svc.Health(r.Context())
+		return err // No trace
+	})
+	return err // No trace
+}
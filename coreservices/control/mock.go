@@ -21,6 +21,7 @@ package control
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/microbus-io/errors"
 	"github.com/microbus-io/fabric/connector"
@@ -30,11 +31,16 @@ import (
 // Mock is a mockable version of the microservice, allowing functions, event sinks and web handlers to be mocked.
 type Mock struct {
 	*Intermediate
-	mockPing          func(ctx context.Context) (pong int, err error)                                                  // MARKER: Ping
-	mockConfigRefresh func(ctx context.Context) (err error)                                                            // MARKER: ConfigRefresh
-	mockTrace         func(ctx context.Context, id string) (err error)                                                 // MARKER: Trace
-	mockOpenAPI       func(ctx context.Context) (httpResponseBody *controlapi.Document, httpStatusCode int, err error) // MARKER: OpenAPI
-	mockMetrics       func(w http.ResponseWriter, r *http.Request) (err error)                                         // MARKER: Metrics
+	mockPing          func(ctx context.Context) (pong int, err error)                                                                        // MARKER: Ping
+	mockConfigRefresh func(ctx context.Context) (err error)                                                                                  // MARKER: ConfigRefresh
+	mockTrace         func(ctx context.Context, id string) (err error)                                                                       // MARKER: Trace
+	mockOpenAPI       func(ctx context.Context) (httpResponseBody *controlapi.Document, httpStatusCode int, err error)                       // MARKER: OpenAPI
+	mockLeader        func(ctx context.Context) (leader string, err error)                                                                   // MARKER: Leader
+	mockInjectFaults  func(ctx context.Context, rules string) (err error)                                                                    // MARKER: InjectFaults
+	mockLogLevel      func(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error) // MARKER: LogLevel
+	mockHealth        func(ctx context.Context) (healthy bool, checks map[string]controlapi.HealthCheck, err error)                          // MARKER: Health
+	mockMetrics       func(w http.ResponseWriter, r *http.Request) (err error)                                                               // MARKER: Metrics
+	mockPprof         func(w http.ResponseWriter, r *http.Request) (err error)                                                               // MARKER: Pprof
 }
 
 // NewMock creates a new mockable version of the microservice.
@@ -114,6 +120,62 @@ func (svc *Mock) OpenAPI(ctx context.Context) (httpResponseBody *controlapi.Docu
 	return httpResponseBody, httpStatusCode, errors.Trace(err)
 }
 
+// MockLeader sets up a mock handler for Leader.
+func (svc *Mock) MockLeader(handler func(ctx context.Context) (leader string, err error)) *Mock { // MARKER: Leader
+	svc.mockLeader = handler
+	return svc
+}
+
+// Leader executes the mock handler.
+func (svc *Mock) Leader(ctx context.Context) (leader string, err error) { // MARKER: Leader
+	if svc.mockLeader != nil {
+		leader, err = svc.mockLeader(ctx)
+	}
+	return leader, errors.Trace(err)
+}
+
+// MockInjectFaults sets up a mock handler for InjectFaults.
+func (svc *Mock) MockInjectFaults(handler func(ctx context.Context, rules string) (err error)) *Mock { // MARKER: InjectFaults
+	svc.mockInjectFaults = handler
+	return svc
+}
+
+// InjectFaults executes the mock handler.
+func (svc *Mock) InjectFaults(ctx context.Context, rules string) (err error) { // MARKER: InjectFaults
+	if svc.mockInjectFaults != nil {
+		err = svc.mockInjectFaults(ctx, rules)
+	}
+	return errors.Trace(err)
+}
+
+// MockLogLevel sets up a mock handler for LogLevel.
+func (svc *Mock) MockLogLevel(handler func(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error)) *Mock { // MARKER: LogLevel
+	svc.mockLogLevel = handler
+	return svc
+}
+
+// LogLevel executes the mock handler.
+func (svc *Mock) LogLevel(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error) { // MARKER: LogLevel
+	if svc.mockLogLevel != nil {
+		err = svc.mockLogLevel(ctx, level, duration, traceID, baggage)
+	}
+	return errors.Trace(err)
+}
+
+// MockHealth sets up a mock handler for Health.
+func (svc *Mock) MockHealth(handler func(ctx context.Context) (healthy bool, checks map[string]controlapi.HealthCheck, err error)) *Mock { // MARKER: Health
+	svc.mockHealth = handler
+	return svc
+}
+
+// Health executes the mock handler.
+func (svc *Mock) Health(ctx context.Context) (healthy bool, checks map[string]controlapi.HealthCheck, err error) { // MARKER: Health
+	if svc.mockHealth != nil {
+		healthy, checks, err = svc.mockHealth(ctx)
+	}
+	return healthy, checks, errors.Trace(err)
+}
+
 // MockMetrics sets up a mock handler for Metrics.
 func (svc *Mock) MockMetrics(handler func(w http.ResponseWriter, r *http.Request) (err error)) *Mock { // MARKER: Metrics
 	svc.mockMetrics = handler
@@ -127,3 +189,17 @@ func (svc *Mock) Metrics(w http.ResponseWriter, r *http.Request) (err error) { /
 	}
 	return errors.Trace(err)
 }
+
+// MockPprof sets up a mock handler for Pprof.
+func (svc *Mock) MockPprof(handler func(w http.ResponseWriter, r *http.Request) (err error)) *Mock { // MARKER: Pprof
+	svc.mockPprof = handler
+	return svc
+}
+
+// Pprof executes the mock handler.
+func (svc *Mock) Pprof(w http.ResponseWriter, r *http.Request) (err error) { // MARKER: Pprof
+	if svc.mockPprof != nil {
+		err = svc.mockPprof(w, r)
+	}
+	return errors.Trace(err)
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/microbus-io/fabric/connector"
 	"github.com/microbus-io/fabric/coreservices/control/controlapi"
@@ -89,6 +90,51 @@ func TestControl_Mock(t *testing.T) {
 		assert.NoError(err)
 	})
 
+	t.Run("leader", func(t *testing.T) { // MARKER: Leader
+		assert := testarossa.For(t)
+
+		mock.MockLeader(func(ctx context.Context) (leader string, err error) {
+			return
+		})
+		_, err := mock.Leader(ctx)
+		assert.NoError(err)
+	})
+
+	t.Run("inject_faults", func(t *testing.T) { // MARKER: InjectFaults
+		assert := testarossa.For(t)
+
+		mock.MockInjectFaults(func(ctx context.Context, rules string) (err error) {
+			return
+		})
+		var rules string
+		err := mock.InjectFaults(ctx, rules)
+		assert.NoError(err)
+	})
+
+	t.Run("log_level", func(t *testing.T) { // MARKER: LogLevel
+		assert := testarossa.For(t)
+
+		mock.MockLogLevel(func(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error) {
+			return
+		})
+		var level string
+		var duration time.Duration
+		var traceID string
+		var baggage map[string]string
+		err := mock.LogLevel(ctx, level, duration, traceID, baggage)
+		assert.NoError(err)
+	})
+
+	t.Run("health", func(t *testing.T) { // MARKER: Health
+		assert := testarossa.For(t)
+
+		mock.MockHealth(func(ctx context.Context) (healthy bool, checks map[string]controlapi.HealthCheck, err error) {
+			return
+		})
+		_, _, err := mock.Health(ctx)
+		assert.NoError(err)
+	})
+
 	t.Run("metrics", func(t *testing.T) { // MARKER: Metrics
 		assert := testarossa.For(t)
 
@@ -101,4 +147,16 @@ func TestControl_Mock(t *testing.T) {
 		assert.NoError(err)
 	})
 
+	t.Run("pprof", func(t *testing.T) { // MARKER: Pprof
+		assert := testarossa.For(t)
+
+		mock.MockPprof(func(w http.ResponseWriter, r *http.Request) (err error) {
+			return nil
+		})
+		w := httpx.NewResponseRecorder()
+		r := httpx.MustNewRequest("GET", "/", nil)
+		err := mock.Pprof(w, r)
+		assert.NoError(err)
+	})
+
 }
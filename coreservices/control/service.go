@@ -19,6 +19,7 @@ package control
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/microbus-io/errors"
 
@@ -81,3 +82,47 @@ OpenAPI returns the OpenAPI 3.1 document of the microservice. Returns endpoints
 func (svc *Service) OpenAPI(ctx context.Context) (httpResponseBody *controlapi.Document, httpStatusCode int, err error) { // MARKER: OpenAPI
 	return nil, 0, nil
 }
+
+/*
+Leader returns the ID of the instance elected leader among the instances of the microservice. Only microservices that run singleton tickers elect a leader.
+*/
+func (svc *Service) Leader(ctx context.Context) (leader string, err error) { // MARKER: Leader
+	return "", nil
+}
+
+/*
+InjectFaults replaces the fault injection rules of the microservice. Fault injection is not allowed in the PROD deployment.
+The actor of the request must satisfy the claims set in the MICROBUS_FAULT_INJECTION_CLAIMS env var, roles.admin by default.
+*/
+func (svc *Service) InjectFaults(ctx context.Context, rules string) (err error) { // MARKER: InjectFaults
+	return nil
+}
+
+/*
+LogLevel changes the minimum level of the messages logged by the microservice to DEBUG, INFO, WARN or ERROR.
+The change reverts automatically after the duration, which defaults to 15 minutes.
+If a trace ID or baggage are specified, the change applies only to messages logged in the context of
+requests that carry that trace ID and all of those baggage values.
+An empty level reverts the change immediately.
+The actor of the request must satisfy the claims set in the MICROBUS_LOG_LEVEL_CLAIMS env var, roles.admin by default.
+*/
+func (svc *Service) LogLevel(ctx context.Context, level string, duration time.Duration, traceID string, baggage map[string]string) (err error) { // MARKER: LogLevel
+	return nil
+}
+
+/*
+Health reports the outcome of the health checks of the microservice, including those set with SetOnHealthCheck.
+*/
+func (svc *Service) Health(ctx context.Context) (healthy bool, checks map[string]controlapi.HealthCheck, err error) { // MARKER: Health
+	return false, nil, nil
+}
+
+/*
+Pprof returns a runtime profile of the process of the microservice in the format of go tool pprof.
+The profile is one of heap, goroutine, allocs, block, mutex, threadcreate, or profile for a CPU profile
+or trace for an execution trace that are collected over the number of seconds in the query argument.
+The actor of the request must satisfy the claims set in the MICROBUS_PPROF_CLAIMS env var, roles.admin by default.
+*/
+func (svc *Service) Pprof(w http.ResponseWriter, r *http.Request) (err error) { // MARKER: Pprof
+	return nil
+}
@@ -50,3 +50,13 @@ var (
 // MARKER: Trace
 
 // MARKER: Metrics
+
+// MARKER: Leader
+
+// MARKER: InjectFaults
+
+// MARKER: LogLevel
+
+// MARKER: Health
+
+// MARKER: Pprof
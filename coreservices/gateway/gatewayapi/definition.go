@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gatewayapi
+
+import (
+	"github.com/microbus-io/fabric/define"
+)
+
+// HINT: This file is the single source of truth for the microservice's API. After editing it, run
+// cmd/genservice on the microservice's directory (the parent of this api package) to regenerate client.go,
+// intermediate.go, mock.go, mock_test.go, and manifest.yaml. Do not hand-edit those generated files.
+
+// Hostname is the default hostname of the microservice.
+const Hostname = "gateway.core"
+
+// Name is the decorative PascalCase name of the microservice.
+const Name = "Gateway"
+
+// Version is a generation counter bumped on each regeneration, not a semantic version.
+const Version = 1
+
+// Description is the human-readable summary of the microservice, surfaced in OpenAPI and discovery.
+const Description = `The gateway microservice bridges an allowlist of routes between two planes or two NATS clusters.`
+
+// RemotePlane is the plane of the remote side of the gateway. If empty, the plane of the local side is used,
+// in which case RemoteNATS must be set.
+var RemotePlane = define.Config{ // MARKER: RemotePlane
+	Value:      string(""),
+	Validation: "str ^[0-9a-zA-Z]*$",
+}
+
+// RemoteNATS is the URL of the NATS cluster of the remote side of the gateway. If empty, the NATS cluster
+// of the local side is used, in which case RemotePlane must be set.
+var RemoteNATS = define.Config{ // MARKER: RemoteNATS
+	Value:  string(""),
+	Secret: true,
+}
+
+/*
+Outbound is the allowlist of routes of the remote side that are made available to the local side.
+Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.
+*/
+var Outbound = define.Config{ // MARKER: Outbound
+	Value:    string(""),
+	Callback: true,
+}
+
+/*
+Inbound is the allowlist of routes of the local side that are made available to the remote side.
+Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.
+*/
+var Inbound = define.Config{ // MARKER: Inbound
+	Value:    string(""),
+	Callback: true,
+}
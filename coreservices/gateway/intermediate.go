@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by cmd/genservice. DO NOT EDIT.
+
+package gateway
+
+import (
+	"context"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/cfg"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/coreservices/gateway/gatewayapi"
+	"github.com/microbus-io/fabric/coreservices/gateway/resources"
+)
+
+const (
+	Hostname    = gatewayapi.Hostname
+	Version     = gatewayapi.Version
+	Description = gatewayapi.Description
+)
+
+// ToDo is implemented by the service or mock.
+// The intermediate delegates handling to this interface.
+type ToDo interface {
+	OnStartup(ctx context.Context) (err error)
+	OnShutdown(ctx context.Context) (err error)
+	OnChangedOutbound(ctx context.Context) (err error) // MARKER: Outbound
+	OnChangedInbound(ctx context.Context) (err error)  // MARKER: Inbound
+}
+
+// NewService creates a new instance of the microservice.
+func NewService() *Service {
+	svc := &Service{}
+	svc.Intermediate = NewIntermediate(svc)
+	return svc
+}
+
+// Init enables a single-statement pattern for initializing the microservice.
+func (svc *Service) Init(initializer func(svc *Service) (err error)) *Service {
+	svc.Connector.Init(func(_ *connector.Connector) (err error) {
+		return initializer(svc)
+	})
+	return svc
+}
+
+// Intermediate extends and customizes the generic base connector.
+type Intermediate struct {
+	*connector.Connector
+	ToDo
+}
+
+// NewIntermediate creates a new instance of the intermediate.
+func NewIntermediate(impl ToDo) *Intermediate {
+	svc := &Intermediate{
+		Connector: connector.New(Hostname),
+		ToDo:      impl,
+	}
+	svc.SetVersion(Version)
+	svc.SetDescription(Description)
+	svc.SetOnStartup(svc.OnStartup)
+	svc.SetOnShutdown(svc.OnShutdown)
+	svc.SetResFS(resources.FS)
+	svc.SetOnObserveMetrics(svc.doOnObserveMetrics)
+	svc.SetOnConfigChanged(svc.doOnConfigChanged)
+
+	svc.DefineConfig( // MARKER: RemotePlane
+		"RemotePlane",
+		cfg.Description(`RemotePlane is the plane of the remote side of the gateway. If empty, the plane of the local side is used,
+in which case RemoteNATS must be set.`),
+		cfg.Validation(`str ^[0-9a-zA-Z]*$`),
+	)
+	svc.DefineConfig( // MARKER: RemoteNATS
+		"RemoteNATS",
+		cfg.Description(`RemoteNATS is the URL of the NATS cluster of the remote side of the gateway. If empty, the NATS cluster
+of the local side is used, in which case RemotePlane must be set.`),
+		cfg.Secret(),
+	)
+	svc.DefineConfig( // MARKER: Outbound
+		"Outbound",
+		cfg.Description(`Outbound is the allowlist of routes of the remote side that are made available to the local side.
+Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.`),
+	)
+	svc.DefineConfig( // MARKER: Inbound
+		"Inbound",
+		cfg.Description(`Inbound is the allowlist of routes of the local side that are made available to the remote side.
+Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.`),
+	)
+
+	return svc
+}
+
+// doOnObserveMetrics is called when metrics are produced.
+func (svc *Intermediate) doOnObserveMetrics(ctx context.Context) (err error) {
+	return svc.Parallel()
+}
+
+// doOnConfigChanged is called when the config of the microservice changes.
+func (svc *Intermediate) doOnConfigChanged(ctx context.Context, changed func(string) bool) (err error) {
+	if changed("Outbound") {
+		err = svc.OnChangedOutbound(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if changed("Inbound") {
+		err = svc.OnChangedInbound(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// RemotePlane is the plane of the remote side of the gateway. If empty, the plane of the local side is used,
+// in which case RemoteNATS must be set.
+func (svc *Intermediate) RemotePlane() (value string) { // MARKER: RemotePlane
+	return svc.Config("RemotePlane")
+}
+
+// SetRemotePlane sets the value of the configuration property.
+func (svc *Intermediate) SetRemotePlane(value string) (err error) { // MARKER: RemotePlane
+	return svc.SetConfig("RemotePlane", value)
+}
+
+// RemoteNATS is the URL of the NATS cluster of the remote side of the gateway. If empty, the NATS cluster
+// of the local side is used, in which case RemotePlane must be set.
+func (svc *Intermediate) RemoteNATS() (value string) { // MARKER: RemoteNATS
+	return svc.Config("RemoteNATS")
+}
+
+// SetRemoteNATS sets the value of the configuration property.
+func (svc *Intermediate) SetRemoteNATS(value string) (err error) { // MARKER: RemoteNATS
+	return svc.SetConfig("RemoteNATS", value)
+}
+
+// Outbound is the allowlist of routes of the remote side that are made available to the local side.
+// Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+// The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.
+func (svc *Intermediate) Outbound() (value string) { // MARKER: Outbound
+	return svc.Config("Outbound")
+}
+
+// SetOutbound sets the value of the configuration property.
+func (svc *Intermediate) SetOutbound(value string) (err error) { // MARKER: Outbound
+	return svc.SetConfig("Outbound", value)
+}
+
+// Inbound is the allowlist of routes of the local side that are made available to the remote side.
+// Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+// The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.
+func (svc *Intermediate) Inbound() (value string) { // MARKER: Inbound
+	return svc.Config("Inbound")
+}
+
+// SetInbound sets the value of the configuration property.
+func (svc *Intermediate) SetInbound(value string) (err error) { // MARKER: Inbound
+	return svc.SetConfig("Inbound", value)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by cmd/genservice. DO NOT EDIT.
+
+package gateway
+
+import (
+	"context"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/connector"
+)
+
+// Mock is a mockable version of the microservice, allowing functions, event sinks and web handlers to be mocked.
+type Mock struct {
+	*Intermediate
+	mockOnChangedOutbound func(ctx context.Context) (err error) // MARKER: Outbound
+	mockOnChangedInbound  func(ctx context.Context) (err error) // MARKER: Inbound
+}
+
+// NewMock creates a new mockable version of the microservice.
+func NewMock() *Mock {
+	svc := &Mock{}
+	svc.Intermediate = NewIntermediate(svc)
+	svc.SetVersion(7357) // Stands for TEST
+	return svc
+}
+
+// OnStartup is called when the microservice is started up.
+func (svc *Mock) OnStartup(ctx context.Context) (err error) {
+	if svc.Deployment() != connector.LOCAL && svc.Deployment() != connector.TESTING {
+		return errors.New("mocking disallowed in %s deployment", svc.Deployment())
+	}
+	return nil
+}
+
+// OnShutdown is called when the microservice is shut down.
+func (svc *Mock) OnShutdown(ctx context.Context) (err error) {
+	return nil
+}
+
+// MockOnChangedOutbound sets up a mock handler for OnChangedOutbound.
+func (svc *Mock) MockOnChangedOutbound(handler func(ctx context.Context) (err error)) *Mock { // MARKER: Outbound
+	svc.mockOnChangedOutbound = handler
+	return svc
+}
+
+// OnChangedOutbound executes the mock handler.
+func (svc *Mock) OnChangedOutbound(ctx context.Context) (err error) { // MARKER: Outbound
+	if svc.mockOnChangedOutbound != nil {
+		err = svc.mockOnChangedOutbound(ctx)
+	}
+	return errors.Trace(err)
+}
+
+// MockOnChangedInbound sets up a mock handler for OnChangedInbound.
+func (svc *Mock) MockOnChangedInbound(handler func(ctx context.Context) (err error)) *Mock { // MARKER: Inbound
+	svc.mockOnChangedInbound = handler
+	return svc
+}
+
+// OnChangedInbound executes the mock handler.
+func (svc *Mock) OnChangedInbound(ctx context.Context) (err error) { // MARKER: Inbound
+	if svc.mockOnChangedInbound != nil {
+		err = svc.mockOnChangedInbound(ctx)
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by cmd/genservice. DO NOT EDIT.
+
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/testarossa"
+)
+
+func TestGateway_Mock(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	mock := NewMock()
+	mock.SetDeployment(connector.TESTING)
+
+	t.Run("on_startup", func(t *testing.T) {
+		assert := testarossa.For(t)
+		err := mock.OnStartup(ctx)
+		assert.NoError(err)
+	})
+
+	t.Run("on_shutdown", func(t *testing.T) {
+		assert := testarossa.For(t)
+		err := mock.OnShutdown(ctx)
+		assert.NoError(err)
+	})
+
+	t.Run("on_changed_outbound", func(t *testing.T) { // MARKER: Outbound
+		assert := testarossa.For(t)
+
+		mock.MockOnChangedOutbound(func(ctx context.Context) (err error) {
+			return
+		})
+		err := mock.OnChangedOutbound(ctx)
+		assert.NoError(err)
+	})
+
+	t.Run("on_changed_inbound", func(t *testing.T) { // MARKER: Inbound
+		assert := testarossa.For(t)
+
+		mock.MockOnChangedInbound(func(ctx context.Context) (err error) {
+			return
+		})
+		err := mock.OnChangedInbound(ctx)
+		assert.NoError(err)
+	})
+
+}
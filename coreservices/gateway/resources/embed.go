@@ -0,0 +1,6 @@
+package resources
+
+import "embed"
+
+//go:embed *
+var FS embed.FS
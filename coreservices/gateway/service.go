@@ -0,0 +1,345 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+)
+
+var (
+	_ context.Context
+	_ http.Request
+	_ errors.TracedError
+)
+
+/*
+Service implements the gateway.core microservice.
+
+The gateway microservice bridges an allowlist of routes between two planes or two NATS clusters.
+The local side of the gateway is the microservice itself. The remote side is a connector of the same hostname
+that joins the remote plane or NATS cluster. Requests to an allowlisted route are received by one side and relayed
+by the other, carrying over the actor, baggage, trace context and remaining time budget of the original request.
+*/
+type Service struct {
+	*Intermediate // IMPORTANT: Do not remove
+
+	// HINT: Add member variables here
+	remote        *connector.Connector
+	outboundNames []string
+	inboundNames  []string
+	seq           int
+	mux           sync.Mutex
+}
+
+// OnStartup is called when the microservice is started up.
+func (svc *Service) OnStartup(ctx context.Context) (err error) {
+	remotePlane := svc.RemotePlane()
+	if remotePlane == "" {
+		remotePlane = svc.Plane()
+	}
+	if remotePlane == svc.Plane() && svc.RemoteNATS() == "" {
+		return errors.New("remote side must differ from the local side in its plane or NATS cluster")
+	}
+	outbound, err := parseRoutes(svc.Outbound())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	inbound, err := parseRoutes(svc.Inbound())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = checkLoops(outbound, inbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	remote := connector.New(svc.Hostname())
+	remote.SetDescription(svc.Description())
+	remote.SetVersion(svc.Version())
+	remote.SetDeployment(svc.Deployment())
+	remote.SetPlane(remotePlane)
+	remote.SetNATS(svc.RemoteNATS())
+
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+	svc.remote = remote
+	svc.outboundNames, err = svc.bridge(svc.Connector, remote, "Outbound", outbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc.inboundNames, err = svc.bridge(remote, svc.Connector, "Inbound", inbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = remote.Startup(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc.LogInfo(ctx, "Bridging",
+		"remotePlane", remotePlane,
+		"outbound", len(outbound),
+		"inbound", len(inbound),
+	)
+	return nil
+}
+
+// OnShutdown is called when the microservice is shut down.
+func (svc *Service) OnShutdown(ctx context.Context) (err error) {
+	svc.mux.Lock()
+	remote := svc.remote
+	svc.remote = nil
+	svc.outboundNames = nil
+	svc.inboundNames = nil
+	svc.mux.Unlock()
+	if remote != nil {
+		err = remote.Shutdown(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+/*
+OnChangedOutbound is called when the Outbound config property changes.
+
+Outbound is the allowlist of routes of the remote side that are made available to the local side.
+Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.
+*/
+func (svc *Service) OnChangedOutbound(ctx context.Context) (err error) { // MARKER: Outbound
+	outbound, err := parseRoutes(svc.Outbound())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	inbound, err := parseRoutes(svc.Inbound())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = checkLoops(outbound, inbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+	if svc.remote == nil {
+		return nil
+	}
+	names, err := svc.bridge(svc.Connector, svc.remote, "Outbound", outbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc.unbridge(svc.Connector, svc.outboundNames)
+	svc.outboundNames = names
+	return nil
+}
+
+/*
+OnChangedInbound is called when the Inbound config property changes.
+
+Inbound is the allowlist of routes of the local side that are made available to the remote side.
+Routes are separated by new lines or semicolons, each in the format host[:port][/path] [METHOD].
+The port defaults to 443. A path ending in /* matches by prefix, and a missing path matches all paths.
+*/
+func (svc *Service) OnChangedInbound(ctx context.Context) (err error) { // MARKER: Inbound
+	inbound, err := parseRoutes(svc.Inbound())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	outbound, err := parseRoutes(svc.Outbound())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = checkLoops(outbound, inbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+	if svc.remote == nil {
+		return nil
+	}
+	names, err := svc.bridge(svc.remote, svc.Connector, "Inbound", inbound)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	svc.unbridge(svc.remote, svc.inboundNames)
+	svc.inboundNames = names
+	return nil
+}
+
+// route is an allowlisted route that is bridged by the gateway.
+type route struct {
+	method string
+	url    string
+}
+
+// parseRoutes parses an allowlist of routes separated by new lines or semicolons,
+// each in the format host[:port][/path] [METHOD].
+func parseRoutes(routes string) ([]route, error) {
+	var result []route
+	lines := strings.FieldsFunc(routes, func(r rune) bool {
+		return r == '\n' || r == ';'
+	})
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, errors.New("invalid route '%s'", strings.TrimSpace(line))
+		}
+		method := "ANY"
+		if len(fields) == 2 {
+			method = strings.ToUpper(fields[1])
+		}
+		hostPort, path, hasPath := strings.Cut(fields[0], "/")
+		host, port, hasPort := strings.Cut(hostPort, ":")
+		host = strings.ToLower(host)
+		err := httpx.ValidateHostname(host)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !hasPort {
+			port = "443"
+		}
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return nil, errors.New("invalid port in route '%s'", fields[0])
+		}
+		if port == "888" {
+			return nil, errors.New("control port 888 cannot be bridged in route '%s'", fields[0])
+		}
+		switch {
+		case !hasPath:
+			path = "/{path...}"
+		case strings.HasSuffix(path, "*"):
+			path = "/" + strings.TrimSuffix(path, "*") + "{path...}"
+		default:
+			path = "/" + path
+		}
+		result = append(result, route{
+			method: method,
+			url:    "https://" + host + ":" + port + path,
+		})
+	}
+	return result, nil
+}
+
+// checkLoops returns an error if any outbound route overlaps an inbound route.
+// A request matching both would be relayed back and forth between the local and remote sides.
+func checkLoops(outbound []route, inbound []route) error {
+	for _, o := range outbound {
+		for _, i := range inbound {
+			if o.overlaps(i) {
+				return errors.New("route '%s %s' overlaps both outbound and inbound route '%s %s'", o.method, o.url, i.method, i.url)
+			}
+		}
+	}
+	return nil
+}
+
+// overlaps indicates if a request may match both routes.
+func (r route) overlaps(other route) bool {
+	if r.method != "ANY" && other.method != "ANY" && r.method != other.method {
+		return false
+	}
+	prefix, isPrefix := strings.CutSuffix(r.url, "{path...}")
+	otherPrefix, otherIsPrefix := strings.CutSuffix(other.url, "{path...}")
+	switch {
+	case isPrefix && otherIsPrefix:
+		return strings.HasPrefix(prefix, otherPrefix) || strings.HasPrefix(otherPrefix, prefix)
+	case isPrefix:
+		return strings.HasPrefix(other.url, prefix)
+	case otherIsPrefix:
+		return strings.HasPrefix(r.url, otherPrefix)
+	default:
+		return r.url == other.url
+	}
+}
+
+// bridge subscribes the connector to the routes, relaying the requests it receives via the other connector.
+// It returns the names of the new subscriptions.
+func (svc *Service) bridge(from *connector.Connector, to *connector.Connector, prefix string, routes []route) (names []string, err error) {
+	for _, r := range routes {
+		svc.seq++
+		name := prefix + strconv.Itoa(svc.seq)
+		err = from.Subscribe(name, relay(to),
+			sub.At(r.method, r.url),
+			sub.Description("Bridges "+r.method+" "+r.url),
+			sub.Web(),
+		)
+		if err != nil {
+			svc.unbridge(from, names)
+			return nil, errors.Trace(err)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// unbridge unsubscribes the connector from the named subscriptions.
+func (svc *Service) unbridge(from *connector.Connector, names []string) {
+	for _, name := range names {
+		from.Unsubscribe(name)
+	}
+}
+
+// relay returns a handler that relays requests via the connector and writes back the response.
+// The actor, baggage, trace context and time budget are carried over by the context of the request.
+func relay(to *connector.Connector) sub.HTTPHandler {
+	return func(w http.ResponseWriter, r *http.Request) (err error) {
+		header := http.Header{}
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, frame.HeaderPrefix) || name == "Traceparent" || name == "Tracestate" {
+				continue
+			}
+			header[name] = values
+		}
+		options := []pub.Option{
+			pub.Method(r.Method),
+			pub.URL(r.URL.String()),
+			pub.CopyHeaders(header),
+		}
+		if r.Body != nil && r.Body != http.NoBody {
+			options = append(options, pub.Body(r.Body))
+		}
+		res, err := to.Request(r.Context(), options...)
+		if err != nil {
+			return err // No trace
+		}
+		for name, values := range res.Header {
+			if !strings.HasPrefix(name, frame.HeaderPrefix) {
+				w.Header()[name] = values
+			}
+		}
+		w.WriteHeader(res.StatusCode)
+		_, err = io.Copy(w, res.Body)
+		return errors.Trace(err)
+	}
+}
@@ -0,0 +1,309 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/application"
+	"github.com/microbus-io/fabric/connector"
+	"github.com/microbus-io/fabric/frame"
+	"github.com/microbus-io/fabric/pub"
+	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/utils"
+	"github.com/microbus-io/testarossa"
+)
+
+var (
+	_ context.Context
+	_ *testing.T
+	_ application.Application
+	_ connector.Connector
+	_ frame.Frame
+	_ pub.Option
+	_ testarossa.Asserter
+)
+
+// newEcho creates a microservice that echoes back what it received over the gateway.
+func newEcho(hostname string) *connector.Connector {
+	echo := connector.New(hostname)
+	echo.Subscribe("Echo",
+		func(w http.ResponseWriter, r *http.Request) error {
+			var actor struct {
+				Sub string `json:"sub"`
+			}
+			frame.Of(r).ParseActor(&actor)
+			body, _ := io.ReadAll(r.Body)
+			budget := frame.Of(r).TimeBudget()
+			w.Header().Set("X-Actor", actor.Sub)
+			w.Header().Set("X-Baggage", frame.Of(r).Baggage("Region"))
+			w.Header().Set("X-Budget", strconv.FormatBool(budget > 0 && budget <= 5*time.Second))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+			return nil
+		},
+		sub.At("ANY", ":443/echo/{path...}"),
+		sub.Web(),
+	)
+	echo.Subscribe("Teapot",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return errors.New("short and stout", http.StatusTeapot)
+		},
+		sub.At("GET", ":443/teapot"),
+		sub.Web(),
+	)
+	return echo
+}
+
+// callEcho makes a request to the echo microservice, returning the body and headers of the response.
+func callEcho(ctx context.Context, caller *connector.Connector, method string, url string) (string, http.Header, error) {
+	res, err := caller.Request(ctx,
+		pub.Method(method),
+		pub.URL(url),
+		pub.Body("hello"),
+		pub.Actor(map[string]any{"sub": "harry"}),
+		pub.Baggage("Region", "east"),
+		pub.Timeout(5*time.Second),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	if res.StatusCode != http.StatusCreated {
+		return "", nil, errors.New("unexpected status code %d", res.StatusCode)
+	}
+	body, _ := io.ReadAll(res.Body)
+	return string(body), res.Header, nil
+}
+
+func TestGateway_OnChangedOutbound(t *testing.T) { // MARKER: Outbound
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	// Run the backend in the remote plane
+	remotePlane := utils.RandomIdentifier(12)
+	backend := newEcho("backend.outbound.gateway.core")
+	backend.SetPlane(remotePlane)
+	err := backend.Startup(ctx)
+	assert.NoError(err)
+	defer backend.Shutdown(ctx)
+
+	// Initialize the microservice under test
+	svc := NewService()
+	svc.SetRemotePlane(remotePlane)
+	svc.SetOutbound("backend.outbound.gateway.core/echo/* POST; backend.outbound.gateway.core/teapot")
+
+	// Initialize the testers
+	tester := connector.New("tester.client")
+
+	// Run the testing app
+	app := application.New()
+	app.Add(
+		svc,
+		tester,
+	)
+	app.RunInTest(t)
+
+	t.Run("relay_request", func(t *testing.T) {
+		assert := testarossa.For(t)
+		body, header, err := callEcho(ctx, tester, "POST", "https://backend.outbound.gateway.core/echo/x/y")
+		if assert.NoError(err) {
+			assert.Expect(
+				body, "POST /echo/x/y hello",
+				header.Get("X-Actor"), "harry",
+				header.Get("X-Baggage"), "east",
+				header.Get("X-Budget"), "true",
+			)
+		}
+	})
+
+	t.Run("relay_error", func(t *testing.T) {
+		assert := testarossa.For(t)
+		_, err := tester.Request(ctx, pub.GET("https://backend.outbound.gateway.core/teapot"))
+		if assert.Error(err) {
+			assert.Equal(http.StatusTeapot, errors.StatusCode(err))
+			assert.Contains(err.Error(), "short and stout")
+		}
+	})
+
+	t.Run("not_allowlisted", func(t *testing.T) {
+		assert := testarossa.For(t)
+		_, _, err := callEcho(ctx, tester, "PUT", "https://backend.outbound.gateway.core/echo/x/y")
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+		_, _, err = callEcho(ctx, tester, "POST", "https://backend.outbound.gateway.core:444/echo/x/y")
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	})
+
+	t.Run("change_allowlist", func(t *testing.T) {
+		assert := testarossa.For(t)
+		err := svc.SetOutbound("backend.outbound.gateway.core/echo/x/y PUT")
+		assert.NoError(err)
+		body, _, err := callEcho(ctx, tester, "PUT", "https://backend.outbound.gateway.core/echo/x/y")
+		assert.Expect(body, "PUT /echo/x/y hello", err, nil)
+		_, _, err = callEcho(ctx, tester, "POST", "https://backend.outbound.gateway.core/echo/x/y")
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+		_, err = tester.Request(ctx, pub.GET("https://backend.outbound.gateway.core/teapot"))
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+
+		err = svc.SetOutbound("backend.outbound.gateway.core:888/ping")
+		assert.Error(err)
+	})
+}
+
+func TestGateway_OnChangedInbound(t *testing.T) { // MARKER: Inbound
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	// Initialize the microservice under test
+	remotePlane := utils.RandomIdentifier(12)
+	svc := NewService()
+	svc.SetRemotePlane(remotePlane)
+
+	// Initialize the testers
+	frontend := newEcho("frontend.inbound.gateway.core")
+
+	// Run the testing app
+	app := application.New()
+	app.Add(
+		svc,
+		frontend,
+	)
+	app.RunInTest(t)
+
+	// Call from the remote plane
+	remoteTester := connector.New("tester.client")
+	remoteTester.SetPlane(remotePlane)
+	err := remoteTester.Startup(ctx)
+	assert.NoError(err)
+	defer remoteTester.Shutdown(ctx)
+
+	t.Run("not_allowlisted", func(t *testing.T) {
+		assert := testarossa.For(t)
+		_, _, err := callEcho(ctx, remoteTester, "GET", "https://frontend.inbound.gateway.core/echo/abc")
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	})
+
+	t.Run("change_allowlist", func(t *testing.T) {
+		assert := testarossa.For(t)
+		err := svc.SetInbound("frontend.inbound.gateway.core")
+		assert.NoError(err)
+		body, header, err := callEcho(ctx, remoteTester, "GET", "https://frontend.inbound.gateway.core/echo/abc")
+		if assert.NoError(err) {
+			assert.Expect(
+				body, "GET /echo/abc hello",
+				header.Get("X-Actor"), "harry",
+				header.Get("X-Baggage"), "east",
+			)
+		}
+
+		err = svc.SetInbound("")
+		assert.NoError(err)
+		_, _, err = callEcho(ctx, remoteTester, "GET", "https://frontend.inbound.gateway.core/echo/abc")
+		assert.Equal(http.StatusNotFound, errors.StatusCode(err))
+	})
+}
+
+func TestGateway_SameSide(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	svc := NewService()
+	svc.SetDeployment(connector.TESTING)
+	svc.SetPlane(utils.RandomIdentifier(12))
+	err := svc.Startup(ctx)
+	if !assert.Error(err) {
+		svc.Shutdown(ctx)
+	}
+}
+
+func TestGateway_ParseRoutes(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	routes, err := parseRoutes(" Payments.Example:444/charge post ;\ncatalog.example/api/*\n\nusers.example ")
+	if assert.NoError(err) && assert.Len(routes, 3) {
+		assert.Equal(route{method: "POST", url: "https://payments.example:444/charge"}, routes[0])
+		assert.Equal(route{method: "ANY", url: "https://catalog.example:443/api/{path...}"}, routes[1])
+		assert.Equal(route{method: "ANY", url: "https://users.example:443/{path...}"}, routes[2])
+	}
+
+	routes, err = parseRoutes("")
+	assert.NoError(err)
+	assert.Len(routes, 0)
+
+	for _, bad := range []string{
+		"payments.example:888/ping",
+		"payments.example:0/charge",
+		"payments.example:x/charge",
+		"payments.example/charge GET POST",
+		"bad_host.example",
+		"*.example",
+	} {
+		_, err = parseRoutes(bad)
+		assert.Error(err, "%s", bad)
+	}
+}
+
+func TestGateway_Loops(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	for _, tc := range []struct {
+		outbound string
+		inbound  string
+		loop     bool
+	}{
+		{"payments.example", "payments.example", true},
+		{"payments.example/charge POST", "payments.example/* POST", true},
+		{"payments.example/charge POST", "payments.example/charge", true},
+		{"payments.example/api/*", "payments.example/api/v1/*", true},
+		{"payments.example/charge POST", "payments.example/charge GET", false},
+		{"payments.example/charge", "payments.example/refund", false},
+		{"payments.example:444", "payments.example", false},
+		{"payments.example/api/*", "users.example/api/*", false},
+		{"payments.example/charge", "", false},
+	} {
+		outbound, err := parseRoutes(tc.outbound)
+		assert.NoError(err)
+		inbound, err := parseRoutes(tc.inbound)
+		assert.NoError(err)
+		err = checkLoops(outbound, inbound)
+		assert.Equal(tc.loop, err != nil, "%s | %s", tc.outbound, tc.inbound)
+	}
+
+	// The microservice refuses to start with overlapping routes
+	ctx := t.Context()
+	svc := NewService()
+	svc.SetDeployment(connector.TESTING)
+	svc.SetRemotePlane(utils.RandomIdentifier(12))
+	svc.SetOutbound("payments.example")
+	svc.SetInbound("payments.example/charge")
+	err := svc.Startup(ctx)
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "overlaps")
+	} else {
+		svc.Shutdown(ctx)
+	}
+}
@@ -177,3 +177,13 @@ var BlockedPaths = define.Config{ // MARKER: BlockedPaths
 *.exe`,
 	Callback: true,
 }
+
+// ProbePort is the plaintext HTTP port on which to serve the /healthz and /readyz probes, such as those of the
+// Kubernetes liveness and readiness probes. The probes are not served on the public ports listed in Ports, so this
+// port should be reachable only from inside the cluster. The probes are disabled if the port is 0.
+var ProbePort = define.Config{ // MARKER: ProbePort
+	Value:      int(0),
+	Default:    "0",
+	Validation: "int [0,65535]",
+	Callback:   true,
+}
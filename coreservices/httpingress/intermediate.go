@@ -49,6 +49,7 @@ type ToDo interface {
 	OnChangedWriteTimeout(ctx context.Context) (err error)         // MARKER: WriteTimeout
 	OnChangedReadHeaderTimeout(ctx context.Context) (err error)    // MARKER: ReadHeaderTimeout
 	OnChangedBlockedPaths(ctx context.Context) (err error)         // MARKER: BlockedPaths
+	OnChangedProbePort(ctx context.Context) (err error)            // MARKER: ProbePort
 }
 
 // NewService creates a new instance of the microservice.
@@ -209,6 +210,14 @@ Extensions are specified with "*.ext" and are matched against the extension of t
 *.esp
 *.exe`),
 	)
+	svc.DefineConfig( // MARKER: ProbePort
+		"ProbePort",
+		cfg.Description(`ProbePort is the plaintext HTTP port on which to serve the /healthz and /readyz probes, such as those of the
+Kubernetes liveness and readiness probes. The probes are not served on the public ports listed in Ports, so this
+port should be reachable only from inside the cluster. The probes are disabled if the port is 0.`),
+		cfg.DefaultValue(`0`),
+		cfg.Validation(`int [0,65535]`),
+	)
 
 	return svc
 }
@@ -268,6 +277,12 @@ func (svc *Intermediate) doOnConfigChanged(ctx context.Context, changed func(str
 			return errors.Trace(err)
 		}
 	}
+	if changed("ProbePort") {
+		err = svc.OnChangedProbePort(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
@@ -395,3 +410,17 @@ func (svc *Intermediate) BlockedPaths() (value string) { // MARKER: BlockedPaths
 func (svc *Intermediate) SetBlockedPaths(value string) (err error) { // MARKER: BlockedPaths
 	return svc.SetConfig("BlockedPaths", value)
 }
+
+// ProbePort is the plaintext HTTP port on which to serve the /healthz and /readyz probes, such as those of the
+// Kubernetes liveness and readiness probes. The probes are not served on the public ports listed in Ports, so this
+// port should be reachable only from inside the cluster. The probes are disabled if the port is 0.
+func (svc *Intermediate) ProbePort() (value int) { // MARKER: ProbePort
+	_val := svc.Config("ProbePort")
+	_i, _ := strconv.ParseInt(_val, 10, 64)
+	return int(_i)
+}
+
+// SetProbePort sets the value of the configuration property.
+func (svc *Intermediate) SetProbePort(value int) (err error) { // MARKER: ProbePort
+	return svc.SetConfig("ProbePort", strconv.Itoa(value))
+}
@@ -62,6 +62,10 @@ func Logger(logger service.Logger) Middleware {
 					logFunc = logger.LogWarn
 					maxLen = 512
 				}
+				if tracer, ok := logger.(service.Tracer); ok {
+					// Select the trace before logging so that log sampling does not suppress the error
+					tracer.ForceTrace(r.Context())
+				}
 				logFunc(r.Context(), "Serving",
 					"error", err,
 					"path", pathAndQuery(r, maxLen),
@@ -0,0 +1,56 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/connector"
+)
+
+/*
+Probes returns a middleware that responds to requests to /healthz and /readyz, such as those of the Kubernetes
+liveness and readiness probes, with the JSON report of the corresponding check. A check that returns an error
+fails the probe with a 503 status code. Other requests pass through to the next handler.
+*/
+func Probes(healthz func(ctx context.Context) (report any, err error), readyz func(ctx context.Context) (report any, err error)) Middleware {
+	return func(next connector.HTTPHandler) connector.HTTPHandler {
+		return func(w http.ResponseWriter, r *http.Request) (err error) {
+			var check func(ctx context.Context) (report any, err error)
+			switch r.URL.Path {
+			case "/healthz":
+				check = healthz
+			case "/readyz":
+				check = readyz
+			}
+			if check == nil || (r.Method != "GET" && r.Method != "HEAD") {
+				return next(w, r) // No trace
+			}
+			report, checkErr := check(r.Context())
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Cache-Control", "no-store")
+			if checkErr != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			err = json.NewEncoder(w).Encode(report)
+			return errors.Trace(err)
+		}
+	}
+}
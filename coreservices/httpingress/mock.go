@@ -36,6 +36,7 @@ type Mock struct {
 	mockOnChangedWriteTimeout         func(ctx context.Context) (err error) // MARKER: WriteTimeout
 	mockOnChangedReadHeaderTimeout    func(ctx context.Context) (err error) // MARKER: ReadHeaderTimeout
 	mockOnChangedBlockedPaths         func(ctx context.Context) (err error) // MARKER: BlockedPaths
+	mockOnChangedProbePort            func(ctx context.Context) (err error) // MARKER: ProbePort
 }
 
 // NewMock creates a new mockable version of the microservice.
@@ -170,3 +171,17 @@ func (svc *Mock) OnChangedBlockedPaths(ctx context.Context) (err error) { // MAR
 	}
 	return errors.Trace(err)
 }
+
+// MockOnChangedProbePort sets up a mock handler for OnChangedProbePort.
+func (svc *Mock) MockOnChangedProbePort(handler func(ctx context.Context) (err error)) *Mock { // MARKER: ProbePort
+	svc.mockOnChangedProbePort = handler
+	return svc
+}
+
+// OnChangedProbePort executes the mock handler.
+func (svc *Mock) OnChangedProbePort(ctx context.Context) (err error) { // MARKER: ProbePort
+	if svc.mockOnChangedProbePort != nil {
+		err = svc.mockOnChangedProbePort(ctx)
+	}
+	return errors.Trace(err)
+}
@@ -125,4 +125,14 @@ func TestHttpingress_Mock(t *testing.T) {
 		assert.NoError(err)
 	})
 
+	t.Run("on_changed_probe_port", func(t *testing.T) { // MARKER: ProbePort
+		assert := testarossa.For(t)
+
+		mock.MockOnChangedProbePort(func(ctx context.Context) (err error) {
+			return
+		})
+		err := mock.OnChangedProbePort(ctx)
+		assert.NoError(err)
+	})
+
 }
@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpingress
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/coreservices/httpingress/middleware"
+)
+
+// probeReport is the response to the /healthz and /readyz probes.
+type probeReport struct {
+	Healthy bool `json:"healthy"`
+}
+
+// serveProbes serves the /healthz and /readyz probes on the probe port.
+// Any other request is rejected with a 404.
+func (svc *Service) serveProbes(w http.ResponseWriter, r *http.Request) {
+	handler := middleware.Probes(svc.livenessProbe, svc.readinessProbe)(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("", http.StatusNotFound)
+	})
+	err := handler(w, r)
+	if err != nil {
+		statusCode := errors.StatusCode(err)
+		http.Error(w, http.StatusText(statusCode), statusCode)
+	}
+}
+
+// livenessProbe responds to /healthz with the liveness of the ingress itself.
+// The health of other microservices does not affect it, so that a failing downstream dependency does not cause the ingress to be restarted.
+func (svc *Service) livenessProbe(ctx context.Context) (report any, err error) {
+	if !svc.IsStarted() {
+		return &probeReport{Healthy: false}, errors.New("unhealthy", http.StatusServiceUnavailable)
+	}
+	return &probeReport{Healthy: true}, nil
+}
+
+// readinessProbe responds to /readyz with the readiness of the ingress itself to accept traffic.
+// The probe fails until the ingress completes startup and its public HTTP listeners are up.
+// Other microservices do not affect it, so that their rolling deployments do not take the ingress out of rotation.
+func (svc *Service) readinessProbe(ctx context.Context) (report any, err error) {
+	svc.mux.Lock()
+	listening := false
+	for port := range svc.httpServers {
+		if port != svc.ProbePort() {
+			listening = true
+		}
+	}
+	svc.mux.Unlock()
+	if !svc.IsStarted() || !listening {
+		return &probeReport{Healthy: false}, errors.New("not ready", http.StatusServiceUnavailable)
+	}
+	return &probeReport{Healthy: true}, nil
+}
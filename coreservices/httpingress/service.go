@@ -256,11 +256,28 @@ func (svc *Service) startHTTPServers(ctx context.Context) (err error) {
 		}
 	}
 
+	// The probes are served on a dedicated plaintext port that is not one of the public ports
+	probePort := svc.ProbePort()
+	if probePort > 0 {
+		for _, s := range specs {
+			if s.port == probePort {
+				err = errors.New("probe port %d cannot be one of the public ports", probePort)
+				svc.LogError(ctx, "Starting HTTP listener", "error", err)
+				return errors.Trace(err)
+			}
+		}
+		specs = append(specs, portSpec{port: probePort})
+	}
+
 	for _, s := range specs {
+		var handler http.Handler = svc
+		if s.port == probePort {
+			handler = http.HandlerFunc(svc.serveProbes)
+		}
 		// https://pkg.go.dev/net/http?utm_source=godoc#Server
 		httpServer := &http.Server{
 			Addr:              ":" + strconv.Itoa(s.port),
-			Handler:           svc,
+			Handler:           handler,
 			ReadHeaderTimeout: svc.ReadHeaderTimeout(),
 			ReadTimeout:       svc.ReadTimeout(),
 			WriteTimeout:      svc.WriteTimeout(),
@@ -810,3 +827,14 @@ func (svc *Service) exchangeToken(ctx context.Context, bearerToken string) (acce
 	}
 	return accessToken, nil
 }
+
+/*
+OnChangedProbePort is called when the ProbePort config property changes.
+
+ProbePort is the plaintext HTTP port on which to serve the /healthz and /readyz probes, such as those of the
+Kubernetes liveness and readiness probes. The probes are not served on the public ports listed in Ports, so this
+port should be reachable only from inside the cluster. The probes are disabled if the port is 0.
+*/
+func (svc *Service) OnChangedProbePort(ctx context.Context) (err error) { // MARKER: ProbePort
+	return svc.restartHTTPServers(ctx)
+}
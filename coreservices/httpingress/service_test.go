@@ -18,12 +18,15 @@ package httpingress
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -124,6 +127,24 @@ func TestHttpingress_Incoming(t *testing.T) {
 			)
 			return nil
 		}),
+		connector.New("streaming").Init(func(c *connector.Connector) (err error) {
+			c.Subscribe("Events",
+				func(w http.ResponseWriter, r *http.Request) error {
+					w.Header().Set("Content-Type", "text/event-stream")
+					for i := range 2 {
+						w.Write([]byte("data: " + strconv.Itoa(i) + "\n\n"))
+						w.(http.Flusher).Flush()
+						if i == 0 {
+							<-done
+						}
+					}
+					return nil
+				},
+				sub.At("GET", "events"),
+				sub.Web(),
+			)
+			return nil
+		}),
 		connector.New("compression").Init(func(c *connector.Connector) (err error) {
 			c.Subscribe("Ok",
 				func(w http.ResponseWriter, r *http.Request) error {
@@ -445,6 +466,31 @@ func TestHttpingress_Incoming(t *testing.T) {
 		}
 	})
 
+	t.Run("streaming", func(t *testing.T) {
+		assert := testarossa.For(t)
+
+		req, err := http.NewRequest("GET", "http://localhost:4040/streaming/events", nil)
+		assert.NoError(err)
+		req.Header.Set("Accept-Encoding", "gzip")
+		res, err := httpClient.Do(req)
+		if assert.NoError(err) {
+			assert.True(strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream"))
+			assert.Equal("", res.Header.Get("Content-Length"))
+
+			// The first event should arrive before the handler returns
+			buf := make([]byte, len("data: 0\n\n"))
+			_, err = io.ReadFull(res.Body, buf)
+			if assert.NoError(err) {
+				assert.Equal("data: 0\n\n", string(buf))
+			}
+			done <- true
+			b, err := io.ReadAll(res.Body)
+			if assert.NoError(err) {
+				assert.Equal("data: 1\n\n", string(b))
+			}
+		}
+	})
+
 	t.Run("internal_ports_firewall", func(t *testing.T) {
 		assert := testarossa.For(t)
 
@@ -887,6 +933,104 @@ func TestHttpingress_Incoming(t *testing.T) {
 	})
 }
 
+func TestHttpingress_Probes(t *testing.T) {
+	// No t.Parallel: starting a web server
+	ctx := t.Context()
+	_ = ctx
+
+	// Initialize the microservice under test
+	svc := NewService()
+	svc.SetPorts("4041")
+	svc.SetProbePort(4042)
+
+	var mux sync.Mutex
+	var downstreamErr error
+	dependent := connector.New("dependent.probes")
+	dependent.SetOnHealthCheck(func(ctx context.Context) map[string]error {
+		mux.Lock()
+		defer mux.Unlock()
+		return map[string]error{
+			"downstream": downstreamErr,
+		}
+	})
+	httpClient := http.Client{Timeout: time.Second * 4}
+
+	// Run the testing app
+	app := application.New()
+	app.Add(
+		svc,
+		dependent,
+	)
+	app.RunInTest(t)
+
+	probe := func(port string, path string) (status int, report probeReport) {
+		res, err := httpClient.Get("http://localhost:" + port + path)
+		if err != nil {
+			return 0, report
+		}
+		defer res.Body.Close()
+		json.NewDecoder(res.Body).Decode(&report)
+		return res.StatusCode, report
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		assert := testarossa.For(t)
+		status, report := probe("4042", "/healthz")
+		assert.Equal(http.StatusOK, status)
+		assert.True(report.Healthy)
+
+		status, report = probe("4042", "/readyz")
+		assert.Equal(http.StatusOK, status)
+		assert.True(report.Healthy)
+	})
+
+	t.Run("unhealthy_dependency", func(t *testing.T) {
+		assert := testarossa.For(t)
+		mux.Lock()
+		downstreamErr = errors.New("connection refused")
+		mux.Unlock()
+		defer func() {
+			mux.Lock()
+			downstreamErr = nil
+			mux.Unlock()
+		}()
+
+		// The liveness of the ingress is not affected by other microservices
+		status, report := probe("4042", "/healthz")
+		assert.Equal(http.StatusOK, status)
+		assert.True(report.Healthy)
+
+		// The readiness of the ingress is not affected by other microservices
+		status, report = probe("4042", "/readyz")
+		assert.Equal(http.StatusOK, status)
+		assert.True(report.Healthy)
+	})
+
+	t.Run("not_on_public_port", func(t *testing.T) {
+		assert := testarossa.For(t)
+		status, report := probe("4041", "/healthz")
+		assert.NotEqual(http.StatusOK, status)
+		assert.False(report.Healthy)
+		status, report = probe("4041", "/readyz")
+		assert.NotEqual(http.StatusOK, status)
+		assert.False(report.Healthy)
+	})
+
+	t.Run("other_paths", func(t *testing.T) {
+		assert := testarossa.For(t)
+		status, _ := probe("4042", "/dependent.probes/")
+		assert.Equal(http.StatusNotFound, status)
+	})
+
+	t.Run("probe_port_is_public", func(t *testing.T) {
+		assert := testarossa.For(t)
+		err := svc.SetProbePort(4041)
+		assert.Error(err)
+		err = svc.SetProbePort(4042)
+		assert.NoError(err)
+	})
+}
+
 func TestHttpingress_ResolveInternalURL(t *testing.T) {
 	t.Parallel()
 	assert := testarossa.For(t)
@@ -1187,3 +1331,31 @@ func TestHTTPIngress_OnChangedBlockedPaths(t *testing.T) { // MARKER: BlockedPat
 		})
 	*/
 }
+
+func TestHTTPIngress_OnChangedProbePort(t *testing.T) { // MARKER: ProbePort
+	t.Parallel()
+	ctx := t.Context()
+	_ = ctx
+
+	// Initialize the microservice under test
+	svc := NewService()
+
+	// Run the testing app
+	app := application.New()
+	app.Add(
+		// HINT: Add microservices or mocks required for this test
+		svc,
+	)
+	app.RunInTest(t)
+
+	/*
+		HINT: Fill in test cases using the following pattern
+
+		t.Run("test_case_name", func(t *testing.T) {
+			assert := testarossa.For(t)
+
+			err := svc.SetProbePort(value)
+			assert.NoError(err)
+		})
+	*/
+}
@@ -53,8 +53,13 @@ type Function struct {
 	RequiredClaims string        // boolean expression over JWT claims; empty means open
 	TimeBudget     time.Duration // per-endpoint max duration; zero means the framework default
 	LoadBalancing  string        // "" (default), define.None, or a custom queue name
+	MaxConcurrency int           // max requests processed concurrently by each replica; zero means no limit
+	WaitQueue      int           // requests that may wait when MaxConcurrency is reached; excess requests are shed
+	IdempotencyTTL time.Duration // retention of responses to requests with an Idempotency-Key; zero means no deduplication
 	Manual         bool          // registered via sub.Manual(); brought online later with svc.ActivateSubscription(name)
 	Tags           []string      // sub.Tag labels for grouping subscriptions (e.g. "python")
+	RetryPolicy    RetryPolicy   // retries of the generated client; GET, PUT or DELETE only
+	CacheTTL       time.Duration // max duration the generated client caches responses, applied as a pub.Cache option; GET only
 	In             any           // the FooIn{} struct, as a type carrier
 	Out            any           // the FooOut{} struct, as a type carrier
 }
@@ -62,6 +67,16 @@ type Function struct {
 // URL is the full URL of the endpoint, joined with its Host.
 func (f Function) URL() string { return joinHostAndPath(f.Host, f.Route) }
 
+// RetryPolicy mirrors pub.RetryPolicy. The generated client retries failed requests of a Function
+// with a MaxAttempts greater than 1 by converting its RetryPolicy to a pub.Retry option.
+type RetryPolicy struct {
+	MaxAttempts int           // total number of attempts, including the first
+	Backoff     time.Duration // delay before the second attempt, doubled before each subsequent attempt
+	MaxBackoff  time.Duration // cap on the delay between attempts; zero means no cap
+	Jitter      float64       // fraction of the delay that is randomized, between 0 and 1
+	RetryOn     []int         // status codes that are retried; empty means pub.DefaultRetryOn
+}
+
 // Web is a raw http.ResponseWriter / *http.Request handler endpoint. It carries no In/Out.
 type Web struct {
 	Host           string        // the api package's Hostname const
@@ -70,6 +85,9 @@ type Web struct {
 	RequiredClaims string        // boolean expression over JWT claims; empty means open
 	TimeBudget     time.Duration // per-endpoint max duration; zero means the framework default
 	LoadBalancing  string        // "" (default), define.None, or a custom queue name
+	MaxConcurrency int           // max requests processed concurrently by each replica; zero means no limit
+	WaitQueue      int           // requests that may wait when MaxConcurrency is reached; excess requests are shed
+	IdempotencyTTL time.Duration // retention of responses to requests with an Idempotency-Key; zero means no deduplication
 	Manual         bool          // registered via sub.Manual(); brought online later with svc.ActivateSubscription(name)
 	Tags           []string      // sub.Tag labels for grouping subscriptions (e.g. "python")
 }
@@ -119,6 +137,7 @@ type OutboundEvent struct {
 	RequiredClaims string        // boolean expression over JWT claims; empty means open
 	TimeBudget     time.Duration // per-endpoint max duration; zero means the framework default
 	LoadBalancing  string        // "" (default), define.None, or a custom queue name
+	Durable        bool          // persisted to a JetStream stream and delivered at least once to each InboundEvent
 	In             any           // the FooIn{} struct, as a type carrier
 	Out            any           // the FooOut{} struct, as a type carrier
 }
@@ -136,6 +155,7 @@ type InboundEvent struct {
 	LoadBalancing  string        // "" (default), define.None, or a custom queue name
 	Manual         bool          // registered via sub.Manual(); brought online later with svc.ActivateSubscription(name)
 	Tags           []string      // sub.Tag labels for grouping subscriptions (e.g. "python")
+	MaxDeliveries  int           // deliveries of a durable event before it is dead-lettered; zero means the framework default
 }
 
 // Config is a runtime configuration property, sourced as a string then converted to Value's Go type.
@@ -159,7 +179,9 @@ type Metric struct {
 
 // Ticker runs a recurring operation on a schedule.
 type Ticker struct {
-	Interval time.Duration // duration between iterations
+	Interval  time.Duration // duration between iterations
+	Schedule  string        // cron expression, e.g. "CRON_TZ=America/New_York 0 2 * * MON-FRI", in lieu of an interval
+	Singleton bool          // runs on the leader elected among all instances rather than on each instance
 }
 
 // joinHostAndPath mirrors httpx.JoinHostAndPath.
@@ -18,8 +18,10 @@ package define
 
 import (
 	"testing"
+	"time"
 
 	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/fabric/pub"
 )
 
 // TestURL_MatchesHttpx pins joinHostAndPath to httpx.JoinHostAndPath, guarding the verbatim copy
@@ -44,3 +46,20 @@ func TestURL_MatchesHttpx(t *testing.T) {
 		}
 	}
 }
+
+// TestRetryPolicy_MatchesPub pins RetryPolicy to pub.RetryPolicy. The generated client converts one to
+// the other, which compiles only while the two structs have identical fields.
+func TestRetryPolicy_MatchesPub(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+		Jitter:      0.5,
+		RetryOn:     []int{503},
+	}
+	converted := pub.RetryPolicy(policy)
+	if converted.MaxAttempts != 3 || converted.Backoff != 100*time.Millisecond || converted.MaxBackoff != time.Second ||
+		converted.Jitter != 0.5 || len(converted.RetryOn) != 1 || converted.RetryOn[0] != 503 {
+		t.Errorf("converted policy %+v does not match %+v", converted, policy)
+	}
+}
@@ -34,24 +34,30 @@ import (
 )
 
 const (
-	HeaderPrefix        = "Microbus-"
-	HeaderBaggagePrefix = HeaderPrefix + "Baggage-"
-	HeaderMsgId         = HeaderPrefix + "Msg-Id"
-	HeaderFromHost      = HeaderPrefix + "From-Host"
-	HeaderFromId        = HeaderPrefix + "From-Id"
-	HeaderFromVersion   = HeaderPrefix + "From-Version"
-	HeaderTimeBudget    = HeaderPrefix + "Time-Budget"
-	HeaderCallDepth     = HeaderPrefix + "Call-Depth"
-	HeaderOpCode        = HeaderPrefix + "Op-Code"
-	HeaderQueue         = HeaderPrefix + "Queue"
-	HeaderFragment      = HeaderPrefix + "Fragment"
-	HeaderLocality      = HeaderPrefix + "Locality"
-	HeaderActor         = HeaderPrefix + "Actor"
+	HeaderPrefix          = "Microbus-"
+	HeaderBaggagePrefix   = HeaderPrefix + "Baggage-"
+	HeaderMsgId           = HeaderPrefix + "Msg-Id"
+	HeaderFromHost        = HeaderPrefix + "From-Host"
+	HeaderFromId          = HeaderPrefix + "From-Id"
+	HeaderFromVersion     = HeaderPrefix + "From-Version"
+	HeaderTimeBudget      = HeaderPrefix + "Time-Budget"
+	HeaderCallDepth       = HeaderPrefix + "Call-Depth"
+	HeaderOpCode          = HeaderPrefix + "Op-Code"
+	HeaderQueue           = HeaderPrefix + "Queue"
+	HeaderFragment        = HeaderPrefix + "Fragment"
+	HeaderStream          = HeaderPrefix + "Stream"
+	HeaderLocality        = HeaderPrefix + "Locality"
+	HeaderActor           = HeaderPrefix + "Actor"
+	HeaderContentEncoding = HeaderPrefix + "Content-Encoding"
+	HeaderAcceptEncoding  = HeaderPrefix + "Accept-Encoding"
+	HeaderCancelable      = HeaderPrefix + "Cancelable"
+	HeaderFaultsInjected  = HeaderPrefix + "Faults-Injected"
 
 	OpCodeError    = "Err"
 	OpCodeAck      = "Ack"
 	OpCodeRequest  = "Req"
 	OpCodeResponse = "Res"
+	OpCodeCancel   = "Can"
 )
 
 type contextKeyType struct{}
@@ -370,6 +376,39 @@ func (f Frame) SetFragment(index int, max int) {
 	}
 }
 
+// Stream returns the index of the chunk of a streamed response and the total number of chunks.
+// Chunks are indexed starting at 1. The total is 0 until the last chunk, which carries its own index as the total.
+// Both are 0 if the response is not streamed.
+func (f Frame) Stream() (index int, max int) {
+	v := f.h.Get(HeaderStream)
+	if v == "" {
+		return 0, 0
+	}
+	parts := strings.Split(v, "/")
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	index, err := strconv.Atoi(parts[0])
+	if err != nil || index < 1 {
+		return 0, 0
+	}
+	max, err = strconv.Atoi(parts[1])
+	if err != nil || max < 0 {
+		return 0, 0
+	}
+	return index, max
+}
+
+// SetStream sets the index of the chunk of a streamed response and the total number of chunks.
+// Chunks are indexed starting at 1. The total should be 0 for all but the last chunk.
+func (f Frame) SetStream(index int, max int) {
+	if index < 1 || max < 0 {
+		f.h.Del(HeaderStream)
+	} else {
+		f.h.Set(HeaderStream, strconv.Itoa(index)+"/"+strconv.Itoa(max))
+	}
+}
+
 // Baggage is an arbitrary name=value pair that is passed through to downstream microservices.
 func (f Frame) Baggage(name string) (value string) {
 	return f.h.Get(HeaderBaggagePrefix + name)
@@ -446,6 +485,77 @@ func (f Frame) SetLocality(locality string) {
 	}
 }
 
+// Cancelable indicates if the responder that acked the request supports its cancellation by the caller.
+func (f Frame) Cancelable() bool {
+	return f.h.Get(HeaderCancelable) == "1"
+}
+
+// SetCancelable sets whether the responder that acks the request supports its cancellation by the caller.
+// Callers send a cancellation only to responders that advertise support, because older responders
+// would process the cancellation as a new request.
+func (f Frame) SetCancelable(cancelable bool) {
+	if cancelable {
+		f.h.Set(HeaderCancelable, "1")
+	} else {
+		f.h.Del(HeaderCancelable)
+	}
+}
+
+// FaultsInjected indicates if the caller already injected faults into the request per its fault injection rules.
+func (f Frame) FaultsInjected() bool {
+	return f.h.Get(HeaderFaultsInjected) == "1"
+}
+
+// SetFaultsInjected sets whether the caller already injected faults into the request per its fault injection rules.
+// Responders do not inject the faults of the request again, so that a rule known to both sides is applied only once.
+func (f Frame) SetFaultsInjected(injected bool) {
+	if injected {
+		f.h.Set(HeaderFaultsInjected, "1")
+	} else {
+		f.h.Del(HeaderFaultsInjected)
+	}
+}
+
+// ContentEncoding indicates the encoding with which the transport compressed the body of the message.
+// It is distinct from the Content-Encoding header, which is set by the application.
+func (f Frame) ContentEncoding() string {
+	return f.h.Get(HeaderContentEncoding)
+}
+
+// SetContentEncoding sets the encoding with which the transport compressed the body of the message.
+func (f Frame) SetContentEncoding(encoding string) {
+	if encoding == "" {
+		f.h.Del(HeaderContentEncoding)
+	} else {
+		f.h.Set(HeaderContentEncoding, encoding)
+	}
+}
+
+// AcceptEncoding returns the encodings that the sender of the message is able to decompress, in order of preference.
+func (f Frame) AcceptEncoding() []string {
+	v := f.h.Get(HeaderAcceptEncoding)
+	if v == "" {
+		return nil
+	}
+	var result []string
+	for enc := range strings.SplitSeq(v, ",") {
+		enc = strings.TrimSpace(enc)
+		if enc != "" {
+			result = append(result, enc)
+		}
+	}
+	return result
+}
+
+// SetAcceptEncoding sets the encodings that the sender of the message is able to decompress, in order of preference.
+func (f Frame) SetAcceptEncoding(encodings ...string) {
+	if len(encodings) == 0 {
+		f.h.Del(HeaderAcceptEncoding)
+	} else {
+		f.h.Set(HeaderAcceptEncoding, strings.Join(encodings, ", "))
+	}
+}
+
 // SetActor sets the actor of the frame as an unsigned JWT with the given claims.
 // The claims object is marshaled to JSON and used as the JWT payload.
 // A nil claims object clears the actor.
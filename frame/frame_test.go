@@ -154,6 +154,47 @@ func TestFrame_GetSet(t *testing.T) {
 	fi, fm = f.Fragment()
 	assert.Equal(fi, 1)
 	assert.Equal(fm, 1)
+
+	assert.Equal("", f.ContentEncoding())
+	f.SetContentEncoding("zstd")
+	assert.Equal("zstd", f.ContentEncoding())
+	f.SetContentEncoding("")
+	assert.Equal("", f.ContentEncoding())
+
+	assert.Len(f.AcceptEncoding(), 0)
+	f.SetAcceptEncoding("zstd", "br")
+	assert.Equal("zstd, br", f.h.Get(HeaderAcceptEncoding))
+	assert.Equal([]string{"zstd", "br"}, f.AcceptEncoding())
+	f.SetAcceptEncoding()
+	assert.Len(f.AcceptEncoding(), 0)
+
+	si, sm := f.Stream()
+	assert.Equal(0, si)
+	assert.Equal(0, sm)
+	f.SetStream(3, 0)
+	si, sm = f.Stream()
+	assert.Equal(3, si)
+	assert.Equal(0, sm)
+	f.SetStream(4, 4)
+	si, sm = f.Stream()
+	assert.Equal(4, si)
+	assert.Equal(4, sm)
+	f.SetStream(0, 0)
+	si, sm = f.Stream()
+	assert.Equal(0, si)
+	assert.Equal(0, sm)
+
+	assert.False(f.Cancelable())
+	f.SetCancelable(true)
+	assert.True(f.Cancelable())
+	f.SetCancelable(false)
+	assert.False(f.Cancelable())
+
+	assert.False(f.FaultsInjected())
+	f.SetFaultsInjected(true)
+	assert.True(f.FaultsInjected())
+	f.SetFaultsInjected(false)
+	assert.False(f.FaultsInjected())
 }
 
 func TestFrame_XForwarded(t *testing.T) {
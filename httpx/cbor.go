@@ -0,0 +1,292 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/microbus-io/errors"
+)
+
+// cborTagEmbeddedJSON is the CBOR tag of a byte string holding a JSON document.
+const cborTagEmbeddedJSON = 262
+
+// Limits that protect the decoder from crafted input.
+const (
+	cborMaxNestedLevels  = 10000   // Maximum nesting of data items, same as encoding/json
+	cborMaxArrayElements = 1 << 20 // Maximum number of elements of an array
+	cborMaxMapPairs      = 1 << 20 // Maximum number of key-value pairs of a map
+)
+
+var (
+	cborEncMode cbor.EncMode
+	cborDecMode cbor.DecMode
+
+	cborAnyCache sync.Map // reflect.Type -> bool
+)
+
+func init() {
+	var err error
+	cborEncMode, err = cbor.EncOptions{
+		Sort:                    cbor.SortBytewiseLexical,
+		ShortestFloat:           cbor.ShortestFloat16,
+		Time:                    cbor.TimeRFC3339Nano,
+		TimeTag:                 cbor.EncTagRequired,
+		BinaryMarshaler:         cbor.BinaryMarshalerNone,
+		TextMarshaler:           cbor.TextMarshalerTextString,
+		JSONMarshalerTranscoder: cborTranscoder(embedJSON),
+	}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	cborDecMode, err = cbor.DecOptions{
+		MaxNestedLevels:           cborMaxNestedLevels,
+		MaxArrayElements:          cborMaxArrayElements,
+		MaxMapPairs:               cborMaxMapPairs,
+		TimeTagToAny:              cbor.TimeTagToRFC3339Nano,
+		BinaryUnmarshaler:         cbor.BinaryUnmarshalerNone,
+		TextUnmarshaler:           cbor.TextUnmarshalerTextString,
+		JSONUnmarshalerTranscoder: cborTranscoder(extractJSON),
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+}
+
+/*
+cborCodec encodes payloads as CBOR (RFC 8949) using github.com/fxamacker/cbor.
+
+Values are mapped the same way encoding/json maps them, so that payload types need no CBOR-specific tags:
+structs are encoded as maps keyed by the names in their json tags, honoring the omitempty and omitzero options,
+nil slices, maps and pointers are encoded as null, and interface values decode into the same types JSON
+decodes into (map[string]any, []any, float64, string and bool).
+Numbers, byte slices and time.Time are encoded in their compact binary form.
+Types that implement json.Marshaler are embedded as JSON (tag 262) and types that implement
+encoding.TextMarshaler are encoded as text.
+*/
+type cborCodec struct{}
+
+// ContentType is application/cbor.
+func (cborCodec) ContentType() string {
+	return "application/cbor"
+}
+
+// Marshal encodes the value as CBOR.
+func (cborCodec) Marshal(v any) ([]byte, error) {
+	b, err := cborEncMode.Marshal(v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return b, nil
+}
+
+// Unmarshal decodes the CBOR data into the value pointed to by v.
+func (cborCodec) Unmarshal(data []byte, v any) error {
+	err := cborDecMode.Unmarshal(data, v)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Interface values hold what JSON would have decoded into them
+	cborAsJSON(reflect.ValueOf(v))
+	return nil
+}
+
+// cborTranscoder adapts a function to the cbor.Transcoder interface.
+type cborTranscoder func(dst io.Writer, src io.Reader) error
+
+// Transcode calls the function.
+func (f cborTranscoder) Transcode(dst io.Writer, src io.Reader) error {
+	return f(dst, src)
+}
+
+// embedJSON transcodes the output of a json.Marshaler to a byte string tagged as embedded JSON.
+func embedJSON(dst io.Writer, src io.Reader) error {
+	j, err := io.ReadAll(src)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b, err := cborEncMode.Marshal(cbor.Tag{Number: cborTagEmbeddedJSON, Content: j})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = dst.Write(b)
+	return errors.Trace(err)
+}
+
+// extractJSON transcodes a data item to the input of a json.Unmarshaler.
+// Embedded JSON is passed through as is, and any other data item is converted to its JSON equivalent.
+func extractJSON(dst io.Writer, src io.Reader) error {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var x any
+	err = cborDecMode.Unmarshal(data, &x)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tag, ok := x.(cbor.Tag); ok && tag.Number == cborTagEmbeddedJSON {
+		if j, ok := tag.Content.([]byte); ok {
+			_, err = dst.Write(j)
+			return errors.Trace(err)
+		}
+	}
+	j, err := json.Marshal(jsonValue(x))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = dst.Write(j)
+	return errors.Trace(err)
+}
+
+// jsonValue converts a generically decoded data item to the value JSON would have decoded it into.
+// Numbers become float64, maps become map[string]any and embedded JSON is decoded.
+func jsonValue(x any) any {
+	switch x := x.(type) {
+	case uint64:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case big.Int:
+		f, _ := new(big.Float).SetInt(&x).Float64()
+		return f
+	case []any:
+		for i := range x {
+			x[i] = jsonValue(x[i])
+		}
+		return x
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for k, v := range x {
+			m[jsonKey(k)] = jsonValue(v)
+		}
+		return m
+	case map[string]any:
+		for k, v := range x {
+			x[k] = jsonValue(v)
+		}
+		return x
+	case cbor.Tag:
+		if j, ok := x.Content.([]byte); ok && x.Number == cborTagEmbeddedJSON {
+			var v any
+			if json.Unmarshal(j, &v) == nil {
+				return v
+			}
+		}
+		return jsonValue(x.Content)
+	}
+	return x
+}
+
+// jsonKey converts a generically decoded map key to a string.
+func jsonKey(k any) string {
+	switch k := k.(type) {
+	case string:
+		return k
+	case cbor.ByteString:
+		return string(k)
+	case uint64:
+		return strconv.FormatUint(k, 10)
+	case int64:
+		return strconv.FormatInt(k, 10)
+	case float64:
+		return strconv.FormatFloat(k, 'f', -1, 64)
+	}
+	return fmt.Sprint(k)
+}
+
+// cborAsJSON replaces the generically decoded data items held in the interface values reachable from v
+// with the values JSON would have decoded into them.
+func cborAsJSON(v reflect.Value) {
+	if !v.IsValid() || !cborHasAny(v.Type(), nil) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || v.NumMethod() > 0 || !v.CanSet() {
+			return
+		}
+		x := jsonValue(v.Interface())
+		if x == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(x))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			cborAsJSON(v.Elem())
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Field(i).CanSet() {
+				cborAsJSON(v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			cborAsJSON(v.Index(i))
+		}
+	case reflect.Map:
+		if !cborHasAny(v.Type().Elem(), nil) {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			cborAsJSON(elem)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
+
+// cborHasAny indicates if values of the type can hold an empty interface value.
+func cborHasAny(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting == nil {
+		// Only results of complete traversals are cached
+		if cached, ok := cborAnyCache.Load(t); ok {
+			return cached.(bool)
+		}
+		has := cborHasAny(t, map[reflect.Type]bool{})
+		cborAnyCache.Store(t, has)
+		return has
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return cborHasAny(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if t.Field(i).IsExported() && cborHasAny(t.Field(i).Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
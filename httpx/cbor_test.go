@@ -0,0 +1,286 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestHttpx_CBOREncoding(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	// Test vectors from RFC 8949 Appendix A
+	testCases := []struct {
+		value any
+		hex   string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{1000000, "1a000f4240"},
+		{uint64(18446744073709551615), "1bffffffffffffffff"},
+		{-1, "20"},
+		{-1000, "3903e7"},
+		{1.5, "f93e00"},
+		{1.1, "fb3ff199999999999a"},
+		{float32(100000.0), "fa47c35000"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{"", "60"},
+		{"IETF", "6449455446"},
+		{"ü", "62c3bc"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{[]int{1, 2, 3}, "83010203"},
+		{[]string(nil), "f6"},
+		{map[string]int{"a": 1, "b": 2}, "a2616101616202"},
+	}
+	for _, tc := range testCases {
+		b, err := CBOR.Marshal(tc.value)
+		if assert.NoError(err) {
+			assert.Equal(tc.hex, hex.EncodeToString(b), "%v", tc.value)
+		}
+	}
+}
+
+func TestHttpx_CBORDecoding(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	decode := func(h string, v any) error {
+		b, err := hex.DecodeString(h)
+		assert.NoError(err)
+		return CBOR.Unmarshal(b, v)
+	}
+
+	// Half-precision floats
+	var f float64
+	err := decode("f93c00", &f)
+	assert.Expect(err, nil, f, 1.0)
+	err = decode("f97bff", &f)
+	assert.Expect(err, nil, f, 65504.0)
+	err = decode("f9c400", &f)
+	assert.Expect(err, nil, f, -4.0)
+	err = decode("f97c00", &f)
+	assert.Expect(err, nil, math.IsInf(f, 1), true)
+
+	// Indefinite-length items
+	var s string
+	err = decode("7f657374726561646d696e67ff", &s)
+	assert.Expect(err, nil, s, "streaming")
+	var arr []int
+	err = decode("9f018202039f0405ffff", &arr)
+	assert.Error(err) // Nested arrays cannot decode into ints
+	err = decode("9f0102030405ff", &arr)
+	assert.Expect(err, nil, arr, []int{1, 2, 3, 4, 5})
+	var m map[string]any
+	err = decode("bf61610161629f0203ffff", &m)
+	assert.Expect(err, nil, m, map[string]any{"a": 1.0, "b": []any{2.0, 3.0}})
+
+	// Integer overflow
+	var i8 int8
+	err = decode("190100", &i8)
+	assert.Error(err)
+	var u uint
+	err = decode("20", &u)
+	assert.Error(err)
+
+	// Malformed or truncated data
+	err = decode("1a000f42", &u)
+	assert.Error(err)
+	err = decode("1c", &u)
+	assert.Error(err)
+	err = decode("0000", &u)
+	assert.Error(err)
+	err = CBOR.Unmarshal([]byte{0}, u)
+	assert.Error(err)
+}
+
+func TestHttpx_CBORRoundTrip(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	type Point struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	}
+	type Base struct {
+		ID      int    `json:"id"`
+		Shadow  string `json:"shadow"`
+		Private string `json:"-"`
+	}
+	type Payload struct {
+		Base
+		Shadow     int                 `json:"shadow"`
+		Name       string              `json:"name,omitzero"`
+		Empty      string              `json:"empty,omitempty"`
+		Untagged   bool                //
+		Vector     []float64           `json:"vector,omitzero"`
+		Small      []float32           `json:"small,omitzero"`
+		Data       []byte              `json:"data,omitzero"`
+		Points     map[string]Point    `json:"points,omitzero"`
+		ByID       map[int]string      `json:"byId,omitzero"`
+		Ptr        *Point              `json:"ptr,omitzero"`
+		NilPtr     *Point              `json:"nilPtr"`
+		When       time.Time           `json:"when,omitzero"`
+		Duration   time.Duration       `json:"duration,omitzero"`
+		URL        *url.URL            `json:"url,omitzero"`
+		Raw        json.RawMessage     `json:"raw,omitzero"`
+		Any        any                 `json:"any,omitzero"`
+		Nested     [][]int             `json:"nested,omitzero"`
+		Array      [3]int8             `json:"array"`
+		Headers    map[string][]string `json:"headers,omitzero"`
+		unexported int
+	}
+	when := time.Date(2026, 5, 4, 10, 20, 30, 123456789, time.UTC)
+	in := Payload{
+		Base:     Base{ID: 5, Shadow: "hidden", Private: "secret"},
+		Shadow:   7,
+		Name:     "Microbus",
+		Untagged: true,
+		Vector:   []float64{0.1, -2.5, math.MaxFloat64, 0},
+		Small:    []float32{1.5, -0.25},
+		Data:     []byte("binary"),
+		Points:   map[string]Point{"a": {1, 2}, "b": {-3, 4.75}},
+		ByID:     map[int]string{1: "one", -2: "minus two"},
+		Ptr:      &Point{X: 9},
+		When:     when,
+		Duration: 3 * time.Second,
+		Raw:      json.RawMessage(`{"x":[1,2]}`),
+		Any:      map[string]any{"k": []any{"v", 1.0, true, nil}},
+		Nested:   [][]int{{1}, {2, 3}},
+		Array:    [3]int8{-1, 0, 1},
+		Headers:  map[string][]string{"Accept": {"a", "b"}},
+	}
+	in.URL, _ = url.Parse("https://example.com/path?q=1")
+	in.unexported = 1
+
+	b, err := CBOR.Marshal(in)
+	if !assert.NoError(err) {
+		return
+	}
+	var out Payload
+	err = CBOR.Unmarshal(b, &out)
+	if !assert.NoError(err) {
+		return
+	}
+	in.Private = ""
+	in.Base.Shadow = "" // Shadowed by the outer field, as in JSON
+	in.unexported = 0
+	assert.Equal(in, out)
+
+	// The same structure should be seen through a generic decoding as through JSON
+	jsonBytes, err := json.Marshal(in)
+	assert.NoError(err)
+	var viaJSON any
+	err = json.Unmarshal(jsonBytes, &viaJSON)
+	assert.NoError(err)
+	var viaCBOR any
+	err = CBOR.Unmarshal(b, &viaCBOR)
+	assert.NoError(err)
+	jsonMap := viaJSON.(map[string]any)
+	cborMap := viaCBOR.(map[string]any)
+	assert.Equal(len(jsonMap), len(cborMap))
+	for k := range jsonMap {
+		_, ok := cborMap[k]
+		assert.True(ok, "missing %s", k)
+	}
+	assert.Equal(jsonMap["points"], cborMap["points"])
+	assert.Equal(jsonMap["raw"], cborMap["raw"])
+	assert.Equal(jsonMap["any"], cborMap["any"])
+	assert.Equal(jsonMap["when"], cborMap["when"])
+
+	// Binary encoding should be more compact than JSON
+	assert.True(len(b) < len(jsonBytes))
+}
+
+func TestHttpx_CBORNull(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	type Payload struct {
+		S []int          `json:"s"`
+		M map[string]int `json:"m"`
+		P *int           `json:"p"`
+		N int            `json:"n"`
+	}
+	one := 1
+	out := Payload{S: []int{1}, M: map[string]int{"a": 1}, P: &one, N: 1}
+	b, err := CBOR.Marshal(map[string]any{"s": nil, "m": nil, "p": nil, "n": nil})
+	assert.NoError(err)
+	err = CBOR.Unmarshal(b, &out)
+	if assert.NoError(err) {
+		assert.Expect(
+			out.S, []int(nil),
+			out.M, map[string]int(nil),
+			out.P, (*int)(nil),
+			out.N, 1,
+		)
+	}
+}
+
+func TestHttpx_CBORCraftedInput(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	// Deeply nested indefinite-length arrays
+	nested := bytes.Repeat([]byte{0x9f}, cborMaxNestedLevels+1)
+	var x any
+	err := CBOR.Unmarshal(nested, &x)
+	assert.Contains(err, "max nested level")
+	var s []any
+	err = CBOR.Unmarshal(nested, &s)
+	assert.Contains(err, "max nested level")
+	type Payload struct {
+		X any `json:"x"`
+	}
+	var p Payload
+	err = CBOR.Unmarshal(append([]byte{0xa1, 0x61, 'y'}, nested...), &p)
+	assert.Contains(err, "max nested level")
+
+	// Nesting within the limit is decoded
+	ok := append(bytes.Repeat([]byte{0x81}, 100), 0x01)
+	err = CBOR.Unmarshal(ok, &x)
+	assert.NoError(err)
+
+	// An array that claims more elements than are present
+	type Big struct {
+		Data [1024]byte `json:"data"`
+	}
+	claimed := []byte{0x9a, 0x00, 0x10, 0x00, 0x00} // Array of 1M elements
+	claimed = append(claimed, 0xa0, 0x1c)           // Empty map followed by a malformed item
+	claimed = append(claimed, make([]byte, 1<<20)...)
+	var bigs []Big
+	err = CBOR.Unmarshal(claimed, &bigs)
+	assert.Contains(err, "invalid additional information")
+	var tooBig []int
+	err = CBOR.Unmarshal([]byte{0x9a, 0x00, 0x10, 0x00, 0x01}, &tooBig) // Array of 1M+1 elements
+	assert.Contains(err, "exceeded max number of elements")
+	var short []int
+	err = CBOR.Unmarshal([]byte{0x9b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, &short)
+	assert.Error(err)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"encoding/json"
+	"mime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Codec marshals and unmarshals payloads of a media type.
+// Codecs are used by the payload helpers to encode and decode the payloads of functional endpoints.
+type Codec interface {
+	// ContentType is the media type of the encoding, e.g. application/json.
+	ContentType() string
+	// Marshal encodes the value.
+	Marshal(v any) ([]byte, error)
+	// Unmarshal decodes the data into the value pointed to by v.
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JSON is the codec of the application/json media type. It is the default encoding of payloads.
+	JSON Codec = jsonCodec{}
+	// CBOR is the codec of the application/cbor media type. It is a compact binary encoding
+	// that generated clients negotiate via the Accept header for the responses of other microservices.
+	// Request bodies are sent as JSON so that servers that do not understand CBOR are not given inputs they ignore.
+	CBOR Codec = cborCodec{}
+
+	codecs    = map[string]Codec{}
+	codecsMux sync.RWMutex
+)
+
+func init() {
+	RegisterCodec(JSON)
+	RegisterCodec(CBOR)
+}
+
+// RegisterCodec registers a codec for its media type, replacing any codec previously registered for it.
+// Registered codecs are selected by the Content-Type and Accept headers of requests and responses.
+func RegisterCodec(codec Codec) {
+	codecsMux.Lock()
+	codecs[strings.ToLower(codec.ContentType())] = codec
+	codecsMux.Unlock()
+}
+
+// LookupCodec returns the codec registered for the media type of the content type, or nil if there is none.
+// Parameters of the content type, such as the charset, are ignored.
+func LookupCodec(contentType string) Codec {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	codecsMux.RLock()
+	defer codecsMux.RUnlock()
+	return codecs[mediaType]
+}
+
+// NegotiateCodec returns the registered codec most preferred by an Accept header.
+// Wildcards are not considered to be a preference, so JSON is returned unless a codec is named explicitly.
+func NegotiateCodec(accept string) Codec {
+	var best Codec
+	bestQ := 0.0
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || strings.Contains(mediaType, "*") {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(qs, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		codecsMux.RLock()
+		codec := codecs[mediaType]
+		codecsMux.RUnlock()
+		if codec != nil {
+			best, bestQ = codec, q
+		}
+	}
+	if best == nil {
+		return JSON
+	}
+	return best
+}
+
+// AcceptHeader returns an Accept header that prefers the codec and falls back to JSON.
+func AcceptHeader(codec Codec) string {
+	if codec == JSON {
+		return JSON.ContentType()
+	}
+	return codec.ContentType() + ", " + JSON.ContentType() + ";q=0.9"
+}
+
+// jsonCodec is the codec of the application/json media type.
+type jsonCodec struct{}
+
+// ContentType is application/json.
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+// Marshal encodes the value as JSON.
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the JSON data into the value pointed to by v.
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
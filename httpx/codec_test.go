@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestHttpx_LookupCodec(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	assert.Equal(JSON, LookupCodec("application/json"))
+	assert.Equal(JSON, LookupCodec("application/json; charset=utf-8"))
+	assert.Equal(CBOR, LookupCodec("Application/CBOR"))
+	assert.Nil(LookupCodec("text/plain"))
+	assert.Nil(LookupCodec(""))
+	assert.Nil(LookupCodec("application/x-www-form-urlencoded"))
+}
+
+func TestHttpx_NegotiateCodec(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	assert.Equal(JSON, NegotiateCodec(""))
+	assert.Equal(JSON, NegotiateCodec("*/*"))
+	assert.Equal(JSON, NegotiateCodec("text/html, application/*"))
+	assert.Equal(JSON, NegotiateCodec("application/json"))
+	assert.Equal(CBOR, NegotiateCodec("application/cbor"))
+	assert.Equal(CBOR, NegotiateCodec(AcceptHeader(CBOR)))
+	assert.Equal(JSON, NegotiateCodec("application/cbor;q=0.5, application/json"))
+	assert.Equal(CBOR, NegotiateCodec("text/html, application/cbor, */*;q=0.8"))
+	assert.Equal(JSON, NegotiateCodec("application/msgpack"))
+	assert.Equal("application/json", AcceptHeader(JSON))
+}
+
+func TestHttpx_PayloadCodecs(t *testing.T) {
+	t.Parallel()
+
+	type In struct {
+		X      int       `json:"x,omitzero"`
+		Vector []float64 `json:"vector,omitzero"`
+	}
+	type Out struct {
+		Sum            float64 `json:"sum,omitzero"`
+		HTTPStatusCode int     `json:"-"`
+	}
+
+	for _, codec := range []Codec{JSON, CBOR} {
+		t.Run(codec.ContentType(), func(t *testing.T) {
+			assert := testarossa.For(t)
+
+			// Client side: the body of the request is encoded with the codec
+			query, body, contentType, err := WriteInputPayloadWith(codec, "POST", In{X: 5, Vector: []float64{1.5, 2.5}})
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Expect(
+				len(query), 0,
+				contentType, codec.ContentType(),
+			)
+			r := httptest.NewRequest("POST", "https://host/sum", bytes.NewReader(body))
+			r.Header.Set("Content-Type", contentType)
+			r.Header.Set("Accept", AcceptHeader(codec))
+
+			// Server side: the request is decoded by its content type and the response is negotiated by the Accept header
+			var in In
+			err = ReadInputPayload(r, "/sum", &in)
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Expect(
+				in.X, 5,
+				in.Vector, []float64{1.5, 2.5},
+			)
+			w := httptest.NewRecorder()
+			err = WriteNegotiatedOutputPayload(w, r, Out{Sum: 9, HTTPStatusCode: http.StatusAccepted})
+			assert.NoError(err)
+			assert.Expect(
+				w.Code, http.StatusAccepted,
+				w.Header().Get("Content-Type"), codec.ContentType(),
+			)
+
+			// Client side: the response is decoded by its content type
+			var out Out
+			err = ReadOutputPayload(w.Result(), &out)
+			assert.Expect(
+				err, nil,
+				out.Sum, 9.0,
+				out.HTTPStatusCode, http.StatusAccepted,
+			)
+		})
+	}
+
+	t.Run("no_body", func(t *testing.T) {
+		assert := testarossa.For(t)
+
+		query, body, contentType, err := WriteInputPayloadWith(CBOR, "GET", In{X: 5})
+		assert.Expect(
+			err, nil,
+			query.Get("x"), "5",
+			body, []byte(nil),
+			contentType, "",
+		)
+	})
+}
+
+// embeddingPayload is a large numeric payload, similar to that produced by the embedder example.
+type embeddingPayload struct {
+	Model  string    `json:"model,omitzero"`
+	Vector []float64 `json:"vector,omitzero"`
+}
+
+func newEmbeddingPayload() embeddingPayload {
+	p := embeddingPayload{
+		Model:  "text-embedding",
+		Vector: make([]float64, 1536),
+	}
+	for i := range p.Vector {
+		p.Vector[i] = rand.NormFloat64()
+	}
+	return p
+}
+
+func benchmarkPayloadCodec(b *testing.B, codec Codec) {
+	payload := newEmbeddingPayload()
+	r := httptest.NewRequest("GET", "https://host/embed", nil)
+	r.Header.Set("Accept", AcceptHeader(codec))
+	var size int
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		w := httptest.NewRecorder()
+		_ = WriteNegotiatedOutputPayload(w, r, payload)
+		size = w.Body.Len()
+		res := w.Result()
+		var out embeddingPayload
+		_ = ReadOutputPayload(res, &out)
+		io.Copy(io.Discard, res.Body)
+	}
+	b.ReportMetric(float64(size), "bytes/payload")
+}
+
+func BenchmarkHttpx_PayloadJSON(b *testing.B) {
+	benchmarkPayloadCodec(b, JSON)
+}
+
+func BenchmarkHttpx_PayloadCBOR(b *testing.B) {
+	benchmarkPayloadCodec(b, CBOR)
+}
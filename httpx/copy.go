@@ -30,9 +30,29 @@ import (
 // As an optimization, when the response body is a [BodyReader] and the writer is an empty [ResponseRecorder], Copy
 // transfers ownership of the body's bytes to the recorder rather than copying them, so the two share one backing
 // array. The bytes should therefore be considered read-only after the copy.
+//
+// A [StreamBody] is likewise handed over to an empty [ResponseRecorder] without being read, so that it can be
+// passed along further. When copied to any other writer that implements [http.Flusher], the writer is flushed
+// after each chunk so that the stream reaches the client incrementally.
 func Copy(w http.ResponseWriter, res *http.Response) error {
-	// Optimize for ResponseRecorder and BodyReader
+	// Pass streams through recorders without reading them
 	rr, ok1 := w.(*ResponseRecorder)
+	sb, ok3 := res.Body.(*StreamBody)
+	if ok1 && ok3 && (rr.body == nil || rr.body.Len() == 0) {
+		for k, vv := range res.Header {
+			if k == "Content-Length" {
+				continue
+			}
+			for _, v := range vv {
+				w.Header().Add(k, v)
+			}
+		}
+		rr.statusCode = res.StatusCode
+		rr.stream = sb
+		return nil
+	}
+
+	// Optimize for ResponseRecorder and BodyReader
 	br, ok2 := res.Body.(*BodyReader)
 	if ok1 && ok2 {
 		if len(rr.header) == 0 {
@@ -65,7 +85,31 @@ func Copy(w http.ResponseWriter, res *http.Response) error {
 			w.Header().Add(k, v)
 		}
 	}
+	if ok3 {
+		w.Header().Del("Content-Length")
+	}
 	w.WriteHeader(res.StatusCode)
+	if flusher, ok := w.(http.Flusher); ok && ok3 {
+		// Flush each chunk as it arrives
+		defer sb.Close()
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := sb.Read(buf)
+			if n > 0 {
+				_, werr := w.Write(buf[:n])
+				if werr != nil {
+					return errors.Trace(werr)
+				}
+				flusher.Flush()
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
 	if res.Body != nil {
 		_, err := io.Copy(w, res.Body)
 		if err != nil {
@@ -25,6 +25,7 @@ import (
 
 var (
 	hostnameValidator = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)*$`)
+	versionSegment    = regexp.MustCompile(`^ver-[0-9]+(\.|$)`)
 )
 
 // ValidateHostname checks that the string is a canonical Microbus service identity.
@@ -32,7 +33,8 @@ var (
 //   - Length up to 252 characters.
 //   - Only lowercase letters, digits, dot separators, and hyphens. No underscores. No uppercase.
 //   - Segments are separated by single dots; no leading, trailing, or consecutive dots.
-//   - The first segment may not start with the reserved prefixes "id-" or "loc-".
+//   - The first segment may not start with the reserved prefixes "id-" or "loc-",
+//     nor be a version slot in the form "ver-" followed by digits (e.g. "ver-7").
 //   - The hostname is not "all" and does not end in ".all".
 //
 // The caller is responsible for normalization (trim, lowercase). Non-canonical input
@@ -47,7 +49,7 @@ func ValidateHostname(hostname string) error {
 	if !hostnameValidator.MatchString(hostname) {
 		return errors.New("invalid hostname '%s'", hostname)
 	}
-	if strings.HasPrefix(hostname, "id-") || strings.HasPrefix(hostname, "loc-") {
+	if strings.HasPrefix(hostname, "id-") || strings.HasPrefix(hostname, "loc-") || versionSegment.MatchString(hostname) {
 		return errors.New("invalid hostname '%s' (reserved prefix)", hostname)
 	}
 	if hostname == "all" || strings.HasSuffix(hostname, ".all") {
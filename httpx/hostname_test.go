@@ -35,6 +35,8 @@ func TestHttpx_ValidateHostname(t *testing.T) {
 		"hello-world",
 		"a.b.c.d",
 		"my-service.example.com",
+		"ver-api.example.com", // not a version slot
+		"version.example.com",
 	}
 	invalid := []string{
 		"",
@@ -53,6 +55,8 @@ func TestHttpx_ValidateHostname(t *testing.T) {
 		"id-foo.bar",             // reserved prefix
 		"loc-us",                 // reserved prefix
 		"loc-us-west.b",          // reserved prefix
+		"ver-7.foo",              // reserved version slot
+		"ver-7",                  // reserved version slot
 		"all",                    // reserved broadcast hostname
 		"foo.all",                // reserved broadcast suffix
 		"foo.bar.all",            // reserved broadcast suffix
@@ -17,25 +17,41 @@ limitations under the License.
 package httpx
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 	"reflect"
 
 	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/utils"
 )
 
 // ParseRequestBody parses the body of an incoming request and populates the fields of a data object.
-// It supports JSON and URL-encoded form data content types.
+// It supports URL-encoded form data and the content types of the registered codecs, such as JSON and CBOR.
 // Use json tags to designate the name of the argument to map to each field.
 func ParseRequestBody(r *http.Request, data any) error {
-	// Parse JSON in the body
 	contentType := r.Header.Get("Content-Type")
-	if contentType == "application/json" {
+	codec := LookupCodec(contentType)
+	// Parse JSON in the body
+	if codec == JSON {
 		err := json.NewDecoder(r.Body).Decode(&data)
 		if err != nil {
 			return errors.Trace(err)
 		}
+	} else if codec != nil {
+		// Parse a binary payload in the body
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(body) > 0 {
+			err = codec.Unmarshal(body, data)
+			if err != nil {
+				return errors.Trace(err, http.StatusBadRequest)
+			}
+		}
 	}
 	// Parse form in body
 	if contentType == "application/x-www-form-urlencoded" {
@@ -125,6 +141,24 @@ func WriteInputPayload(method string, in any) (query url.Values, body any, err e
 	return query, body, nil
 }
 
+// WriteInputPayloadWith determines how to deliver the input payload, via query arguments or the body of the request.
+// The body, if any, is encoded with the codec and its content type is returned alongside it.
+// It enables the HTTPRequestBody magic argument.
+func WriteInputPayloadWith(codec Codec, method string, in any) (query url.Values, body []byte, contentType string, err error) {
+	query, bodySource, err := WriteInputPayload(method, in)
+	if err != nil {
+		return nil, nil, "", errors.Trace(err)
+	}
+	if utils.IsNil(bodySource) {
+		return query, nil, "", nil
+	}
+	body, err = codec.Marshal(bodySource)
+	if err != nil {
+		return nil, nil, "", errors.Trace(err)
+	}
+	return query, body, codec.ContentType(), nil
+}
+
 // ReadOutputPayload reads the HTTP response into the output payload.
 // It enables the HTTPResponseBody and HTTPStatusCode magic arguments.
 func ReadOutputPayload(res *http.Response, out any) (err error) {
@@ -138,11 +172,26 @@ func ReadOutputPayload(res *http.Response, out any) (err error) {
 			f.SetInt(int64(res.StatusCode))
 		}
 	}
-	if res.Body != nil && res.Body != http.NoBody {
+	if res.Body == nil || res.Body == http.NoBody {
+		return nil
+	}
+	codec := LookupCodec(res.Header.Get("Content-Type"))
+	if codec == nil || codec == JSON {
 		err = json.NewDecoder(res.Body).Decode(_decodeTarget)
 		if err != nil {
 			return errors.Trace(err)
 		}
+		return nil
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(body) > 0 {
+		err = codec.Unmarshal(body, _decodeTarget)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	return nil
 }
@@ -150,28 +199,45 @@ func ReadOutputPayload(res *http.Response, out any) (err error) {
 // WriteOutputPayload writes the output payload to the HTTP response as JSON.
 // It enables the HTTPStatusCode and HTTPResponseBody magic arguments.
 func WriteOutputPayload(w http.ResponseWriter, out any) (err error) {
-	w.Header().Set("Content-Type", "application/json")
+	return WriteOutputPayloadWith(w, JSON, out)
+}
+
+// WriteNegotiatedOutputPayload writes the output payload to the HTTP response
+// in the encoding preferred by the Accept header of the request, or as JSON if none is preferred.
+// It enables the HTTPStatusCode and HTTPResponseBody magic arguments.
+func WriteNegotiatedOutputPayload(w http.ResponseWriter, r *http.Request, out any) (err error) {
+	return WriteOutputPayloadWith(w, NegotiateCodec(r.Header.Get("Accept")), out)
+}
+
+// WriteOutputPayloadWith writes the output payload to the HTTP response, encoded with the codec.
+// It enables the HTTPStatusCode and HTTPResponseBody magic arguments.
+func WriteOutputPayloadWith(w http.ResponseWriter, codec Codec, out any) (err error) {
+	w.Header().Set("Content-Type", codec.ContentType())
 	v := reflect.ValueOf(out)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
-	if v.Kind() == reflect.Struct {
-		if f := v.FieldByName("HTTPStatusCode"); f.IsValid() {
-			w.WriteHeader(int(f.Int()))
-		}
-	}
-	encoder := json.NewEncoder(w)
 	if v.Kind() == reflect.Struct {
 		if f := v.FieldByName("HTTPResponseBody"); f.IsValid() {
-			err = encoder.Encode(f.Interface())
-		} else {
-			err = encoder.Encode(out)
+			out = f.Interface()
 		}
+	}
+	var body []byte
+	if codec == JSON {
+		var buf bytes.Buffer
+		err = json.NewEncoder(&buf).Encode(out)
+		body = buf.Bytes()
 	} else {
-		err = encoder.Encode(out)
+		body, err = codec.Marshal(out)
 	}
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	if v.Kind() == reflect.Struct {
+		if f := v.FieldByName("HTTPStatusCode"); f.IsValid() {
+			w.WriteHeader(int(f.Int()))
+		}
+	}
+	_, err = w.Write(body)
+	return errors.Trace(err)
 }
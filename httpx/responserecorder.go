@@ -19,6 +19,7 @@ package httpx
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 )
@@ -30,6 +31,7 @@ var _ = http.ResponseWriter(&ResponseRecorder{})
 type ResponseRecorder struct {
 	header     http.Header
 	body       *bytes.Buffer
+	stream     io.ReadCloser
 	statusCode int
 }
 
@@ -47,11 +49,13 @@ func (rr *ResponseRecorder) Clear() {
 	rr.header = make(http.Header)
 	rr.statusCode = http.StatusOK
 	rr.body = nil
+	rr.stream = nil
 }
 
 // ClearBody resets the body's content.
 func (rr *ResponseRecorder) ClearBody() {
 	rr.body = nil
+	rr.stream = nil
 }
 
 // ClearHeader resets the headers.
@@ -81,6 +85,8 @@ func (rr *ResponseRecorder) WriteHeader(statusCode int) {
 }
 
 // Result returns the response generated by the recorder.
+// If a streamed body was copied into the recorder, the response carries that stream as its body
+// and its content length is unknown.
 func (rr *ResponseRecorder) Result() *http.Response {
 	res := &http.Response{
 		Proto:      "HTTP/1.1",
@@ -90,6 +96,12 @@ func (rr *ResponseRecorder) Result() *http.Response {
 		Header:     rr.header,
 	}
 	res.Status = fmt.Sprintf("%03d %s", res.StatusCode, http.StatusText(res.StatusCode))
+	if rr.stream != nil {
+		res.Body = rr.stream
+		res.ContentLength = -1
+		rr.header.Del("Content-Length")
+		return res
+	}
 	if rr.body != nil {
 		res.Body = NewBodyReader(rr.body.Bytes())
 		res.ContentLength = int64(rr.body.Len())
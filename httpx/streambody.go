@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"io"
+	"sync"
+)
+
+// StreamBody is the body of a response that arrives in chunks, such as a response streamed over the bus.
+// The producer appends chunks as they arrive and the consumer reads them in order.
+// Unlike [io.Pipe], appending does not block until the chunk is read.
+type StreamBody struct {
+	mux    sync.Mutex
+	ready  chan struct{}
+	chunks [][]byte
+	err    error
+	closed bool
+}
+
+// NewStreamBody creates a new empty stream body.
+func NewStreamBody() *StreamBody {
+	return &StreamBody{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// Append queues a chunk to be read by the consumer.
+// Chunks appended after the stream was ended or closed are discarded.
+func (sb *StreamBody) Append(chunk []byte) {
+	if len(chunk) == 0 {
+		return
+	}
+	sb.mux.Lock()
+	if sb.err == nil && !sb.closed {
+		sb.chunks = append(sb.chunks, chunk)
+	}
+	sb.mux.Unlock()
+	sb.signal()
+}
+
+// End marks the end of the stream. The consumer receives err after reading all queued chunks,
+// or [io.EOF] if err is nil. Only the first call to End has effect.
+func (sb *StreamBody) End(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+	sb.mux.Lock()
+	if sb.err == nil {
+		sb.err = err
+	}
+	sb.mux.Unlock()
+	sb.signal()
+}
+
+// signal wakes up a pending read.
+func (sb *StreamBody) signal() {
+	select {
+	case sb.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Read reads the next available bytes of the stream, blocking until a chunk arrives or the stream ends.
+// It implements the [io.Reader] interface.
+func (sb *StreamBody) Read(p []byte) (n int, err error) {
+	for {
+		sb.mux.Lock()
+		if sb.closed {
+			sb.mux.Unlock()
+			return 0, io.ErrClosedPipe
+		}
+		if len(sb.chunks) > 0 {
+			n = copy(p, sb.chunks[0])
+			if n == len(sb.chunks[0]) {
+				sb.chunks[0] = nil
+				sb.chunks = sb.chunks[1:]
+			} else {
+				sb.chunks[0] = sb.chunks[0][n:]
+			}
+			sb.mux.Unlock()
+			return n, nil
+		}
+		if sb.err != nil {
+			err = sb.err
+			sb.mux.Unlock()
+			return 0, err
+		}
+		sb.mux.Unlock()
+		<-sb.ready
+	}
+}
+
+// Close discards any queued chunks and stops accepting new ones.
+// It implements the [io.Closer] interface.
+func (sb *StreamBody) Close() error {
+	sb.mux.Lock()
+	sb.closed = true
+	sb.chunks = nil
+	sb.mux.Unlock()
+	sb.signal()
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestHttpx_StreamBody(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	sb := NewStreamBody()
+	sb.Append([]byte("Hello"))
+	sb.Append([]byte(" "))
+
+	// Read returns the available bytes without waiting for more
+	buf := make([]byte, 3)
+	n, err := sb.Read(buf)
+	assert.Expect(n, 3, err, nil, string(buf[:n]), "Hel")
+	buf = make([]byte, 16)
+	n, err = sb.Read(buf)
+	assert.Expect(n, 2, err, nil, string(buf[:n]), "lo")
+
+	// Read blocks until more bytes arrive
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sb.Append([]byte("World"))
+		sb.End(nil)
+	}()
+	b, err := io.ReadAll(sb)
+	assert.NoError(err)
+	assert.Equal(" World", string(b))
+
+	// Appending after the end has no effect
+	sb.Append([]byte("!"))
+	n, err = sb.Read(buf)
+	assert.Expect(n, 0, err, io.EOF)
+}
+
+func TestHttpx_StreamBodyError(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	sb := NewStreamBody()
+	sb.Append([]byte("Hello"))
+	sb.End(errors.New("oops"))
+	b, err := io.ReadAll(sb)
+	assert.Equal("Hello", string(b))
+	assert.Error(err)
+
+	sb = NewStreamBody()
+	sb.Append([]byte("Hello"))
+	sb.Close()
+	_, err = sb.Read(make([]byte, 16))
+	assert.Equal(io.ErrClosedPipe, err)
+}
+
+func TestHttpx_CopyStreamBody(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	sb := NewStreamBody()
+	sb.Append([]byte("Hello"))
+	res := &http.Response{
+		StatusCode: http.StatusAccepted,
+		Header: http.Header{
+			"Content-Type":   []string{"text/plain"},
+			"Content-Length": []string{"5"},
+		},
+		Body:          sb,
+		ContentLength: -1,
+	}
+
+	// The stream passes through a recorder without being read
+	rr := NewResponseRecorder()
+	err := Copy(rr, res)
+	assert.NoError(err)
+	res = rr.Result()
+	assert.Expect(
+		res.StatusCode, http.StatusAccepted,
+		res.ContentLength, int64(-1),
+		res.Header.Get("Content-Type"), "text/plain",
+		res.Header.Get("Content-Length"), "",
+	)
+	assert.True(res.Body == sb)
+
+	// A flusher is flushed as the stream arrives
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sb.Append([]byte(" World"))
+		sb.End(nil)
+	}()
+	w := httptest.NewRecorder()
+	err = Copy(w, res)
+	assert.NoError(err)
+	assert.Expect(
+		w.Code, http.StatusAccepted,
+		w.Body.String(), "Hello World",
+		w.Flushed, true,
+		w.Header().Get("Content-Length"), "",
+	)
+}
@@ -33,6 +33,7 @@ type Document struct {
 	Paths      map[string]map[string]*Operation `json:"paths,omitzero"`
 	Components *Components                      `json:"components,omitzero"`
 	Tags       []*Tag                           `json:"tags,omitzero"`
+	XTickers   []*Ticker                        `json:"x-tickers,omitzero"`
 }
 
 // Tag groups a set of operations under a label, typically rendered as a collapsible section
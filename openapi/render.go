@@ -379,6 +379,7 @@ func Render(s *Service) *Document {
 		}
 		doc.Paths[path][strings.ToLower(pathMethod)] = op
 	}
+	doc.XTickers = s.Tickers
 	return doc
 }
 
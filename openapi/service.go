@@ -26,5 +26,6 @@ type Service struct {
 	Description string
 	Version     int
 	Endpoints   []*Endpoint
+	Tickers     []*Ticker
 	RemoteURI   string
 }
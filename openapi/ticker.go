@@ -0,0 +1,29 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import "time"
+
+// Ticker describes a recurring job of a microservice. Tickers are not endpoints and are
+// rendered in the x-tickers extension of the OpenAPI document rather than in its paths.
+type Ticker struct {
+	Name      string    `json:"name"`
+	Interval  string    `json:"interval,omitzero"`
+	Schedule  string    `json:"schedule,omitzero"`
+	Singleton bool      `json:"singleton,omitzero"`
+	NextRun   time.Time `json:"nextRun,omitzero"`
+}
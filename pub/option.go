@@ -378,3 +378,98 @@ func Timeout(timeout time.Duration) Option {
 		return nil
 	}
 }
+
+// Retry retries a unicast request that fails with one of the retryable status codes of the policy.
+// Attempts are spaced out with an exponential backoff and are limited by the time budget of the request.
+// Retries should only be enabled for idempotent requests, or for requests to endpoints that honor idempotency keys.
+// All attempts of a POST or PATCH request share an [IdempotencyKey].
+func Retry(policy RetryPolicy) Option {
+	return func(req *Request) error {
+		if policy.MaxAttempts <= 1 {
+			req.Retry = nil
+			return nil
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return errors.New("jitter must be between 0 and 1")
+		}
+		req.Retry = &policy
+		return nil
+	}
+}
+
+// Version pins a unicast request to the replicas of the microservice that run the given version.
+// Pinning overrides the weighted routing of requests among versions.
+// The request fails with a 404 error if no replica of the version is running.
+func Version(version int) Option {
+	return func(req *Request) error {
+		if version < 0 {
+			return errors.New("negative version '%d'", version)
+		}
+		req.Version = &version
+		return nil
+	}
+}
+
+// Cache serves a unicast GET request from the client-side response cache of the microservice
+// for up to the given duration. The responder may shorten the duration with the max-age directive
+// of the Cache-Control header of its response, require revalidation with no-cache, or prevent caching with no-store.
+// A cached response whose duration elapsed is revalidated with the responder using its ETag, if it has one.
+// Responses are cached per actor so that data gated by claims is never shared across actors.
+func Cache(ttl time.Duration) Option {
+	return func(req *Request) error {
+		if ttl < 0 {
+			ttl = 0
+		}
+		req.CacheTTL = ttl
+		return nil
+	}
+}
+
+// Coalesce collapses concurrent identical unicast requests made by the microservice into a single request.
+// Requests are identical if they have the same method, URL, actor and body.
+// The first request is sent over the bus, and its response is shared with the requests that arrive
+// while it is pending, all of which must set this option. Headers other than the actor are taken from the first request.
+// Coalescing is suitable for read-only requests that many goroutines make at once, for example when a hot key misses the cache.
+func Coalesce() Option {
+	return func(req *Request) error {
+		req.Coalesce = true
+		return nil
+	}
+}
+
+// Durable persists a multicast request in a stream, from which it is delivered at least once
+// to each of the durable subscribers of the endpoint, even those that are not running when the request is made.
+// Responses are not returned and the request completes once it is persisted.
+// Durable delivery requires NATS with JetStream enabled. Otherwise, the request is multicast as usual.
+func Durable() Option {
+	return func(req *Request) error {
+		req.Durable = true
+		return nil
+	}
+}
+
+// IdempotencyKey sets the Idempotency-Key header of the request.
+// An endpoint that honors idempotency keys executes the request only once,
+// and replays the first response to duplicate requests with the same key and actor.
+// Deduplication is best-effort across replicas of the endpoint: duplicates that arrive at different replicas
+// at the very same time may both be executed.
+// The key should be unique per operation, e.g. a random UUID generated when the operation is first attempted.
+// A retried POST or PATCH request that does not set a key is assigned one that is shared by all its attempts.
+func IdempotencyKey(key string) Option {
+	return Header("Idempotency-Key", key)
+}
+
+// Hedge re-publishes a unicast request if no response arrives within the given delay,
+// returning the first response to arrive and discarding the other.
+// Hedging reduces tail latency when the request is served by multiple replicas.
+// It is limited to requests with an idempotent method: GET, HEAD, OPTIONS, PUT or DELETE.
+// An [IdempotencyKey] does not qualify other methods because not all endpoints honor it.
+func Hedge(after time.Duration) Option {
+	return func(req *Request) error {
+		if after < 0 {
+			return errors.New("negative hedge delay")
+		}
+		req.Hedge = after
+		return nil
+	}
+}
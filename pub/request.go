@@ -36,6 +36,12 @@ type Request struct {
 	Multicast     bool
 	ContentLength int
 	Timeout       time.Duration
+	Retry         *RetryPolicy
+	Hedge         time.Duration
+	Version       *int
+	CacheTTL      time.Duration
+	Coalesce      bool
+	Durable       bool
 
 	queryArgs string
 }
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/microbus-io/errors"
@@ -212,6 +213,17 @@ func TestPub_Apply(t *testing.T) {
 	r.Apply(Header("Foo", "Bar"))
 	assert.Equal("Bar", r.Header.Get("Foo"))
 
+	r.Apply(Hedge(time.Second))
+	assert.Equal(time.Second, r.Hedge)
+	err = r.Apply(Hedge(-time.Second))
+	assert.Error(err)
+
+	r.Apply(Coalesce())
+	assert.True(r.Coalesce)
+
+	r.Apply(Durable())
+	assert.True(r.Durable)
+
 	actorJWT := signTestJWT(t, jwt.MapClaims{
 		"sub":   "foo@example.com",
 		"roles": []string{"a", "b", "c"},
@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// DefaultRetryOn is the set of status codes that are retried when a retry policy does not specify any.
+var DefaultRetryOn = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how a failed unicast request is retried.
+type RetryPolicy struct {
+	MaxAttempts int           // total number of attempts, including the first
+	Backoff     time.Duration // delay before the second attempt, doubled before each subsequent attempt
+	MaxBackoff  time.Duration // cap on the delay between attempts; zero means no cap
+	Jitter      float64       // fraction of the delay that is randomized, between 0 and 1
+	RetryOn     []int         // status codes that are retried; empty means DefaultRetryOn
+}
+
+// ShouldRetry indicates if a request that failed with the status code should be retried.
+func (p *RetryPolicy) ShouldRetry(statusCode int) bool {
+	if len(p.RetryOn) == 0 {
+		return slices.Contains(DefaultRetryOn, statusCode)
+	}
+	return slices.Contains(p.RetryOn, statusCode)
+}
+
+// Delay returns the delay before the given attempt, indexed starting at 1.
+// There is no delay before the first attempt.
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	if attempt <= 1 || p.Backoff <= 0 {
+		return 0
+	}
+	delay := p.Backoff
+	for i := 2; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		// Randomize within [delay*(1-jitter), delay]
+		delay -= time.Duration(float64(delay) * p.Jitter * rand.Float64())
+	}
+	return delay
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pub
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestPub_RetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	policy := RetryPolicy{
+		MaxAttempts: 6,
+		Backoff:     100 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+	assert.Expect(
+		policy.Delay(1), time.Duration(0),
+		policy.Delay(2), 100*time.Millisecond,
+		policy.Delay(3), 200*time.Millisecond,
+		policy.Delay(4), 400*time.Millisecond,
+		policy.Delay(5), 800*time.Millisecond,
+		policy.Delay(6), time.Second,
+		policy.Delay(100), time.Second,
+	)
+
+	// Jitter shortens the delay by up to the fraction
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.Delay(3)
+		assert.True(delay > 100*time.Millisecond && delay <= 200*time.Millisecond, "%v", delay)
+	}
+}
+
+func TestPub_RetryPolicyShouldRetry(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	policy := RetryPolicy{MaxAttempts: 3}
+	assert.Expect(
+		policy.ShouldRetry(http.StatusServiceUnavailable), true,
+		policy.ShouldRetry(http.StatusTooManyRequests), true,
+		policy.ShouldRetry(http.StatusInternalServerError), false,
+		policy.ShouldRetry(http.StatusOK), false,
+	)
+
+	policy.RetryOn = []int{http.StatusInternalServerError}
+	assert.Expect(
+		policy.ShouldRetry(http.StatusServiceUnavailable), false,
+		policy.ShouldRetry(http.StatusInternalServerError), true,
+	)
+}
+
+func TestPub_RetryOption(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	r, err := NewRequest(Retry(RetryPolicy{MaxAttempts: 3}))
+	if assert.NoError(err) && assert.NotNil(r.Retry) {
+		assert.Equal(3, r.Retry.MaxAttempts)
+	}
+
+	// A single attempt disables retries
+	err = r.Apply(Retry(RetryPolicy{MaxAttempts: 1}))
+	assert.NoError(err)
+	assert.Nil(r.Retry)
+
+	_, err = NewRequest(Retry(RetryPolicy{MaxAttempts: 3, Jitter: 2}))
+	assert.Error(err)
+}
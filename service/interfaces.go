@@ -27,6 +27,7 @@ import (
 	"github.com/microbus-io/fabric/cfg"
 	"github.com/microbus-io/fabric/pub"
 	"github.com/microbus-io/fabric/sub"
+	"github.com/microbus-io/fabric/tick"
 	"github.com/microbus-io/fabric/trc"
 )
 
@@ -90,6 +91,10 @@ type StartupHandler func(ctx context.Context) error
 // ShutdownHandler handles the OnShutdown callback.
 type ShutdownHandler func(ctx context.Context) error
 
+// HealthCheckHandler handles the OnHealthCheck callback.
+// It returns the outcome of each named check, with a nil error indicating a healthy check.
+type HealthCheckHandler func(ctx context.Context) (checks map[string]error)
+
 // StarterStopper are the lifecycle actions of the microservice.
 type StarterStopper interface {
 	Startup(ctx context.Context) (err error)
@@ -103,6 +108,7 @@ type StarterStopper interface {
 
 	SetOnStartup(handler StartupHandler) error
 	SetOnShutdown(handler ShutdownHandler) error
+	SetOnHealthCheck(handler HealthCheckHandler) error
 }
 
 // Identifier are the properties used to uniquely identify and address the microservice.
@@ -140,7 +146,7 @@ type TickerHandler func(ctx context.Context) error
 
 // Ticker are actions used to schedule recurring jobs.
 type Ticker interface {
-	StartTicker(name string, interval time.Duration, handler TickerHandler) error
+	StartTicker(name string, interval time.Duration, handler TickerHandler, options ...tick.Option) error
 }
 
 // Executor are actions for running jobs in Go routines.
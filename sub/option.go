@@ -103,6 +103,78 @@ func TimeBudget(d time.Duration) Option {
 	}
 }
 
+// MaxConcurrency limits the number of requests that the subscription processes concurrently.
+// Requests in excess of the limit wait in a queue of the size set by [WaitQueue], if any.
+// When the queue is also full, the subscription is saturated and sheds the request:
+// a load-balanced subscription responds with a 503 service unavailable error and a Retry-After header,
+// whereas a subscription with no queue declines to ack the request.
+// A zero or negative limit means no limit.
+func MaxConcurrency(n int) Option {
+	return func(sub *Subscription) error {
+		if n < 0 {
+			n = 0
+		}
+		sub.MaxConcurrency = n
+		return nil
+	}
+}
+
+// WaitQueue sets the number of requests that may wait for the processing of others to complete
+// when the subscription reaches its [MaxConcurrency] limit.
+// Waiting requests are processed in order of arrival, subject to their time budget.
+// A zero or negative size means requests in excess of the limit are shed immediately.
+func WaitQueue(n int) Option {
+	return func(sub *Subscription) error {
+		if n < 0 {
+			n = 0
+		}
+		sub.WaitQueue = n
+		return nil
+	}
+}
+
+// IdempotencyKeys deduplicates requests that carry an Idempotency-Key header.
+// The first successful response to a request is retained for the given duration and is replayed
+// to duplicate requests with the same key that are made by the same actor to the same endpoint.
+// Duplicates that arrive while the first request is still processing wait for its response.
+// This is guaranteed among duplicates that arrive at the same replica, but is best-effort across replicas
+// because duplicates that arrive at different replicas at the very same time may both be executed.
+// Errors are not retained, so that a request that failed may be retried.
+// A zero or negative duration disables deduplication.
+func IdempotencyKeys(ttl time.Duration) Option {
+	return func(sub *Subscription) error {
+		if ttl < 0 {
+			ttl = 0
+		}
+		sub.IdempotencyTTL = ttl
+		return nil
+	}
+}
+
+// Durable consumes the events fired to the endpoint with [pub.Durable] from their stream,
+// rather than only while the microservice is running. Each event is delivered at least once
+// to one of the replicas that share the queue of the subscription, and is redelivered until its handler succeeds.
+// An event that exhausts its [MaxDeliveries] is published to a dead-letter subject.
+// Durable delivery requires NATS with JetStream enabled. Otherwise, the subscription behaves as usual.
+func Durable() Option {
+	return func(sub *Subscription) error {
+		sub.Durable = true
+		return nil
+	}
+}
+
+// MaxDeliveries sets the number of times a [Durable] event is delivered to the subscription before it is dead-lettered.
+// A zero or negative number means the framework default of 5.
+func MaxDeliveries(n int) Option {
+	return func(sub *Subscription) error {
+		if n < 0 {
+			n = 0
+		}
+		sub.MaxDeliveries = n
+		return nil
+	}
+}
+
 // Method overrides the default "ANY" method for a Listen subscription.
 // Accepts one of the recognized HTTP methods (GET, HEAD, POST, PUT, DELETE, CONNECT, OPTIONS,
 // TRACE, PATCH) or "ANY" to match any method. Matching is case-insensitive; the value is
@@ -72,6 +72,11 @@ type Subscription struct {
 	specPath       string
 	RequiredClaims string
 	TimeBudget     time.Duration
+	MaxConcurrency int
+	WaitQueue      int
+	IdempotencyTTL time.Duration
+	Durable        bool
+	MaxDeliveries  int
 	Type           string
 	Inputs         any
 	Outputs        any
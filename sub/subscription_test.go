@@ -19,6 +19,7 @@ package sub
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/microbus-io/testarossa"
 )
@@ -133,6 +134,30 @@ func TestSub_Apply(t *testing.T) {
 
 	err = s.Apply(Queue("$$$"))
 	assert.Error(err)
+
+	s.Apply(MaxConcurrency(4), WaitQueue(8))
+	assert.Expect(
+		s.MaxConcurrency, 4,
+		s.WaitQueue, 8,
+	)
+	s.Apply(MaxConcurrency(-1), WaitQueue(-1))
+	assert.Expect(
+		s.MaxConcurrency, 0,
+		s.WaitQueue, 0,
+	)
+
+	s.Apply(IdempotencyKeys(time.Hour))
+	assert.Equal(time.Hour, s.IdempotencyTTL)
+	s.Apply(IdempotencyKeys(-time.Hour))
+	assert.Zero(s.IdempotencyTTL)
+
+	s.Apply(Durable(), MaxDeliveries(3))
+	assert.Expect(
+		s.Durable, true,
+		s.MaxDeliveries, 3,
+	)
+	s.Apply(MaxDeliveries(-1))
+	assert.Zero(s.MaxDeliveries)
 }
 
 func TestSub_Canonical(t *testing.T) {
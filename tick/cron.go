@@ -0,0 +1,269 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tick
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/microbus-io/errors"
+)
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+	descriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Cron is a schedule parsed from a cron expression.
+//
+// The expression consists of five space-separated fields: minute (0-59), hour (0-23), day of month (1-31),
+// month (1-12 or JAN-DEC) and day of week (0-7 or SUN-SAT, where both 0 and 7 are Sunday).
+// Each field is a comma-separated list of values, ranges (1-5), steps (*/15 or 0-30/10) or the wildcard *.
+// If both the day of month and the day of week are restricted, a day that matches either of them is a match.
+// The descriptors @yearly, @monthly, @weekly, @daily and @hourly may be used instead of the five fields.
+//
+// The schedule is evaluated in UTC unless the expression is prefixed with a time zone, e.g.
+//
+//	CRON_TZ=America/New_York 0 2 * * MON-FRI
+//
+// A wall clock time that is skipped by a daylight saving transition fires at the moment of the transition.
+// A wall clock time that is repeated by a daylight saving transition fires only on its first occurrence.
+type Cron struct {
+	expr    string
+	loc     *time.Location
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+// ParseCron parses a cron expression.
+func ParseCron(expr string) (*Cron, error) {
+	c := &Cron{
+		expr: strings.TrimSpace(expr),
+		loc:  time.UTC,
+	}
+	fields := strings.Fields(c.expr)
+	if len(fields) > 0 {
+		tz, ok := strings.CutPrefix(fields[0], "CRON_TZ=")
+		if !ok {
+			tz, ok = strings.CutPrefix(fields[0], "TZ=")
+		}
+		if ok {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				return nil, errors.New("invalid time zone '%s' in cron expression '%s'", tz, expr)
+			}
+			c.loc = loc
+			fields = fields[1:]
+		}
+	}
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		d, ok := descriptors[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, errors.New("unknown descriptor '%s' in cron expression '%s'", fields[0], expr)
+		}
+		fields = strings.Fields(d)
+	}
+	if len(fields) != 5 {
+		return nil, errors.New("cron expression '%s' must have 5 fields", expr)
+	}
+	var err error
+	c.minute, _, err = parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.hour, _, err = parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.dom, c.domStar, err = parseCronField(fields[2], 1, 31, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.month, _, err = parseCronField(fields[3], 1, 12, monthNames)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.dow, c.dowStar, err = parseCronField(fields[4], 0, 7, dayNames)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if c.dow&(1<<7) != 0 {
+		// Both 0 and 7 are Sunday
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	return c, nil
+}
+
+// parseCronField parses a single field of a cron expression into a bit set of its values.
+// It also indicates if the field is unrestricted.
+func parseCronField(field string, lo int, hi int, names map[string]int) (bits uint64, star bool, err error) {
+	star = strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+	for part := range strings.SplitSeq(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, false, errors.New("invalid step in cron field '%s'", field)
+			}
+		}
+		var from, to int
+		switch {
+		case rng == "*" || rng == "?":
+			from, to = lo, hi
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			from, err = parseCronValue(a, names)
+			if err != nil {
+				return 0, false, errors.New("invalid value in cron field '%s'", field)
+			}
+			to, err = parseCronValue(b, names)
+			if err != nil {
+				return 0, false, errors.New("invalid value in cron field '%s'", field)
+			}
+		default:
+			from, err = parseCronValue(rng, names)
+			if err != nil {
+				return 0, false, errors.New("invalid value in cron field '%s'", field)
+			}
+			to = from
+			if hasStep {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return 0, false, errors.New("value out of range %d-%d in cron field '%s'", lo, hi, field)
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, star, nil
+}
+
+// parseCronValue parses a numeric or named value of a cron field.
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// String returns the cron expression of the schedule.
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Location returns the time zone in which the schedule is evaluated.
+func (c *Cron) Location() *time.Location {
+	return c.loc
+}
+
+// Next returns the next time after the given time that matches the schedule,
+// or the zero time if no such time exists within the next five years.
+func (c *Cron) Next(after time.Time) time.Time {
+	// Iterate over the wall clock, using UTC as a calendar without daylight saving transitions
+	wall := wallClock(after.In(c.loc)).Truncate(time.Minute).Add(time.Minute)
+	limit := wall.AddDate(5, 0, 0)
+	for wall.Before(limit) {
+		if c.month&(1<<uint(wall.Month())) == 0 {
+			wall = time.Date(wall.Year(), wall.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.matchDay(wall) {
+			wall = time.Date(wall.Year(), wall.Month(), wall.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if c.hour&(1<<uint(wall.Hour())) == 0 {
+			wall = wall.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if c.minute&(1<<uint(wall.Minute())) == 0 {
+			wall = wall.Add(time.Minute)
+			continue
+		}
+		t := c.resolve(wall)
+		if t.After(after) {
+			return t
+		}
+		wall = wall.Add(time.Minute)
+	}
+	return time.Time{}
+}
+
+// matchDay indicates if the day of the wall clock time matches the schedule.
+func (c *Cron) matchDay(wall time.Time) bool {
+	domMatch := c.dom&(1<<uint(wall.Day())) != 0
+	dowMatch := c.dow&(1<<uint(wall.Weekday())) != 0
+	if !c.domStar && !c.dowStar {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// resolve converts a wall clock time to an instant in the time zone of the schedule.
+// A wall clock time that is skipped by a daylight saving transition resolves to the instant of the transition.
+// A wall clock time that is repeated by a daylight saving transition resolves to its first occurrence.
+func (c *Cron) resolve(wall time.Time) time.Time {
+	// The offsets in effect on either side of a transition near the wall clock time
+	_, offBefore := wall.Add(-24 * time.Hour).In(c.loc).Zone()
+	_, offAfter := wall.Add(24 * time.Hour).In(c.loc).Zone()
+	earlier := wall.Add(-time.Duration(max(offBefore, offAfter)) * time.Second)
+	later := wall.Add(-time.Duration(min(offBefore, offAfter)) * time.Second)
+	if wallClock(earlier.In(c.loc)).Equal(wall) {
+		return earlier
+	}
+	if wallClock(later.In(c.loc)).Equal(wall) {
+		return later
+	}
+	// Skipped wall clock time: binary search for the instant of the transition
+	_, offEarlier := earlier.In(c.loc).Zone()
+	for later.Sub(earlier) > time.Second {
+		mid := earlier.Add(later.Sub(earlier) / 2)
+		if _, off := mid.In(c.loc).Zone(); off == offEarlier {
+			earlier = mid
+		} else {
+			later = mid
+		}
+	}
+	return later.Truncate(time.Second)
+}
+
+// wallClock returns the wall clock time of t as a UTC time.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tick
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestTick_ParseCron(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	valid := []string{
+		"* * * * *",
+		"0 2 * * MON-FRI",
+		"*/15 0-6,18-23 1,15 JAN-jun ?",
+		"5/10 * * * 7",
+		"CRON_TZ=America/New_York 0 2 * * 1-5",
+		"TZ=Europe/London @daily",
+		"@hourly",
+	}
+	for _, expr := range valid {
+		_, err := ParseCron(expr)
+		assert.NoError(err, "%s", expr)
+	}
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"X * * * *",
+		"@sometimes",
+		"CRON_TZ=Mars/Olympus 0 0 * * *",
+	}
+	for _, expr := range invalid {
+		_, err := ParseCron(expr)
+		assert.Error(err, "%s", expr)
+	}
+}
+
+func TestTick_CronNext(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	at := func(s string) time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		assert.NoError(err)
+		return t
+	}
+	testCases := []struct {
+		expr  string
+		after string
+		next  string
+	}{
+		{"* * * * *", "2026-05-04T10:20:30Z", "2026-05-04T10:21:00Z"},
+		{"*/15 * * * *", "2026-05-04T10:15:00Z", "2026-05-04T10:30:00Z"},
+		{"0 2 * * MON-FRI", "2026-05-08T03:00:00Z", "2026-05-11T02:00:00Z"}, // Friday to Monday
+		{"0 0 1 * *", "2026-12-15T00:00:00Z", "2027-01-01T00:00:00Z"},
+		{"0 0 29 2 *", "2026-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"0 0 13 * 5", "2026-05-04T00:00:00Z", "2026-05-08T00:00:00Z"}, // Friday or the 13th
+		{"0 0 * * 7", "2026-05-04T00:00:00Z", "2026-05-10T00:00:00Z"},  // 7 is Sunday
+		{"@weekly", "2026-05-04T00:00:00Z", "2026-05-10T00:00:00Z"},
+		{"CRON_TZ=America/New_York 0 2 * * MON-FRI", "2026-05-04T00:00:00Z", "2026-05-04T06:00:00Z"},
+		{"CRON_TZ=America/New_York 0 2 * * MON-FRI", "2026-12-04T00:00:00Z", "2026-12-04T07:00:00Z"},
+		// Skipped by the transition to daylight saving time on 2026-03-08 fires at the transition
+		{"CRON_TZ=America/New_York 30 2 * * *", "2026-03-07T08:00:00Z", "2026-03-08T07:00:00Z"},
+		{"CRON_TZ=America/New_York 30 2 * * *", "2026-03-08T07:00:00Z", "2026-03-09T06:30:00Z"},
+		{"CRON_TZ=America/New_York 30 3 * * *", "2026-03-07T09:00:00Z", "2026-03-08T07:30:00Z"},
+		// Repeated by the transition from daylight saving time on 2026-11-01 fires once
+		{"CRON_TZ=America/New_York 30 1 * * *", "2026-10-31T06:00:00Z", "2026-11-01T05:30:00Z"},
+		{"CRON_TZ=America/New_York 30 1 * * *", "2026-11-01T05:30:00Z", "2026-11-02T06:30:00Z"},
+		{"CRON_TZ=America/New_York 0 * * * *", "2026-11-01T05:00:00Z", "2026-11-01T07:00:00Z"},
+	}
+	for _, tc := range testCases {
+		c, err := ParseCron(tc.expr)
+		if assert.NoError(err) {
+			assert.Equal(at(tc.next), c.Next(at(tc.after)).UTC(), "%s after %s", tc.expr, tc.after)
+		}
+	}
+
+	// A schedule that never matches
+	c, err := ParseCron("0 0 31 2 *")
+	if assert.NoError(err) {
+		assert.True(c.Next(time.Now()).IsZero())
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tick
+
+import (
+	"github.com/microbus-io/errors"
+)
+
+// Option is used to construct a ticker in Connector.StartTicker
+type Option func(t *Ticker) error
+
+// Singleton runs the ticker on a single instance of the microservice rather than on all of them.
+// The instance is the leader elected among all instances that share the same hostname.
+// If the leader shuts down or stops responding, another instance takes over.
+func Singleton() Option {
+	return func(t *Ticker) error {
+		t.Singleton = true
+		return nil
+	}
+}
+
+// Schedule runs the ticker on a cron schedule rather than at a fixed interval.
+// The schedule is evaluated in UTC unless the expression is prefixed with a time zone,
+// e.g. "CRON_TZ=America/New_York 0 2 * * MON-FRI" runs at 2am New York time on weekdays.
+func Schedule(expr string) Option {
+	return func(t *Ticker) error {
+		cron, err := ParseCron(expr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		t.Schedule = cron
+		return nil
+	}
+}
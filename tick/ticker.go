@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tick
+
+import (
+	"time"
+
+	"github.com/microbus-io/errors"
+)
+
+// Ticker is a recurring job of a microservice.
+// Although technically public, it is used internally and should not be constructed by microservices directly.
+type Ticker struct {
+	Name      string
+	Interval  time.Duration
+	Schedule  *Cron
+	Singleton bool
+}
+
+// NewTicker creates a new ticker.
+func NewTicker(name string, interval time.Duration, options ...Option) (*Ticker, error) {
+	if name == "" {
+		return nil, errors.New("ticker name is required")
+	}
+	t := &Ticker{
+		Name:     name,
+		Interval: interval,
+	}
+	err := t.Apply(options...)
+	if err != nil {
+		return nil, err
+	}
+	if t.Schedule != nil {
+		if t.Interval != 0 {
+			return nil, errors.New("interval and schedule are mutually exclusive")
+		}
+	} else if t.Interval <= 0 {
+		return nil, errors.New("non-positive interval '%v'", t.Interval)
+	}
+	return t, nil
+}
+
+// Apply the provided options to the ticker.
+func (t *Ticker) Apply(options ...Option) error {
+	for _, opt := range options {
+		err := opt(t)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tick
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microbus-io/testarossa"
+)
+
+func TestTick_NewTicker(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	tkr, err := NewTicker("Job", time.Minute)
+	if assert.NoError(err) {
+		assert.Expect(
+			tkr.Name, "Job",
+			tkr.Interval, time.Minute,
+			tkr.Singleton, false,
+		)
+	}
+
+	tkr, err = NewTicker("Job", time.Minute, Singleton())
+	if assert.NoError(err) {
+		assert.True(tkr.Singleton)
+	}
+
+	tkr, err = NewTicker("Job", 0, Schedule("0 2 * * *"))
+	if assert.NoError(err) {
+		assert.Expect(
+			tkr.Interval, time.Duration(0),
+			tkr.Schedule.String(), "0 2 * * *",
+		)
+	}
+	_, err = NewTicker("Job", time.Minute, Schedule("0 2 * * *"))
+	assert.Error(err)
+	_, err = NewTicker("Job", 0, Schedule("0 2 * *"))
+	assert.Error(err)
+	_, err = NewTicker("", time.Minute)
+	assert.Error(err)
+	_, err = NewTicker("Job", 0)
+	assert.Error(err)
+}
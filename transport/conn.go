@@ -32,6 +32,7 @@ import (
 	"github.com/microbus-io/fabric/env"
 	"github.com/microbus-io/fabric/mem"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 var (
@@ -45,8 +46,21 @@ type refCountedNATSConn struct {
 	*nats.Conn
 	refCount int
 	cacheKey string
+	js       jetstream.JetStream
+	jsErr    error
+	jsOnce   sync.Once
+	jsProbe  sync.Mutex
+	jsState  int       // One of jsUnknown, jsEnabled or jsDisabled
+	jsRetry  time.Time // Time after which an inconclusive probe of JetStream is retried
 }
 
+// Availability states of JetStream on a NATS connection.
+const (
+	jsUnknown = iota
+	jsEnabled
+	jsDisabled
+)
+
 // Logger is used by the transport to log messages in the caller's context.
 type Logger interface {
 	LogInfo(ctx context.Context, msg string, args ...any)
@@ -56,9 +70,13 @@ type Logger interface {
 // Conn abstracts the connection to the transport.
 type Conn struct {
 	natsConn            atomic.Pointer[refCountedNATSConn]
+	meshNode            atomic.Pointer[meshNode]
+	meshLatency         time.Duration
 	shortCircuitEnabled atomic.Bool
+	natsURL             string
 	head                *Subscription
 	mux                 sync.Mutex
+	streams             sync.Map // Names of the streams ensured by the connection
 }
 
 // resolveArtifact returns the path of an auth artifact in CWD: the per-service
@@ -76,16 +94,45 @@ func resolveArtifact(hostname, artifact string) string {
 	return ""
 }
 
+// SetNATS sets the URL of the NATS cluster to connect to, overriding the MICROBUS_NATS env var.
+// A connection to an explicit NATS URL uses neither the mesh nor the short-circuit,
+// so that its traffic is not mixed with that of other connections in the same process.
+// It must be called before the transport is opened.
+func (c *Conn) SetNATS(natsURL string) {
+	c.natsURL = natsURL
+}
+
 // Open opens the transport. hostname drives per-service auth-artifact lookup (pass "" to skip).
 func (c *Conn) Open(ctx context.Context, hostname string, logger Logger) error {
-	if v := env.Get("MICROBUS_SHORT_CIRCUIT"); v == "0" || strings.EqualFold(v, "false") {
+	if v := env.Get("MICROBUS_SHORT_CIRCUIT"); v == "0" || strings.EqualFold(v, "false") || c.natsURL != "" {
 		c.shortCircuitEnabled.Store(false)
 	} else {
 		c.shortCircuitEnabled.Store(true)
 	}
 
+	// Mesh
+	if listen := env.Get("MICROBUS_MESH"); listen != "" && c.natsURL == "" {
+		c.meshLatency = meshDefaultLatency
+		if v := env.Get("MICROBUS_MESH_LATENCY"); v != "" {
+			latency, err := time.ParseDuration(v)
+			if err != nil || latency <= 0 {
+				return errors.New("invalid mesh latency '%s'", v, http.StatusBadRequest)
+			}
+			c.meshLatency = latency
+		}
+		node, err := openMesh(ctx, listen, env.Get("MICROBUS_MESH_PEERS"), env.Get("MICROBUS_MESH_MULTICAST"), logger)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.meshNode.Store(node)
+		return nil
+	}
+
 	// URL
-	u := env.Get("MICROBUS_NATS")
+	u := c.natsURL
+	if u == "" {
+		u = env.Get("MICROBUS_NATS")
+	}
 	if u == "" && !c.shortCircuitEnabled.Load() {
 		u = "nats://127.0.0.1:4222"
 	}
@@ -241,7 +288,7 @@ func (c *Conn) Open(ctx context.Context, hostname string, logger Logger) error {
 }
 
 // Close closes the transport.
-// It closes the NATS connection, if appropriate.
+// It closes the NATS connection or the mesh node, if appropriate.
 func (c *Conn) Close() error {
 	// Lingering subscriptions
 	for {
@@ -268,6 +315,11 @@ func (c *Conn) Close() error {
 			c.natsConn.Store(nil)
 		}
 	}
+	// Leave the mesh
+	node := c.meshNode.Swap(nil)
+	if node != nil {
+		node.release()
+	}
 	// Disable short circuit
 	c.shortCircuitEnabled.Store(false)
 	return nil
@@ -287,11 +339,23 @@ func (c *Conn) MaxPayload() int64 {
 // Publish sends data to a subject, allowing for multiple recipients.
 func (c *Conn) Publish(subject string, httpReq *http.Request) (err error) {
 	natsConn := c.natsConn.Load()
+	meshNode := c.meshNode.Load()
 	shortCircuitEnabled := c.shortCircuitEnabled.Load()
-	if !shortCircuitEnabled && natsConn == nil {
+	if !shortCircuitEnabled && natsConn == nil && meshNode == nil {
 		return errors.New("no transport")
 	}
 
+	if meshNode != nil {
+		// The mesh reaches local subscribers too
+		buf := bytes.NewBuffer(make([]byte, 0, 1<<10+1+int(httpReq.ContentLength)))
+		err = httpReq.WriteProxy(buf)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		meshNode.deliver(subject, buf.Bytes())
+		return nil
+	}
+
 	if natsConn != nil {
 		sz := 1<<10 + 1 + int(httpReq.ContentLength) // 2KB block minimum
 		block := mem.Alloc(sz)
@@ -308,7 +372,7 @@ func (c *Conn) Publish(subject string, httpReq *http.Request) (err error) {
 		return nil
 	}
 
-	// Use short-circuit for multicast only if NATS is disabled, because all subscribers, not just local ones, must be reached
+	// Use short-circuit for multicast only if NATS and the mesh are disabled, because all subscribers, not just local ones, must be reached
 	if shortCircuitEnabled {
 		_, err = c.deliverWithShortCircuit(subject, &Msg{Subject: subject, Request: httpReq})
 		if err != nil {
@@ -323,8 +387,9 @@ func (c *Conn) Publish(subject string, httpReq *http.Request) (err error) {
 // Request sends data to a subject, targeting only a single recipient.
 func (c *Conn) Request(subject string, httpReq *http.Request) (err error) {
 	natsConn := c.natsConn.Load()
+	meshNode := c.meshNode.Load()
 	shortCircuitEnabled := c.shortCircuitEnabled.Load()
-	if !shortCircuitEnabled && natsConn == nil {
+	if !shortCircuitEnabled && natsConn == nil && meshNode == nil {
 		return errors.New("no transport")
 	}
 
@@ -337,7 +402,18 @@ func (c *Conn) Request(subject string, httpReq *http.Request) (err error) {
 		if ok {
 			return nil
 		}
-		// If !ok, try with NATS
+		// If !ok, try with NATS or the mesh
+	}
+
+	// Go over the mesh
+	if meshNode != nil {
+		buf := bytes.NewBuffer(make([]byte, 0, 1<<10+1+int(httpReq.ContentLength)))
+		err = httpReq.WriteProxy(buf)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		meshNode.deliver(subject, buf.Bytes())
+		return nil
 	}
 
 	// Go over NATS
@@ -363,8 +439,9 @@ func (c *Conn) Request(subject string, httpReq *http.Request) (err error) {
 // Response sends a response to a subject, targeting only a single recipient.
 func (c *Conn) Response(subject string, httpRes *http.Response) (err error) {
 	natsConn := c.natsConn.Load()
+	meshNode := c.meshNode.Load()
 	shortCircuitEnabled := c.shortCircuitEnabled.Load()
-	if !shortCircuitEnabled && natsConn == nil {
+	if !shortCircuitEnabled && natsConn == nil && meshNode == nil {
 		return errors.New("no transport")
 	}
 
@@ -377,7 +454,18 @@ func (c *Conn) Response(subject string, httpRes *http.Response) (err error) {
 		if ok {
 			return nil
 		}
-		// If !ok, try with NATS
+		// If !ok, try with NATS or the mesh
+	}
+
+	// Go over the mesh
+	if meshNode != nil {
+		buf := bytes.NewBuffer(make([]byte, 0, 1<<10+1+int(httpRes.ContentLength)))
+		err = httpRes.Write(buf)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		meshNode.deliver(subject, buf.Bytes())
+		return nil
 	}
 
 	// Go over NATS
@@ -464,6 +552,9 @@ func (c *Conn) QueueSubscribe(subject string, queue string, handler MsgHandler)
 		}
 		sub.natsSub.SetPendingLimits(-1, -1)
 	}
+	if meshNode := c.meshNode.Load(); meshNode != nil {
+		sub.meshUnsub = meshNode.subscribe(subject, queue, handler)
+	}
 
 	if c.shortCircuitEnabled.Load() {
 		sub.shortCircuitUnsub = shortCircuit.Sub(subject, queue, handler)
@@ -503,6 +594,9 @@ func (c *Conn) Subscribe(subject string, handler MsgHandler) (sub *Subscription,
 		}
 		sub.natsSub.SetPendingLimits(-1, -1)
 	}
+	if meshNode := c.meshNode.Load(); meshNode != nil {
+		sub.meshUnsub = meshNode.subscribe(subject, "", handler)
+	}
 
 	if c.shortCircuitEnabled.Load() {
 		sub.shortCircuitUnsub = shortCircuit.Sub(subject, "", handler)
@@ -521,11 +615,12 @@ func (c *Conn) Subscribe(subject string, handler MsgHandler) (sub *Subscription,
 	return sub, nil
 }
 
-// WaitForSub gives a bit of time for the subscription to be registered with NATS.
-// It is a no op if NATS is not enabled.
+// WaitForSub gives a bit of time for the subscription to be registered with NATS or propagated to the peers of the mesh.
+// It is a no op if neither NATS nor the mesh are enabled.
 func (c *Conn) WaitForSub() {
 	natsConn := c.natsConn.Load()
-	if natsConn != nil {
+	meshNode := c.meshNode.Load()
+	if natsConn != nil || (meshNode != nil && meshNode.hasPeers()) {
 		time.Sleep(20 * time.Millisecond)
 	}
 }
@@ -543,6 +638,14 @@ func (c *Conn) unsubscribe(sub *Subscription) (err error) {
 		}
 		sub.natsSub = nil
 	}
+	if sub.consumeCtx != nil {
+		sub.consumeCtx.Stop()
+		sub.consumeCtx = nil
+	}
+	if sub.meshUnsub != nil {
+		sub.meshUnsub()
+		sub.meshUnsub = nil
+	}
 	shortCircuitUnsub := sub.shortCircuitUnsub
 	if shortCircuitUnsub != nil {
 		shortCircuitUnsub()
@@ -582,6 +685,9 @@ func (c *Conn) Latency() time.Duration {
 			roundTo := time.Duration(100)
 			return (rtt*2 + (roundTo/2+roundTo-1)*time.Millisecond) / roundTo / time.Millisecond * roundTo * time.Millisecond
 		}
+	} else if c.meshNode.Load() != nil {
+		// Peer-to-peer mesh
+		return c.meshLatency
 	} else if c.shortCircuitEnabled.Load() {
 		// Short circuit only
 		return time.Millisecond * 10
@@ -199,3 +199,18 @@ func TestTransport_ResolveArtifact(t *testing.T) {
 		assert.Equal(c.want, got, fmt.Sprintf("hostname=%q artifact=%q", c.hostname, c.artifact))
 	}
 }
+
+func TestTransport_SetNATS(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	assert := testarossa.For(t)
+
+	// An explicit NATS URL is used rather than the short-circuit
+	var c Conn
+	c.SetNATS("nats://127.0.0.1:1")
+	err := c.Open(ctx, "", nil)
+	if !assert.Error(err) {
+		c.Close()
+	}
+	assert.False(c.shortCircuitEnabled.Load())
+}
@@ -0,0 +1,256 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/mem"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// DurableMaxAge is the duration that a durable message is retained in its stream
+// if it is not acknowledged by all the consumers that are interested in it.
+const DurableMaxAge = 7 * 24 * time.Hour
+
+// DurableMaxConcurrency is the default number of messages that a durable subscription processes concurrently.
+const DurableMaxConcurrency = 64
+
+// Headers set on dead-lettered messages.
+const (
+	HeaderDeadLetterError      = "Microbus-Dead-Letter-Error"
+	HeaderDeadLetterDeliveries = "Microbus-Dead-Letter-Deliveries"
+	HeaderDeadLetterConsumer   = "Microbus-Dead-Letter-Consumer"
+)
+
+// DurableMsgHandler processes a durably delivered message.
+// Returning an error causes the message to be redelivered.
+type DurableMsgHandler = func(msg *Msg) error
+
+// DurableConfig configures a durable subscription to a stream.
+type DurableConfig struct {
+	Stream            string        // Name of the stream
+	Subject           string        // Subject captured by the stream, may contain wildcards
+	Consumer          string        // Name of the consumer. Subscriptions with the same consumer name share the messages
+	MaxDeliver        int           // Number of deliveries of a message before it is dead-lettered
+	AckWait           time.Duration // Duration after which an unacknowledged message is redelivered
+	InactiveThreshold time.Duration // Duration of inactivity after which the consumer is deleted, or zero to retain it
+	DeadLetterStream  string        // Name of the stream that retains dead letters
+	DeadLetterSubject string        // Subject to which messages are published after their deliveries are exhausted
+	MaxConcurrency    int           // Number of messages processed concurrently, or zero for DurableMaxConcurrency
+}
+
+// DurableEnabled indicates if durable messaging is available.
+// Durable messaging requires a NATS connection to a server with JetStream enabled.
+// The server is probed once per connection and the result is cached.
+// A probe that fails for a reason other than JetStream not being enabled is retried after a minute.
+func (c *Conn) DurableEnabled() bool {
+	natsConn := c.natsConn.Load()
+	if natsConn == nil {
+		return false
+	}
+	natsConn.jsProbe.Lock()
+	defer natsConn.jsProbe.Unlock()
+	if natsConn.jsState != jsUnknown {
+		return natsConn.jsState == jsEnabled
+	}
+	if time.Now().Before(natsConn.jsRetry) {
+		return false
+	}
+	js, err := c.jetStream()
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		_, err = js.AccountInfo(ctx)
+		cancel()
+	}
+	switch {
+	case err == nil:
+		natsConn.jsState = jsEnabled
+	case errors.Is(err, jetstream.ErrJetStreamNotEnabled), errors.Is(err, jetstream.ErrJetStreamNotEnabledForAccount):
+		natsConn.jsState = jsDisabled
+	default:
+		natsConn.jsRetry = time.Now().Add(time.Minute)
+	}
+	return natsConn.jsState == jsEnabled
+}
+
+// jetStream returns the JetStream context of the NATS connection.
+func (c *Conn) jetStream() (jetstream.JetStream, error) {
+	natsConn := c.natsConn.Load()
+	if natsConn == nil {
+		return nil, errors.New("no NATS connection")
+	}
+	natsConn.jsOnce.Do(func() {
+		natsConn.js, natsConn.jsErr = jetstream.New(natsConn.Conn)
+	})
+	if natsConn.jsErr != nil {
+		return nil, errors.Trace(natsConn.jsErr)
+	}
+	return natsConn.js, nil
+}
+
+// ensureStream creates the stream if it does not exist.
+// Messages are retained until acknowledged by all consumers, or until the retention limit is reached.
+// Streams that were already ensured by the connection are not recreated.
+func (c *Conn) ensureStream(ctx context.Context, js jetstream.JetStream, name string, subject string, retention jetstream.RetentionPolicy) error {
+	if _, ok := c.streams.Load(name); ok {
+		return nil
+	}
+	_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      name,
+		Subjects:  []string{subject},
+		Retention: retention,
+		Storage:   jetstream.FileStorage,
+		MaxAge:    DurableMaxAge,
+	})
+	if err != nil {
+		return errors.Trace(err, "stream", name)
+	}
+	c.streams.Store(name, true)
+	return nil
+}
+
+// DurablePublish persists the request in the stream, to be delivered at least once to each of the consumers of the stream.
+// The stream is created if it does not exist.
+// The message ID is used to deduplicate messages that are published more than once.
+func (c *Conn) DurablePublish(ctx context.Context, stream string, streamSubject string, subject string, msgID string, httpReq *http.Request) (err error) {
+	js, err := c.jetStream()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = c.ensureStream(ctx, js, stream, streamSubject, jetstream.InterestPolicy)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	sz := 1<<10 + 1 + int(httpReq.ContentLength) // 2KB block minimum
+	block := mem.Alloc(sz)
+	defer mem.Free(block)
+	buf := bytes.NewBuffer(block)
+	err = httpReq.WriteProxy(buf)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = js.PublishMsg(ctx, &nats.Msg{Subject: subject, Data: buf.Bytes()}, jetstream.WithMsgID(msgID))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// DurableSubscribe consumes the messages of the stream.
+// A message whose handler returns an error is redelivered with an exponential backoff.
+// After the maximum number of deliveries, the message is published to the dead-letter subject and is no longer redelivered.
+// The stream and the consumer are created if they do not exist.
+func (c *Conn) DurableSubscribe(ctx context.Context, cfg DurableConfig, handler DurableMsgHandler) (sub *Subscription, err error) {
+	js, err := c.jetStream()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	err = c.ensureStream(ctx, js, cfg.Stream, cfg.Subject, jetstream.InterestPolicy)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cfg.DeadLetterStream != "" {
+		err = c.ensureStream(ctx, js, cfg.DeadLetterStream, cfg.DeadLetterSubject, jetstream.LimitsPolicy)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	maxDeliver := max(cfg.MaxDeliver, 1)
+	consumer, err := js.CreateOrUpdateConsumer(ctx, cfg.Stream, jetstream.ConsumerConfig{
+		Durable:           cfg.Consumer,
+		FilterSubject:     cfg.Subject,
+		AckPolicy:         jetstream.AckExplicitPolicy,
+		AckWait:           cfg.AckWait,
+		MaxDeliver:        maxDeliver + 1, // The last delivery is dead-lettered by the handler
+		InactiveThreshold: cfg.InactiveThreshold,
+	})
+	if err != nil {
+		return nil, errors.Trace(err, "consumer", cfg.Consumer)
+	}
+
+	process := func(m jetstream.Msg) {
+		delivery := 1
+		if md, err := m.Metadata(); err == nil {
+			delivery = int(md.NumDelivered)
+		}
+		handlerErr := handler(&Msg{Subject: m.Subject(), Data: m.Data(), Delivery: delivery})
+		if handlerErr == nil {
+			_ = m.Ack()
+			return
+		}
+		if delivery < maxDeliver {
+			// Back off exponentially, starting at 1 second
+			backoff := min(time.Second<<min(delivery-1, 16), time.Minute)
+			_ = m.NakWithDelay(backoff)
+			return
+		}
+		if cfg.DeadLetterSubject != "" {
+			dead := nats.NewMsg(cfg.DeadLetterSubject)
+			dead.Data = m.Data()
+			dead.Header.Set(HeaderDeadLetterError, handlerErr.Error())
+			dead.Header.Set(HeaderDeadLetterDeliveries, strconv.Itoa(delivery))
+			dead.Header.Set(HeaderDeadLetterConsumer, cfg.Consumer)
+			pubCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			_, err := js.PublishMsg(pubCtx, dead)
+			cancel()
+			if err != nil {
+				// Retry later rather than lose the message
+				_ = m.NakWithDelay(time.Minute)
+				return
+			}
+		}
+		_ = m.Term()
+	}
+	// Messages are processed concurrently up to the limit, beyond which the consumer blocks
+	maxConcurrency := cfg.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = DurableMaxConcurrency
+	}
+	slots := make(chan struct{}, maxConcurrency)
+	consumeCtx, err := consumer.Consume(func(m jetstream.Msg) {
+		slots <- struct{}{}
+		go func() {
+			defer func() { <-slots }()
+			process(m)
+		}()
+	}, jetstream.PullMaxMessages(maxConcurrency))
+	if err != nil {
+		return nil, errors.Trace(err, "consumer", cfg.Consumer)
+	}
+
+	sub = &Subscription{
+		conn:       c,
+		consumeCtx: consumeCtx,
+	}
+	c.mux.Lock()
+	if c.head == nil {
+		c.head = sub
+	} else {
+		c.head.prev = sub
+		sub.next = c.head
+		c.head = sub
+	}
+	c.mux.Unlock()
+	return sub, nil
+}
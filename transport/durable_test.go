@@ -0,0 +1,241 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/fabric/httpx"
+	"github.com/microbus-io/testarossa"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// startJetStream starts an embedded NATS server with JetStream enabled and returns its URL.
+func startJetStream(t *testing.T) string {
+	t.Helper()
+	srv, err := natsserver.NewServer(&natsserver.Options{
+		Host:      "127.0.0.1",
+		Port:      -1, // random
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("nats new server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+	return srv.ClientURL()
+}
+
+func TestTransport_Durable(t *testing.T) {
+	// No t.Parallel because of env.Push
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	env.Push("MICROBUS_NATS", startJetStream(t))
+	defer env.Pop("MICROBUS_NATS")
+	var c Conn
+	err := c.Open(ctx, "durable.transport", nil)
+	assert.NoError(err)
+	defer c.Close()
+	assert.True(c.DurableEnabled())
+
+	var mux sync.Mutex
+	deliveries := map[string][]int{}
+	handler := func(msg *Msg) error {
+		mux.Lock()
+		defer mux.Unlock()
+		var body string
+		if i := strings.Index(string(msg.Data), "\r\n\r\n"); i >= 0 {
+			body = string(msg.Data[i+4:])
+		}
+		deliveries[body] = append(deliveries[body], msg.Delivery)
+		if body == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	}
+	cfg := DurableConfig{
+		Stream:            "durable_transport",
+		Subject:           "durable.transport.>",
+		Consumer:          "durable_transport_consumer",
+		MaxDeliver:        2,
+		AckWait:           5 * time.Second,
+		DeadLetterStream:  "durable_transport_deadletter",
+		DeadLetterSubject: "deadletter.durable_transport_consumer",
+	}
+	sub, err := c.DurableSubscribe(ctx, cfg, handler)
+	assert.NoError(err)
+	defer sub.Unsubscribe()
+
+	// Watch the dead-letter subject
+	natsConn, err := nats.Connect(env.Get("MICROBUS_NATS"))
+	assert.NoError(err)
+	defer natsConn.Close()
+	deadLetters := make(chan *nats.Msg, 4)
+	deadSub, err := natsConn.ChanSubscribe(cfg.DeadLetterSubject, deadLetters)
+	assert.NoError(err)
+	defer deadSub.Unsubscribe()
+
+	publish := func(body string, msgID string) {
+		httpReq, err := http.NewRequest("POST", "https://durable.transport/event", httpx.NewBodyReader([]byte(body)))
+		assert.NoError(err)
+		httpReq.ContentLength = int64(len(body))
+		err = c.DurablePublish(ctx, cfg.Stream, cfg.Subject, "durable.transport.event", msgID, httpReq)
+		assert.NoError(err)
+	}
+
+	// Successfully processed messages are delivered once, even if published twice
+	publish("ok", "msg1")
+	publish("ok", "msg1")
+	// Failed messages are redelivered, then dead-lettered
+	publish("fail", "msg2")
+
+	select {
+	case dead := <-deadLetters:
+		assert.Equal("2", dead.Header.Get(HeaderDeadLetterDeliveries))
+		assert.Equal(cfg.Consumer, dead.Header.Get(HeaderDeadLetterConsumer))
+		assert.Contains(dead.Header.Get(HeaderDeadLetterError), "failed")
+		assert.Contains(string(dead.Data), "fail")
+	case <-time.After(10 * time.Second):
+		assert.True(false, "message not dead-lettered")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	mux.Lock()
+	assert.Equal([]int{1}, deliveries["ok"])
+	assert.Equal([]int{1, 2}, deliveries["fail"])
+	mux.Unlock()
+
+	// Messages published while no subscriber is consuming are delivered once it resumes
+	err = sub.Unsubscribe()
+	assert.NoError(err)
+	publish("later", "msg3")
+	sub, err = c.DurableSubscribe(context.Background(), cfg, handler)
+	assert.NoError(err)
+	assert.True(waitFor(func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(deliveries["later"]) == 1
+	}))
+}
+
+func TestTransport_DurableConcurrency(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	var c Conn
+	c.SetNATS(startJetStream(t))
+	err := c.Open(ctx, "durable.concurrency.transport", nil)
+	assert.NoError(err)
+	defer c.Close()
+
+	// Messages are processed concurrently up to the limit
+	var mux sync.Mutex
+	processing, peak, processed := 0, 0, 0
+	handler := func(msg *Msg) error {
+		mux.Lock()
+		processing++
+		peak = max(peak, processing)
+		mux.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mux.Lock()
+		processing--
+		processed++
+		mux.Unlock()
+		return nil
+	}
+	cfg := DurableConfig{
+		Stream:         "durable_concurrency_transport",
+		Subject:        "durable.concurrency.transport.>",
+		Consumer:       "durable_concurrency_transport_consumer",
+		AckWait:        5 * time.Second,
+		MaxConcurrency: 2,
+	}
+	sub, err := c.DurableSubscribe(ctx, cfg, handler)
+	assert.NoError(err)
+	defer sub.Unsubscribe()
+	for i := range 8 {
+		httpReq, err := http.NewRequest("POST", "https://durable.concurrency.transport/event", nil)
+		assert.NoError(err)
+		err = c.DurablePublish(ctx, cfg.Stream, cfg.Subject, "durable.concurrency.transport.event", strconv.Itoa(i), httpReq)
+		assert.NoError(err)
+	}
+	assert.True(waitFor(func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return processed == 8
+	}))
+	mux.Lock()
+	assert.Equal(2, peak)
+	mux.Unlock()
+}
+
+func TestTransport_DurableWithoutJetStream(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	assert := testarossa.For(t)
+
+	srv, err := natsserver.NewServer(&natsserver.Options{
+		Host:   "127.0.0.1",
+		Port:   -1, // random
+		NoLog:  true,
+		NoSigs: true,
+	})
+	assert.NoError(err)
+	go srv.Start()
+	assert.True(srv.ReadyForConnections(5 * time.Second))
+	defer srv.Shutdown()
+
+	// Durable messaging is not available without JetStream
+	var c Conn
+	c.SetNATS(srv.ClientURL())
+	err = c.Open(ctx, "durable.without.jetstream.transport", nil)
+	assert.NoError(err)
+	defer c.Close()
+	assert.False(c.DurableEnabled())
+	assert.False(c.DurableEnabled())
+}
+
+// waitFor polls the condition for up to 10 seconds.
+func waitFor(cond func() bool) bool {
+	for range 200 {
+		if cond() {
+			return true
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,684 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microbus-io/errors"
+	"github.com/microbus-io/fabric/utils"
+)
+
+/*
+The mesh is a peer-to-peer alternative to NATS for small deployments and CI.
+Each process runs a single mesh node that listens on a TCP port and links to every other node of the mesh.
+Peers are listed statically, discovered via UDP multicast announcements, or learned from linked peers.
+
+Nodes exchange their subscriptions over the links, so that each node maintains a trie of
+the subscriptions of the entire mesh. The subscriptions of remote nodes are represented in the trie
+by handlers that forward messages over the link. The publisher therefore selects the recipients,
+including a single member of each queue group, just as the short-circuit does locally.
+
+The mesh is unauthenticated and unencrypted and should only be used on trusted networks.
+*/
+
+const (
+	meshProtocolVersion = "1"
+	meshMaxFrameSize    = 4 << 20
+	meshDialInterval    = time.Second
+	meshHandshakeWait   = 5 * time.Second
+	meshWriteWait       = 30 * time.Second
+	meshMaxQueueSize    = 64 << 20 // Bytes queued to a peer beyond which it is disconnected
+	meshDefaultLatency  = 100 * time.Millisecond
+	meshAnnouncePrefix  = "microbus-mesh"
+)
+
+// Frame types of the mesh protocol.
+const (
+	meshFrameHello byte = 'H' // version, node ID, listening port
+	meshFramePeers byte = 'P' // addresses of peers
+	meshFrameSub   byte = 'S' // subscription ID, subject, queue
+	meshFrameUnsub byte = 'U' // subscription ID
+	meshFrameMsg   byte = 'M' // subscription ID, subject, data
+)
+
+var (
+	meshPool     = map[string]*meshNode{}
+	meshPoolLock sync.Mutex
+)
+
+// meshLocalSub is a subscription of the local node.
+type meshLocalSub struct {
+	subject string
+	queue   string
+	handler MsgHandler
+	unsub   func()
+}
+
+// meshNode is the process-wide node of the mesh.
+type meshNode struct {
+	id       string
+	cacheKey string
+	refCount int // Guarded by meshPoolLock
+	port     string
+	listener net.Listener
+	udpIn    *net.UDPConn
+	udpOut   *net.UDPConn
+	logger   Logger
+	ctx      context.Context
+	trie     trie
+
+	mux       sync.Mutex
+	localSubs map[uint64]*meshLocalSub
+	lastSID   uint64
+	links     map[string]*meshLink // By remote node ID
+	addrs     map[string]string    // Known addresses of peers, mapped to their node ID if known
+	static    map[string]bool      // Addresses of peers listed statically, retried indefinitely
+	selfAddrs map[string]bool      // Addresses that turned out to point to this node
+	dialing   map[string]bool
+	closed    bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// meshLink is a TCP connection to a peer node.
+type meshLink struct {
+	node       *meshNode
+	conn       net.Conn
+	remoteID   string
+	addr       string            // Address on which the peer is listening
+	dialer     string            // ID of the node that dialed the connection
+	remoteSubs map[uint64]func() // Unsub functions of the subscriptions of the peer, guarded by the node's mux
+
+	outMux    sync.Mutex
+	outQueue  [][]byte
+	outSize   int // Total size of the queued frames
+	outSignal chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// openMesh returns the mesh node of the process for the configuration, starting it if necessary.
+// listen is the TCP address to listen on, peers is a comma-separated list of addresses of peers,
+// and multicast is the UDP address of the multicast group in which to discover peers.
+func openMesh(ctx context.Context, listen string, peers string, multicast string, logger Logger) (*meshNode, error) {
+	cacheKey := listen + "|" + peers + "|" + multicast
+	meshPoolLock.Lock()
+	defer meshPoolLock.Unlock()
+	if n := meshPool[cacheKey]; n != nil {
+		n.refCount++
+		return n, nil
+	}
+
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, errors.Trace(err, "listen", listen)
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	n := &meshNode{
+		id:        utils.RandomIdentifier(16),
+		cacheKey:  cacheKey,
+		refCount:  1,
+		port:      port,
+		listener:  listener,
+		logger:    logger,
+		ctx:       ctx,
+		localSubs: map[uint64]*meshLocalSub{},
+		links:     map[string]*meshLink{},
+		addrs:     map[string]string{},
+		static:    map[string]bool{},
+		selfAddrs: map[string]bool{},
+		dialing:   map[string]bool{},
+		done:      make(chan struct{}),
+	}
+	for peer := range strings.SplitSeq(peers, ",") {
+		peer = strings.TrimSpace(peer)
+		if peer != "" {
+			n.addrs[peer] = ""
+			n.static[peer] = true
+		}
+	}
+	if multicast != "" {
+		group, err := net.ResolveUDPAddr("udp", multicast)
+		if err != nil {
+			listener.Close()
+			return nil, errors.Trace(err, "multicast", multicast)
+		}
+		n.udpIn, err = net.ListenMulticastUDP("udp", nil, group)
+		if err != nil {
+			listener.Close()
+			return nil, errors.Trace(err, "multicast", multicast)
+		}
+		n.udpOut, err = net.DialUDP("udp", nil, group)
+		if err != nil {
+			listener.Close()
+			n.udpIn.Close()
+			return nil, errors.Trace(err, "multicast", multicast)
+		}
+		n.wg.Add(2)
+		go n.announceLoop()
+		go n.discoverLoop()
+	}
+	n.wg.Add(2)
+	go n.acceptLoop()
+	go n.dialLoop()
+
+	if logger != nil {
+		logger.LogInfo(ctx, "Listening on mesh",
+			"addr", listener.Addr().String(),
+			"node", n.id,
+		)
+	}
+	meshPool[cacheKey] = n
+	return n, nil
+}
+
+// release decrements the reference count of the node and closes it when it is no longer referenced.
+func (n *meshNode) release() {
+	meshPoolLock.Lock()
+	n.refCount--
+	lastRef := n.refCount == 0
+	if lastRef {
+		delete(meshPool, n.cacheKey)
+	}
+	meshPoolLock.Unlock()
+	if !lastRef {
+		return
+	}
+
+	n.mux.Lock()
+	n.closed = true
+	links := make([]*meshLink, 0, len(n.links))
+	for _, link := range n.links {
+		links = append(links, link)
+	}
+	n.mux.Unlock()
+	close(n.done)
+	n.listener.Close()
+	if n.udpIn != nil {
+		n.udpIn.Close()
+		n.udpOut.Close()
+	}
+	for _, link := range links {
+		link.conn.Close()
+	}
+	n.wg.Wait()
+}
+
+// subscribe registers a local subscription and advertises it to the peers.
+func (n *meshNode) subscribe(subject string, queue string, handler MsgHandler) (unsub func()) {
+	n.mux.Lock()
+	n.lastSID++
+	sid := n.lastSID
+	n.localSubs[sid] = &meshLocalSub{
+		subject: subject,
+		queue:   queue,
+		handler: handler,
+		unsub:   n.trie.Sub(subject, queue, handler),
+	}
+	sidBytes := []byte(strconv.FormatUint(sid, 10))
+	for _, link := range n.links {
+		link.send(meshFrameSub, sidBytes, []byte(subject), []byte(queue))
+	}
+	n.mux.Unlock()
+
+	return func() {
+		n.mux.Lock()
+		ls := n.localSubs[sid]
+		if ls != nil {
+			delete(n.localSubs, sid)
+			ls.unsub()
+			for _, link := range n.links {
+				link.send(meshFrameUnsub, sidBytes)
+			}
+		}
+		n.mux.Unlock()
+	}
+}
+
+// deliver delivers the data to the local and remote subscribers of the subject.
+// One member of each queue group is selected to receive the message.
+func (n *meshNode) deliver(subject string, data []byte) {
+	for _, h := range n.trie.Handlers(subject) {
+		h(&Msg{Subject: subject, Data: data})
+	}
+}
+
+// hasPeers indicates if the node is linked to any peer.
+func (n *meshNode) hasPeers() bool {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	return len(n.links) > 0
+}
+
+// acceptLoop accepts connections from peers.
+func (n *meshNode) acceptLoop() {
+	defer n.wg.Done()
+	for {
+		conn, err := n.listener.Accept()
+		if err != nil {
+			select {
+			case <-n.done:
+				return
+			default:
+			}
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.serve(conn, "")
+		}()
+	}
+}
+
+// dialLoop periodically dials the known peers that are not linked.
+func (n *meshNode) dialLoop() {
+	defer n.wg.Done()
+	ticker := time.NewTicker(meshDialInterval)
+	defer ticker.Stop()
+	for {
+		n.mux.Lock()
+		var targets []string
+		for addr, id := range n.addrs {
+			if n.selfAddrs[addr] || n.dialing[addr] || (id != "" && n.links[id] != nil) {
+				continue
+			}
+			n.dialing[addr] = true
+			targets = append(targets, addr)
+		}
+		n.mux.Unlock()
+		for _, addr := range targets {
+			n.wg.Add(1)
+			go func() {
+				defer n.wg.Done()
+				conn, err := net.DialTimeout("tcp", addr, meshHandshakeWait)
+				if err != nil {
+					n.mux.Lock()
+					delete(n.dialing, addr)
+					if !n.static[addr] {
+						// Discovered peers are forgotten until rediscovered
+						delete(n.addrs, addr)
+					}
+					n.mux.Unlock()
+					return
+				}
+				n.serve(conn, addr)
+			}()
+		}
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serve performs the handshake with the peer on the other end of the connection, then processes its frames
+// until the connection is closed. dialedAddr is the address of the peer if the connection was dialed by this node.
+func (n *meshNode) serve(conn net.Conn, dialedAddr string) {
+	link, r, err := n.handshake(conn, dialedAddr)
+	if dialedAddr != "" {
+		n.mux.Lock()
+		delete(n.dialing, dialedAddr)
+		n.mux.Unlock()
+	}
+	if err != nil || link == nil {
+		conn.Close()
+		return
+	}
+	go link.writeLoop()
+	link.readLoop(r)
+	n.unregister(link)
+}
+
+// handshake exchanges hello frames with the peer and registers the link.
+// A nil link is returned if the connection is redundant.
+func (n *meshNode) handshake(conn net.Conn, dialedAddr string) (link *meshLink, r *bufio.Reader, err error) {
+	conn.SetDeadline(time.Now().Add(meshHandshakeWait))
+	_, err = conn.Write(encodeMeshFrame(meshFrameHello, []byte(meshProtocolVersion), []byte(n.id), []byte(n.port)))
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	r = bufio.NewReaderSize(conn, 64<<10)
+	typ, fields, err := readMeshFrame(r)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if typ != meshFrameHello || len(fields) != 3 || string(fields[0]) != meshProtocolVersion {
+		return nil, nil, errors.New("invalid mesh handshake")
+	}
+	conn.SetDeadline(time.Time{})
+	remoteID := string(fields[1])
+	if remoteID == n.id {
+		if dialedAddr != "" {
+			n.mux.Lock()
+			n.selfAddrs[dialedAddr] = true
+			n.mux.Unlock()
+		}
+		return nil, nil, nil
+	}
+	host, _, _ := net.SplitHostPort(conn.RemoteAddr().String())
+	link = &meshLink{
+		node:      n,
+		conn:      conn,
+		remoteID:  remoteID,
+		addr:      net.JoinHostPort(host, string(fields[2])),
+		dialer:    remoteID,
+		outSignal: make(chan struct{}, 1),
+		closed:    make(chan struct{}),
+	}
+	if dialedAddr != "" {
+		link.dialer = n.id
+	}
+	if !n.register(link, dialedAddr) {
+		return nil, nil, nil
+	}
+	return link, r, nil
+}
+
+// register adds the link to the node, sends it the subscriptions of the node and shares the addresses of the known peers.
+// If the node is already linked to the peer, the link dialed by the node with the lesser ID is retained,
+// so that both ends of a redundant pair of links agree on the link to retain.
+func (n *meshNode) register(link *meshLink, dialedAddr string) bool {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if n.closed {
+		return false
+	}
+	if dialedAddr != "" {
+		n.addrs[dialedAddr] = link.remoteID
+	}
+	if existing := n.links[link.remoteID]; existing != nil {
+		if existing.dialer <= link.dialer {
+			return false
+		}
+		n.dropLink(existing)
+		existing.conn.Close()
+	}
+	n.links[link.remoteID] = link
+	link.remoteSubs = map[uint64]func(){}
+
+	for sid, ls := range n.localSubs {
+		link.send(meshFrameSub, []byte(strconv.FormatUint(sid, 10)), []byte(ls.subject), []byte(ls.queue))
+	}
+	var peerAddrs [][]byte
+	for _, other := range n.links {
+		if other != link {
+			peerAddrs = append(peerAddrs, []byte(other.addr))
+			other.send(meshFramePeers, []byte(link.addr))
+		}
+	}
+	if len(peerAddrs) > 0 {
+		link.send(meshFramePeers, peerAddrs...)
+	}
+	if n.logger != nil {
+		n.logger.LogInfo(n.ctx, "Linked to mesh peer",
+			"addr", link.addr,
+			"node", link.remoteID,
+		)
+	}
+	return true
+}
+
+// unregister removes the link from the node, along with the subscriptions of the peer.
+func (n *meshNode) unregister(link *meshLink) {
+	n.mux.Lock()
+	if n.links[link.remoteID] == link && n.logger != nil && !n.closed {
+		n.logger.LogInfo(n.ctx, "Unlinked from mesh peer",
+			"addr", link.addr,
+			"node", link.remoteID,
+		)
+	}
+	n.dropLink(link)
+	n.mux.Unlock()
+	link.conn.Close()
+	link.closeOnce.Do(func() {
+		close(link.closed)
+	})
+}
+
+// dropLink removes the link and the subscriptions of the peer.
+// This method must be called under a lock.
+func (n *meshNode) dropLink(link *meshLink) {
+	if n.links[link.remoteID] == link {
+		delete(n.links, link.remoteID)
+	}
+	for _, unsub := range link.remoteSubs {
+		unsub()
+	}
+	link.remoteSubs = nil
+}
+
+// send queues a frame to be written to the peer.
+// It does not block, so that it can be called while processing an incoming frame.
+// A peer that falls too far behind is disconnected rather than have frames dropped,
+// because a dropped subscription frame would leave the tries of the nodes inconsistent.
+// The peer's subscriptions are restored when the link is redialed.
+func (l *meshLink) send(typ byte, fields ...[]byte) {
+	frame := encodeMeshFrame(typ, fields...)
+	l.outMux.Lock()
+	if l.outSize+len(frame) > meshMaxQueueSize {
+		l.outQueue = nil
+		l.outSize = 0
+		l.outMux.Unlock()
+		if l.node.logger != nil {
+			l.node.logger.LogError(l.node.ctx, "Disconnecting slow mesh peer",
+				"addr", l.addr,
+				"node", l.remoteID,
+			)
+		}
+		// The read loop unregisters the link
+		l.conn.Close()
+		return
+	}
+	l.outQueue = append(l.outQueue, frame)
+	l.outSize += len(frame)
+	l.outMux.Unlock()
+	select {
+	case l.outSignal <- struct{}{}:
+	default:
+	}
+}
+
+// writeLoop writes the queued frames to the peer.
+func (l *meshLink) writeLoop() {
+	w := bufio.NewWriterSize(l.conn, 64<<10)
+	for {
+		select {
+		case <-l.outSignal:
+		case <-l.closed:
+			return
+		}
+		l.outMux.Lock()
+		frames := l.outQueue
+		l.outQueue = nil
+		l.outSize = 0
+		l.outMux.Unlock()
+		l.conn.SetWriteDeadline(time.Now().Add(meshWriteWait))
+		for _, frame := range frames {
+			w.Write(frame)
+		}
+		err := w.Flush()
+		if err != nil {
+			// The read loop unregisters the link
+			l.conn.Close()
+			return
+		}
+	}
+}
+
+// readLoop processes the frames received from the peer until the connection is closed.
+func (l *meshLink) readLoop(r *bufio.Reader) {
+	n := l.node
+	for {
+		typ, fields, err := readMeshFrame(r)
+		if err != nil {
+			return
+		}
+		switch typ {
+		case meshFrameMsg:
+			if len(fields) != 3 {
+				return
+			}
+			sid, _ := strconv.ParseUint(string(fields[0]), 10, 64)
+			n.mux.Lock()
+			ls := n.localSubs[sid]
+			n.mux.Unlock()
+			if ls != nil {
+				ls.handler(&Msg{Subject: string(fields[1]), Data: fields[2]})
+			}
+		case meshFrameSub:
+			if len(fields) != 3 {
+				return
+			}
+			sid, _ := strconv.ParseUint(string(fields[0]), 10, 64)
+			sidBytes := fields[0]
+			forward := func(msg *Msg) {
+				l.send(meshFrameMsg, sidBytes, []byte(msg.Subject), msg.Data)
+			}
+			n.mux.Lock()
+			if l.remoteSubs != nil {
+				if unsub := l.remoteSubs[sid]; unsub != nil {
+					unsub()
+				}
+				l.remoteSubs[sid] = n.trie.Sub(string(fields[1]), string(fields[2]), forward)
+			}
+			n.mux.Unlock()
+		case meshFrameUnsub:
+			if len(fields) != 1 {
+				return
+			}
+			sid, _ := strconv.ParseUint(string(fields[0]), 10, 64)
+			n.mux.Lock()
+			if unsub := l.remoteSubs[sid]; unsub != nil {
+				unsub()
+				delete(l.remoteSubs, sid)
+			}
+			n.mux.Unlock()
+		case meshFramePeers:
+			n.mux.Lock()
+			for _, addr := range fields {
+				if _, ok := n.addrs[string(addr)]; !ok {
+					n.addrs[string(addr)] = ""
+				}
+			}
+			n.mux.Unlock()
+		}
+	}
+}
+
+// announceLoop periodically announces the node to the multicast group.
+func (n *meshNode) announceLoop() {
+	defer n.wg.Done()
+	announcement := []byte(meshAnnouncePrefix + " " + n.id + " " + n.port)
+	ticker := time.NewTicker(meshDialInterval)
+	defer ticker.Stop()
+	for {
+		n.udpOut.Write(announcement)
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// discoverLoop learns the addresses of the peers that announce themselves to the multicast group.
+func (n *meshNode) discoverLoop() {
+	defer n.wg.Done()
+	buf := make([]byte, 256)
+	for {
+		sz, src, err := n.udpIn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-n.done:
+				return
+			default:
+			}
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		parts := strings.Split(string(buf[:sz]), " ")
+		if len(parts) != 3 || parts[0] != meshAnnouncePrefix || parts[1] == n.id {
+			continue
+		}
+		addr := net.JoinHostPort(src.IP.String(), parts[2])
+		n.mux.Lock()
+		if _, ok := n.addrs[addr]; !ok {
+			n.addrs[addr] = parts[1]
+		}
+		n.mux.Unlock()
+	}
+}
+
+// encodeMeshFrame encodes a frame of the mesh protocol.
+// A frame is made of a 4-byte length, a 1-byte type and any number of length-prefixed fields.
+func encodeMeshFrame(typ byte, fields ...[]byte) []byte {
+	sz := 5
+	for _, f := range fields {
+		sz += binary.MaxVarintLen64 + len(f)
+	}
+	frame := make([]byte, 5, sz)
+	frame[4] = typ
+	for _, f := range fields {
+		frame = binary.AppendUvarint(frame, uint64(len(f)))
+		frame = append(frame, f...)
+	}
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+	return frame
+}
+
+// readMeshFrame reads and decodes a frame of the mesh protocol.
+// The fields reference a newly allocated buffer.
+func readMeshFrame(r *bufio.Reader) (typ byte, fields [][]byte, err error) {
+	var header [4]byte
+	_, err = io.ReadFull(r, header[:])
+	if err != nil {
+		return 0, nil, err // No trace
+	}
+	sz := binary.BigEndian.Uint32(header[:])
+	if sz == 0 || sz > meshMaxFrameSize {
+		return 0, nil, errors.New("invalid mesh frame size", "size", sz)
+	}
+	frame := make([]byte, sz)
+	_, err = io.ReadFull(r, frame)
+	if err != nil {
+		return 0, nil, err // No trace
+	}
+	typ = frame[0]
+	frame = frame[1:]
+	for len(frame) > 0 {
+		fl, n := binary.Uvarint(frame)
+		if n <= 0 || uint64(len(frame)-n) < fl {
+			return 0, nil, errors.New("malformed mesh frame")
+		}
+		fields = append(fields, frame[n:n+int(fl)])
+		frame = frame[n+int(fl):]
+	}
+	return typ, fields, nil
+}
@@ -0,0 +1,290 @@
+/*
+Copyright (c) 2023-2026 Microbus LLC and various contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/microbus-io/fabric/env"
+	"github.com/microbus-io/testarossa"
+)
+
+// linkCount returns the number of peers the node is linked to.
+func (n *meshNode) linkCount() int {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	return len(n.links)
+}
+
+// remoteSubCount returns the number of subscriptions of the peers of the node.
+func (n *meshNode) remoteSubCount() int {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	count := 0
+	for _, link := range n.links {
+		count += len(link.remoteSubs)
+	}
+	return count
+}
+
+// eventually polls the condition for up to 5 seconds.
+func eventually(cond func() bool) bool {
+	for range 500 {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestTransport_MeshFrames(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	var buf bytes.Buffer
+	buf.Write(encodeMeshFrame(meshFrameMsg, []byte("123"), []byte("sub.ject"), bytes.Repeat([]byte("x"), 300)))
+	buf.Write(encodeMeshFrame(meshFrameUnsub, []byte("123")))
+	buf.Write(encodeMeshFrame(meshFramePeers))
+	buf.Write(encodeMeshFrame(meshFrameSub, []byte("1"), []byte("sub.ject"), []byte{}))
+
+	r := bufio.NewReader(&buf)
+	typ, fields, err := readMeshFrame(r)
+	if assert.NoError(err) {
+		assert.Equal(meshFrameMsg, typ)
+		assert.Len(fields, 3)
+		assert.Equal("sub.ject", string(fields[1]))
+		assert.Len(fields[2], 300)
+	}
+	typ, fields, err = readMeshFrame(r)
+	if assert.NoError(err) {
+		assert.Equal(meshFrameUnsub, typ)
+		assert.Equal([][]byte{[]byte("123")}, fields)
+	}
+	typ, fields, err = readMeshFrame(r)
+	if assert.NoError(err) {
+		assert.Equal(meshFramePeers, typ)
+		assert.Len(fields, 0)
+	}
+	typ, fields, err = readMeshFrame(r)
+	if assert.NoError(err) {
+		assert.Equal(meshFrameSub, typ)
+		assert.Len(fields, 3)
+		assert.Len(fields[2], 0)
+	}
+	_, _, err = readMeshFrame(r)
+	assert.Error(err)
+
+	// Malformed frames
+	_, _, err = readMeshFrame(bufio.NewReader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 'M'})))
+	assert.Error(err)
+	_, _, err = readMeshFrame(bufio.NewReader(bytes.NewReader([]byte{0, 0, 0, 3, 'M', 10, 'x'})))
+	assert.Error(err)
+}
+
+func TestTransport_Mesh(t *testing.T) {
+	// No t.Parallel because of env.Push
+	ctx := context.Background()
+	assert := testarossa.For(t)
+
+	env.Push("MICROBUS_SHORT_CIRCUIT", "0")
+	defer env.Pop("MICROBUS_SHORT_CIRCUIT")
+	env.Push("MICROBUS_MESH", "127.0.0.1:0")
+	defer env.Pop("MICROBUS_MESH")
+
+	// Node 1 lists no peers
+	var c1 Conn
+	err := c1.Open(ctx, "", nil)
+	assert.NoError(err)
+	defer c1.Close()
+	n1 := c1.meshNode.Load()
+	assert.NotNil(n1)
+	addr1 := n1.listener.Addr().String()
+	assert.Equal(100*time.Millisecond, c1.Latency())
+
+	// Nodes 2 and 3 list only node 1 and learn of each other from it
+	env.Push("MICROBUS_MESH_PEERS", addr1+","+addr1)
+	defer env.Pop("MICROBUS_MESH_PEERS")
+	var c2 Conn
+	err = c2.Open(ctx, "", nil)
+	assert.NoError(err)
+	defer c2.Close()
+	n2 := c2.meshNode.Load()
+	assert.True(n1 != n2)
+	env.Push("MICROBUS_MESH_PEERS", addr1)
+	defer env.Pop("MICROBUS_MESH_PEERS")
+	var c3 Conn
+	err = c3.Open(ctx, "", nil)
+	assert.NoError(err)
+	defer c3.Close()
+	n3 := c3.meshNode.Load()
+
+	assert.True(eventually(func() bool {
+		return n1.linkCount() == 2 && n2.linkCount() == 2 && n3.linkCount() == 2
+	}))
+
+	// Queue subscribers on different nodes share the requests
+	var count1, count2, countAll1, countAll3 atomic.Int32
+	qs1, err := c1.QueueSubscribe("mesh.queue", "q", func(msg *Msg) { count1.Add(1) })
+	assert.NoError(err)
+	_, err = c2.QueueSubscribe("mesh.queue", "q", func(msg *Msg) { count2.Add(1) })
+	assert.NoError(err)
+	_, err = c1.Subscribe("mesh.all.*", func(msg *Msg) { countAll1.Add(1) })
+	assert.NoError(err)
+	_, err = c3.Subscribe("mesh.all.>", func(msg *Msg) {
+		if msg.Subject == "mesh.all.x" && bytes.Contains(msg.Data, []byte("/all")) {
+			countAll3.Add(1)
+		}
+	})
+	assert.NoError(err)
+	assert.True(eventually(func() bool {
+		return n1.remoteSubCount() == 2 && n2.remoteSubCount() == 3 && n3.remoteSubCount() == 3
+	}))
+
+	for range 20 {
+		httpReq, _ := http.NewRequest("GET", "https://mesh/queue", nil)
+		err = c3.Request("mesh.queue", httpReq)
+		assert.NoError(err)
+	}
+	assert.True(eventually(func() bool {
+		return count1.Load()+count2.Load() == 20
+	}))
+	assert.True(count1.Load() > 0)
+	assert.True(count2.Load() > 0)
+
+	// Multicast reaches all subscribers, including local ones
+	httpReq, _ := http.NewRequest("GET", "https://mesh/all", nil)
+	err = c1.Publish("mesh.all.x", httpReq)
+	assert.NoError(err)
+	assert.True(eventually(func() bool {
+		return countAll1.Load() == 1 && countAll3.Load() == 1
+	}))
+
+	// Unsubscribing is propagated to the peers
+	err = qs1.Unsubscribe()
+	assert.NoError(err)
+	assert.True(eventually(func() bool {
+		return n2.remoteSubCount() == 2 && n3.remoteSubCount() == 2
+	}))
+	count2.Store(0)
+	for range 5 {
+		httpReq, _ := http.NewRequest("GET", "https://mesh/queue", nil)
+		err = c3.Request("mesh.queue", httpReq)
+		assert.NoError(err)
+	}
+	assert.True(eventually(func() bool {
+		return count2.Load() == 5
+	}))
+
+	// The subscriptions of a departed node are removed from its peers
+	c2.Close()
+	assert.True(eventually(func() bool {
+		return n1.linkCount() == 1 && n3.linkCount() == 1 && len(n3.trie.Handlers("mesh.queue")) == 0
+	}))
+}
+
+func TestTransport_MeshLatency(t *testing.T) {
+	// No t.Parallel because of env.Push
+	ctx := context.Background()
+	assert := testarossa.For(t)
+
+	env.Push("MICROBUS_MESH", "127.0.0.1:0")
+	defer env.Pop("MICROBUS_MESH")
+	env.Push("MICROBUS_MESH_LATENCY", "250ms")
+	defer env.Pop("MICROBUS_MESH_LATENCY")
+
+	var c1 Conn
+	err := c1.Open(ctx, "", nil)
+	assert.NoError(err)
+	defer c1.Close()
+	assert.Equal(250*time.Millisecond, c1.Latency())
+
+	env.Push("MICROBUS_MESH_LATENCY", "fast")
+	defer env.Pop("MICROBUS_MESH_LATENCY")
+	var c2 Conn
+	err = c2.Open(ctx, "", nil)
+	assert.Error(err)
+}
+
+func TestTransport_MeshSlowPeer(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	local, remote := net.Pipe()
+	defer remote.Close()
+	l := &meshLink{
+		node:      &meshNode{},
+		conn:      local,
+		outSignal: make(chan struct{}, 1),
+		closed:    make(chan struct{}),
+	}
+
+	// The peer does not read, so the frames pile up in the queue
+	data := make([]byte, 1<<20)
+	for range meshMaxQueueSize/len(data) - 1 {
+		l.send(meshFrameMsg, []byte("1"), []byte("slow.peer"), data)
+	}
+	assert.True(l.outSize > meshMaxQueueSize-2*len(data))
+
+	// The peer is disconnected when the queue overflows
+	l.send(meshFrameMsg, []byte("1"), []byte("slow.peer"), data)
+	assert.Zero(l.outSize)
+	_, err := remote.Read(make([]byte, 1))
+	assert.Error(err)
+}
+
+func TestTransport_MeshMulticast(t *testing.T) {
+	t.Parallel()
+	assert := testarossa.For(t)
+
+	group := fmt.Sprintf("239.255.77.77:%d", 20000+rand.IntN(20000))
+	n1, err := openMesh(t.Context(), "0.0.0.0:0", "", group, nil)
+	if err != nil {
+		t.Skip("multicast not available", err)
+	}
+	defer n1.release()
+	n2, err := openMesh(t.Context(), ":0", "", group, nil)
+	if !assert.NoError(err) {
+		return
+	}
+	defer n2.release()
+	if !eventually(func() bool {
+		return n1.linkCount() == 1 && n2.linkCount() == 1
+	}) {
+		t.Skip("multicast not routed")
+	}
+
+	var count atomic.Int32
+	unsub := n2.subscribe("mesh.multicast", "", func(msg *Msg) { count.Add(1) })
+	defer unsub()
+	assert.True(eventually(func() bool {
+		return len(n1.trie.Handlers("mesh.multicast")) == 1
+	}))
+	n1.deliver("mesh.multicast", []byte("hello"))
+	assert.True(eventually(func() bool {
+		return count.Load() == 1
+	}))
+}
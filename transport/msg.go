@@ -28,4 +28,7 @@ type Msg struct {
 	Data     []byte
 	Request  *http.Request
 	Response *http.Response
+	// Delivery is the number of the delivery attempt of a durable message, starting at 1.
+	// It is zero for messages that are not durable.
+	Delivery int
 }
@@ -19,6 +19,7 @@ package transport
 import (
 	"github.com/microbus-io/errors"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 // Subscription is an expression of interest in a subject.
@@ -28,6 +29,8 @@ type Subscription struct {
 	prev              *Subscription
 	shortCircuitUnsub func()
 	natsSub           *nats.Subscription
+	meshUnsub         func()
+	consumeCtx        jetstream.ConsumeContext
 	done              bool
 }
 
@@ -140,6 +140,22 @@ func (s Span) log(severity string, msg string, args ...any) {
 	s.internal.AddEvent("log", trace.WithAttributes(attrs...))
 }
 
+// AddEvent records a named event on the span.
+// The arguments are expected in the standard slog name=value pairs pattern.
+func (s Span) AddEvent(name string, args ...any) {
+	if s.internal == nil {
+		return
+	}
+	var attrs []attribute.KeyValue
+	slogRec := slog.NewRecord(time.Time{}, slog.LevelInfo, name, 0)
+	slogRec.Add(args...)
+	slogRec.Attrs(func(f slog.Attr) bool {
+		attrs = append(attrs, slogToTracingAttrs("", f)...)
+		return true
+	})
+	s.internal.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
 // LogDebug records a debug log event on the span.
 func (s Span) LogDebug(msg string, args ...any) {
 	s.log("debug", msg, args...)